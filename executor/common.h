@@ -333,6 +333,19 @@ static int fault_injected(int fail_fd)
 #endif
 #endif
 
+#if !GOOS_linux
+#if SYZ_EXECUTOR || SYZ_CALL_SCHEDULE
+// Pinning is supported only on linux, other OSes only delay the call.
+static void set_call_schedule(int cpu, int delay)
+{
+#if !GOOS_windows
+	if (delay)
+		usleep(delay);
+#endif
+}
+#endif
+#endif
+
 #if !GOOS_windows
 #if SYZ_EXECUTOR || SYZ_THREADED
 #include <errno.h>
@@ -4768,6 +4768,46 @@ static int fault_injected(int fail_fd)
 }
 #endif
 
+#if SYZ_EXECUTOR || SYZ_CALL_SCHEDULE
+#include <sched.h>
+#include <unistd.h>
+
+static cpu_set_t call_schedule_cpus;
+static __thread int call_schedule_pinned;
+
+// Pins the current thread to the cpu (1-based, 0 means no pinning) and sleeps for delay microseconds.
+// Used to reproduce races that depend on the relative timing of calls.
+// Affinity is not changed for calls without a cpu, except to undo pinning done by a previous call
+// in the same thread, in which case the original affinity of the process is restored.
+static void set_call_schedule(int cpu, int delay)
+{
+	if (cpu || call_schedule_pinned) {
+		if (CPU_COUNT(&call_schedule_cpus) == 0 &&
+		    sched_getaffinity(0, sizeof(call_schedule_cpus), &call_schedule_cpus))
+			CPU_ZERO(&call_schedule_cpus);
+		cpu_set_t set = call_schedule_cpus;
+		int ncpu = CPU_COUNT(&call_schedule_cpus);
+		if (cpu && ncpu) {
+			// Pin to the (cpu-1)-th cpu among the cpus the process is allowed to run on.
+			int n = (cpu - 1) % ncpu;
+			CPU_ZERO(&set);
+			for (int i = 0; i < CPU_SETSIZE; i++) {
+				if (CPU_ISSET(i, &call_schedule_cpus) && n-- == 0) {
+					CPU_SET(i, &set);
+					break;
+				}
+			}
+		}
+		// Not critical, the call is just executed with a different schedule.
+		if (ncpu)
+			sched_setaffinity(0, sizeof(set), &set);
+		call_schedule_pinned = cpu != 0;
+	}
+	if (delay)
+		usleep(delay);
+}
+#endif
+
 #if (SYZ_EXECUTOR || SYZ_REPEAT) && SYZ_EXECUTOR_USES_FORK_SERVER
 #include <dirent.h>
 #include <errno.h>
@@ -44,6 +44,26 @@ public:
 		byte |= bit;
 	}
 
+	// Inserts all PCs present in the dense max signal bitmap.
+	// See pkg/signal/bitmap.go for the reference implementation.
+	void InsertBitmap(const rpc::SignalBitmapRawT& bitmap)
+	{
+		constexpr uint64 kChunk = static_cast<uint64>(rpc::Const::SignalBitmapChunk);
+		constexpr size_t kChunkBytes = kChunk / kPCDivider / kByteBits;
+		if (bitmap.bitmap.size() != bitmap.offsets.size() * kChunkBytes)
+			failmsg("bad signal bitmap size", "chunks=%zu size=%zu",
+				bitmap.offsets.size(), bitmap.bitmap.size());
+		for (size_t i = 0; i < bitmap.offsets.size(); i++) {
+			const uint8* chunk = &bitmap.bitmap[i * kChunkBytes];
+			for (size_t b = 0; b < kChunkBytes; b++) {
+				for (size_t bit = 0; bit < kByteBits; bit++) {
+					if (chunk[b] & (1 << bit))
+						Insert(bitmap.offsets[i] + (b * kByteBits + bit) * kPCDivider);
+				}
+			}
+		}
+	}
+
 	bool Contains(uint64 pc)
 	{
 		auto [byte, bit] = FindByte(pc, false);
@@ -289,6 +289,8 @@ static bool all_extra_signal;
 // Tunable timeouts, received with execute_req.
 static uint64 syscall_timeout_ms;
 static uint64 program_timeout_ms;
+// Program timeout received with handshake_req (used if execute_req does not override it).
+static uint64 default_program_timeout_ms;
 static uint64 slowdown_scale;
 
 // Can be used to disginguish whether we're at the initialization stage
@@ -387,6 +389,8 @@ struct thread_t {
 	bool fault_injected;
 	cover_t cov;
 	bool soft_fail_state;
+	uint64 start_ms;
+	uint32 elapsed_ms;
 };
 
 static thread_t threads[kMaxThreads];
@@ -424,6 +428,8 @@ struct execute_req {
 	uint64 exec_flags;
 	uint64 all_call_signal;
 	bool all_extra_signal;
+	// If non-zero, overrides the program timeout from the handshake.
+	uint64 program_timeout_ms;
 };
 
 struct execute_reply {
@@ -774,6 +780,7 @@ void parse_handshake(const handshake_req& req)
 	procid = req.pid;
 	syscall_timeout_ms = req.syscall_timeout_ms;
 	program_timeout_ms = req.program_timeout_ms;
+	default_program_timeout_ms = req.program_timeout_ms;
 	slowdown_scale = req.slowdown_scale;
 	flag_debug = (bool)(req.flags & rpc::ExecEnv::Debug);
 	flag_coverage = (bool)(req.flags & rpc::ExecEnv::Signal);
@@ -816,6 +823,7 @@ void parse_execute(const execute_req& req)
 	flag_threaded = req.exec_flags & (1 << 4);
 	all_call_signal = req.all_call_signal;
 	all_extra_signal = req.all_extra_signal;
+	program_timeout_ms = req.program_timeout_ms ? req.program_timeout_ms : default_program_timeout_ms;
 
 	debug("[%llums] exec opts: reqid=%llu type=%llu procid=%llu threaded=%d cover=%d comps=%d dedup=%d signal=%d "
 	      " sandbox=%d/%d/%d/%d timeouts=%llu/%llu/%llu kernel_64_bit=%d\n",
@@ -1161,6 +1169,8 @@ thread_t* schedule_call(int call_index, int call_num, uint64 copyout_index, uint
 	th->call_props = call_props;
 	for (int i = 0; i < kMaxArgs; i++)
 		th->args[i] = args[i];
+	th->start_ms = current_time_ms();
+	th->elapsed_ms = 0;
 	event_set(&th->ready);
 	running++;
 	return th;
@@ -1318,7 +1328,7 @@ void copyout_call_results(thread_t* th)
 	}
 }
 
-void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bool all_signal)
+void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, uint32 elapsed_ms, bool all_signal)
 {
 	CoverAccessScope scope(cov);
 	auto& fbb = *output_builder;
@@ -1349,6 +1359,7 @@ void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bo
 		flags |= rpc::CallFlag::CoverageOverflow;
 	builder.add_flags(flags);
 	builder.add_error(error);
+	builder.add_elapsed_ms(elapsed_ms);
 	if (signal_off)
 		builder.add_signal(signal_off);
 	if (cover_off)
@@ -1372,16 +1383,19 @@ void write_call_output(thread_t* th, bool finished)
 {
 	uint32 reserrno = ENOSYS;
 	rpc::CallFlag flags = rpc::CallFlag::Executed;
+	// For unfinished calls report how long they were running so far.
+	uint32 elapsed_ms = current_time_ms() - th->start_ms;
 	if (finished && th != last_scheduled)
 		flags |= rpc::CallFlag::Blocked;
 	if (finished) {
+		elapsed_ms = th->elapsed_ms;
 		reserrno = th->res != -1 ? 0 : th->reserrno;
 		flags |= rpc::CallFlag::Finished;
 		if (th->fault_injected)
 			flags |= rpc::CallFlag::FaultInjected;
 	}
 	bool all_signal = th->call_index < 64 ? (all_call_signal & (1ull << th->call_index)) : false;
-	write_output(th->call_index, &th->cov, flags, reserrno, all_signal);
+	write_output(th->call_index, &th->cov, flags, reserrno, elapsed_ms, all_signal);
 }
 
 void write_extra_output()
@@ -1391,7 +1405,7 @@ void write_extra_output()
 	cover_collect(&extra_cov);
 	if (!extra_cov.size)
 		return;
-	write_output(-1, &extra_cov, rpc::CallFlag::NONE, 997, all_extra_signal);
+	write_output(-1, &extra_cov, rpc::CallFlag::NONE, 997, 0, all_extra_signal);
 	cover_reset(&extra_cov);
 }
 
@@ -1493,6 +1507,8 @@ void execute_call(thread_t* th)
 	}
 	debug(")\n");
 
+	set_call_schedule(th->call_props.cpu, th->call_props.delay);
+
 	int fail_fd = -1;
 	th->soft_fail_state = false;
 	if (th->call_props.fail_nth > 0) {
@@ -1510,6 +1526,7 @@ void execute_call(thread_t* th)
 	errno = EFAULT;
 	NONFAILING(th->res = execute_syscall(call, th->args));
 	th->reserrno = errno;
+	th->elapsed_ms = current_time_ms() - th->start_ms;
 	// Our pseudo-syscalls may misbehave.
 	if ((th->res == -1 && th->reserrno == 0) || call->attrs.ignore_return)
 		th->reserrno = EINVAL;
@@ -1538,6 +1555,10 @@ void execute_call(thread_t* th)
 		debug(" fault=%d", th->fault_injected);
 	if (th->call_props.rerun > 0)
 		debug(" rerun=%d", th->call_props.rerun);
+	if (th->call_props.delay > 0)
+		debug(" delay=%d", th->call_props.delay);
+	if (th->call_props.cpu > 0)
+		debug(" cpu=%d", th->call_props.cpu);
 	debug("\n");
 }
 
@@ -154,7 +154,7 @@ public:
 			// report false misleading crashes.
 			uint64 timeout = 3 * ProgramTimeoutMs();
 #else
-			uint64 timeout = ProgramTimeoutMs();
+			uint64 timeout = RequestTimeoutMs();
 #endif
 			// Sandbox setup can take significant time.
 			if (state_ == State::Handshaking)
@@ -413,6 +413,7 @@ private:
 		    .exec_flags = static_cast<uint64>(msg_->exec_opts->exec_flags()),
 		    .all_call_signal = all_call_signal,
 		    .all_extra_signal = all_extra_signal,
+		    .program_timeout_ms = RequestTimeoutMs(),
 		};
 		exec_start_ = current_time_ms();
 		ChangeState(State::Executing);
@@ -528,6 +529,17 @@ private:
 		// Glob requests can expand to >10K files and can take a while to run.
 		return program_timeout_ms_ * (req_type_ == rpc::RequestType::Program ? 1 : 10);
 	}
+
+	// The manager may ask for a shorter timeout for programs that are known to execute fast,
+	// so that hanged programs don't waste lots of time.
+	uint32 RequestTimeoutMs() const
+	{
+		if (!msg_ || msg_->timeout_ms <= 0)
+			return ProgramTimeoutMs();
+		uint32 timeout = static_cast<uint32>(msg_->timeout_ms);
+		// The executor requires program timeout to be larger than syscall timeout.
+		return std::min(ProgramTimeoutMs(), std::max(timeout, 2 * syscall_timeout_ms_));
+	}
 };
 
 // Runner manages a set of test subprocesses (Proc's), receives new test requests from the manager,
@@ -750,11 +762,15 @@ private:
 
 	void Handle(const rpc::SignalUpdateRawT& msg)
 	{
-		debug("recv signal update: new=%zu\n", msg.new_max.size());
+		const auto* bitmap = msg.new_max_bitmap.get();
+		debug("recv signal update: new=%zu bitmap chunks=%zu\n",
+		      msg.new_max.size(), bitmap ? bitmap->offsets.size() : 0);
 		if (!max_signal_)
 			fail("signal update when no signal filter installed");
 		for (auto pc : msg.new_max)
 			max_signal_->Insert(pc);
+		if (bitmap)
+			max_signal_->InsertBitmap(*bitmap);
 	}
 
 	void Handle(const rpc::CorpusTriagedRawT& msg)
@@ -30,12 +30,42 @@
 // We don't need even networking in snapshot mode since we communicate via shared memory.
 
 static struct {
-	// Ivshmem interrupt doorbell register.
+	// Ivshmem interrupt doorbell register (nullptr in gVisor sandboxes).
 	volatile uint32* doorbell;
 	volatile rpc::SnapshotHeaderT* hdr;
 	void* input;
 } ivs;
 
+// In gVisor sandboxes there are no ivshmem devices, instead the host bind-mounts a shared memory file
+// at this path (the file must not be restored on checkpoint restore, similar to ivshmem memory).
+// Must be in sync with vm/gvisor/snapshot.go.
+static const char kSnapshotShmemFile[] = "/syz-snapshot-shmem";
+
+// Maps the shared memory region passed in fd as input/output memory.
+static void MapSnapshotShmem(int fd)
+{
+	void* input = mmap(nullptr, static_cast<uint64>(rpc::Const::MaxInputSize),
+			   PROT_READ, MAP_SHARED, fd, 0);
+	void* output = mmap(nullptr, static_cast<uint64>(rpc::Const::MaxOutputSize),
+			    PROT_READ | PROT_WRITE, MAP_SHARED, fd,
+			    static_cast<uint64>(rpc::Const::MaxInputSize));
+	if (input == MAP_FAILED || output == MAP_FAILED)
+		fail("failed to mmap snapshot shmem");
+	debug("mapped shmem input at at %p/%llu\n",
+	      input, static_cast<uint64>(rpc::Const::MaxInputSize));
+	debug("mapped shmem output at at %p/%llu\n",
+	      output, static_cast<uint64>(rpc::Const::MaxOutputSize));
+#if GOOS_linux
+	if (pkeys_enabled && pkey_mprotect(output, static_cast<uint64>(rpc::Const::MaxOutputSize),
+					   PROT_READ | PROT_WRITE, RESERVED_PKEY))
+		exitf("failed to pkey_mprotect output buffer");
+#endif
+	ivs.hdr = static_cast<rpc::SnapshotHeaderT*>(output);
+	ivs.input = input;
+	output_data = reinterpret_cast<OutputData*>(static_cast<char*>(output) + sizeof(rpc::SnapshotHeaderT));
+	output_size = static_cast<uint64>(rpc::Const::MaxOutputSize) - sizeof(rpc::SnapshotHeaderT);
+}
+
 // Finds qemu ivshmem device, see:
 // https://www.qemu.org/docs/master/specs/ivshmem-spec.html
 static void FindIvshmemDevices()
@@ -45,8 +75,6 @@ static void FindIvshmemDevices()
 	if (!devices)
 		fail("opendir(/sys/bus/pci/devices) failed");
 	void* regs = nullptr;
-	void* input = nullptr;
-	void* output = nullptr;
 	while (auto* dev = readdir(devices)) {
 		if (dev->d_name[0] == '.')
 			continue;
@@ -76,33 +104,25 @@ static void FindIvshmemDevices()
 				fail("failed to mmap ivshmem resource0");
 			debug("mapped doorbell registers at %p\n", regs);
 		} else if (statbuf.st_size == static_cast<uint64>(rpc::Const::SnapshotShmemSize)) {
-			input = mmap(nullptr, static_cast<uint64>(rpc::Const::MaxInputSize),
-				     PROT_READ, MAP_SHARED, res2, 0);
-			output = mmap(nullptr, static_cast<uint64>(rpc::Const::MaxOutputSize),
-				      PROT_READ | PROT_WRITE, MAP_SHARED, res2,
-				      static_cast<uint64>(rpc::Const::MaxInputSize));
-			if (input == MAP_FAILED || output == MAP_FAILED)
-				fail("failed to mmap ivshmem resource2");
-			debug("mapped shmem input at at %p/%llu\n",
-			      input, static_cast<uint64>(rpc::Const::MaxInputSize));
-			debug("mapped shmem output at at %p/%llu\n",
-			      output, static_cast<uint64>(rpc::Const::MaxOutputSize));
-#if GOOS_linux
-			if (pkeys_enabled && pkey_mprotect(output, static_cast<uint64>(rpc::Const::MaxOutputSize),
-							   PROT_READ | PROT_WRITE, RESERVED_PKEY))
-				exitf("failed to pkey_mprotect output buffer");
-#endif
+			MapSnapshotShmem(res2);
 		}
 		close(res2);
 	}
 	closedir(devices);
-	if (regs == nullptr || input == nullptr)
+	if (regs == nullptr || ivs.input == nullptr)
 		fail("cannot find ivshmem PCI devices");
 	ivs.doorbell = static_cast<uint32*>(regs) + 3;
-	ivs.hdr = static_cast<rpc::SnapshotHeaderT*>(output);
-	ivs.input = input;
-	output_data = reinterpret_cast<OutputData*>(static_cast<char*>(output) + sizeof(rpc::SnapshotHeaderT));
-	output_size = static_cast<uint64>(rpc::Const::MaxOutputSize) - sizeof(rpc::SnapshotHeaderT);
+}
+
+// Maps the shared memory file provided by the host in gVisor sandboxes.
+// There is no doorbell in this case, the host polls the state in the header.
+static void MapSnapshotShmemFile()
+{
+	int fd = open(kSnapshotShmemFile, O_RDWR);
+	if (fd == -1)
+		fail("failed to open snapshot shmem file");
+	MapSnapshotShmem(fd);
+	close(fd);
 }
 
 static void SnapshotSetup(char** argv, int argc)
@@ -116,7 +136,10 @@ static void SnapshotSetup(char** argv, int argc)
 	// This is required to turn off rate limiting of writes.
 	write_file("/proc/sys/kernel/printk_devkmsg", "on\n");
 #endif
-	FindIvshmemDevices();
+	if (access(kSnapshotShmemFile, F_OK) == 0)
+		MapSnapshotShmemFile();
+	else
+		FindIvshmemDevices();
 	// Wait for the host to write handshake_req into input memory.
 	while (ivs.hdr->state != rpc::SnapshotState::Handshake)
 		sleep_ms(10);
@@ -166,7 +189,8 @@ static void SnapshotSetState(rpc::SnapshotState state)
 	ivs.hdr->state = state;
 	// The register contains VM index shifted by 16 (the host part is VM index 1)
 	// + interrup vector index (0 in our case).
-	*ivs.doorbell = 1 << 16;
+	if (ivs.doorbell)
+		*ivs.doorbell = 1 << 16;
 }
 
 // PopulateMemory prefaults anon memory (we want to avoid minor page faults as well).
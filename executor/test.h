@@ -275,6 +275,59 @@ static int test_cover_filter()
 	return ret;
 }
 
+static int test_signal_bitmap()
+{
+	// Must match pkg/signal/bitmap.go encoding: each chunk covers SignalBitmapChunk bytes of PCs,
+	// one bit per 8-byte PC granule.
+	const uint64 chunk = static_cast<uint64>(rpc::Const::SignalBitmapChunk);
+	const size_t chunk_bytes = chunk / 8 / 8;
+	const uint64 off0 = 1 << 24;
+	const uint64 off1 = 100ull << 30;
+	rpc::SignalBitmapRawT bitmap;
+	bitmap.offsets = {off0, off1};
+	bitmap.bitmap.resize(2 * chunk_bytes);
+	bitmap.bitmap[0] = 1 << 0;
+	bitmap.bitmap[1] = 1 << 3;
+	bitmap.bitmap[2 * chunk_bytes - 1] = 1 << 7;
+
+	CoverFilter filter;
+	filter.InsertBitmap(bitmap);
+
+	std::vector<uint64> contain = {
+	    off0,
+	    off0 + 7,
+	    off0 + 88,
+	    off0 + 95,
+	    off1 + chunk - 8,
+	    off1 + chunk - 1,
+	};
+	std::vector<uint64> dont_contain = {
+	    off0 - 1,
+	    off0 + 8,
+	    off0 + 87,
+	    off0 + 96,
+	    off1 - 1,
+	    off1,
+	    off1 + chunk - 9,
+	    off1 + chunk,
+	};
+
+	int ret = 0;
+	for (auto pc : contain) {
+		if (!filter.Contains(pc)) {
+			printf("filter doesn't contain %llu (0x%llx)\n", pc, pc);
+			ret = 1;
+		}
+	}
+	for (auto pc : dont_contain) {
+		if (filter.Contains(pc)) {
+			printf("filter contains %llu (0x%llx)\n", pc, pc);
+			ret = 1;
+		}
+	}
+	return ret;
+}
+
 static bool test_one_glob(const char* pattern, std::vector<std::string> want)
 {
 	std::vector<std::string> got = Glob(pattern);
@@ -379,6 +432,7 @@ static struct {
     {"test_syzos", test_syzos},
 #endif
     {"test_cover_filter", test_cover_filter},
+    {"test_signal_bitmap", test_signal_bitmap},
     {"test_glob", test_glob},
 };
 
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"maps"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/hash"
@@ -32,6 +33,12 @@ type Corpus struct {
 	StatCover  *stat.Val
 
 	focusAreas []*focusAreaState
+	hits       *signal.HitCounts
+	directed   *directedState
+
+	restoredStats      map[string]SeedStats
+	restoredProvenance map[string]Provenance
+	chooseCount        atomic.Uint64
 }
 
 type focusAreaState struct {
@@ -54,20 +61,31 @@ func NewMonitoredCorpus(ctx context.Context, updates chan<- NewItemEvent) *Corpu
 }
 
 func NewFocusedCorpus(ctx context.Context, updates chan<- NewItemEvent, areas []FocusArea) *Corpus {
+	return NewDirectedCorpus(ctx, updates, areas, nil)
+}
+
+// NewDirectedCorpus creates a corpus for directed fuzzing, directed may be nil.
+func NewDirectedCorpus(ctx context.Context, updates chan<- NewItemEvent, areas []FocusArea,
+	directed *Directed) *Corpus {
 	corpus := &Corpus{
-		ctx:          ctx,
-		progsMap:     make(map[string]*Item),
-		updates:      updates,
-		ProgramsList: &ProgramsList{},
+		ctx:      ctx,
+		progsMap: make(map[string]*Item),
+		hits:     signal.NewHitCounts(),
+		updates:  updates,
+	}
+	if directed != nil {
+		corpus.directed = newDirectedState(directed)
+		corpus.initDirectedStats()
 	}
+	corpus.ProgramsList = corpus.newProgramsList()
 	corpus.StatProgs = stat.New("corpus", "Number of test programs in the corpus", stat.Console,
 		stat.Link("/corpus"), stat.Graph("corpus"), stat.LenOf(&corpus.progsMap, &corpus.mu))
 	corpus.StatSignal = stat.New("signal", "Fuzzing signal in the corpus",
-		stat.LenOf(&corpus.signal, &corpus.mu))
+		stat.Link("/rarity"), stat.LenOf(&corpus.signal, &corpus.mu))
 	corpus.StatCover = stat.New("coverage", "Source coverage in the corpus", stat.Console,
 		stat.Link("/cover"), stat.Prometheus("syz_corpus_cover"), stat.LenOf(&corpus.cover, &corpus.mu))
 	for _, area := range areas {
-		obj := &ProgramsList{}
+		obj := corpus.newProgramsList()
 		if len(areas) > 1 && area.Name != "" {
 			// Only show extra statistics if there's more than one area.
 			stat.New("corpus ["+area.Name+"]",
@@ -101,8 +119,12 @@ type Item struct {
 	Signal  signal.Signal
 	Cover   []uint64
 	Updates []ItemUpdate
+	// Provenance describes how the program was produced when it was first added to the corpus.
+	Provenance Provenance
 
-	areas map[*focusAreaState]struct{}
+	areas    map[*focusAreaState]struct{}
+	stats    *seedStats
+	distance uint32 // see Distance
 }
 
 func (item Item) StringCall() string {
@@ -110,18 +132,20 @@ func (item Item) StringCall() string {
 }
 
 type NewInput struct {
-	Prog     *prog.Prog
-	Call     int
-	Signal   signal.Signal
-	Cover    []uint64
-	RawCover []uint64
+	Prog       *prog.Prog
+	Call       int
+	Signal     signal.Signal
+	Cover      []uint64
+	RawCover   []uint64
+	Provenance Provenance
 }
 
 type NewItemEvent struct {
-	Sig      string
-	Exists   bool
-	ProgData []byte
-	NewCover []uint64
+	Sig        string
+	Exists     bool
+	ProgData   []byte
+	NewCover   []uint64
+	Provenance Provenance
 }
 
 func (corpus *Corpus) Save(inp NewInput) {
@@ -136,6 +160,7 @@ func (corpus *Corpus) Save(inp NewInput) {
 		RawCover: inp.RawCover,
 	}
 	exists := false
+	var provenance Provenance
 	if old, ok := corpus.progsMap[sig]; ok {
 		exists = true
 		newSignal := old.Signal.Copy()
@@ -144,34 +169,46 @@ func (corpus *Corpus) Save(inp NewInput) {
 		newCover.Merge(old.Cover)
 		newCover.Merge(inp.Cover)
 		newItem := &Item{
-			Sig:     sig,
-			Prog:    old.Prog,
-			Call:    old.Call,
-			HasAny:  old.HasAny,
-			Signal:  newSignal,
-			Cover:   newCover.Serialize(),
-			Updates: append([]ItemUpdate{}, old.Updates...),
-			areas:   maps.Clone(old.areas),
+			Sig:        sig,
+			Prog:       old.Prog,
+			Call:       old.Call,
+			HasAny:     old.HasAny,
+			Signal:     newSignal,
+			Cover:      newCover.Serialize(),
+			Updates:    append([]ItemUpdate{}, old.Updates...),
+			Provenance: old.Provenance,
+			areas:      maps.Clone(old.areas),
+			stats:      old.stats,
+			distance:   min(old.distance, corpus.distance(inp.Cover)),
 		}
 		const maxUpdates = 32
 		if len(newItem.Updates) < maxUpdates {
 			newItem.Updates = append(newItem.Updates, update)
 		}
 		corpus.progsMap[sig] = newItem
+		corpus.hits.UpdateProg(old.Signal, newSignal)
 		corpus.applyFocusAreas(newItem, inp.Cover)
+		corpus.applyDirected(newItem, inp.Cover)
+		provenance = newItem.Provenance
 	} else {
 		item := &Item{
-			Sig:     sig,
-			Call:    inp.Call,
-			Prog:    inp.Prog,
-			HasAny:  inp.Prog.ContainsAny(),
-			Signal:  inp.Signal,
-			Cover:   inp.Cover,
-			Updates: []ItemUpdate{update},
+			Sig:        sig,
+			Call:       inp.Call,
+			Prog:       inp.Prog,
+			HasAny:     inp.Prog.ContainsAny(),
+			Signal:     inp.Signal,
+			Cover:      inp.Cover,
+			Updates:    []ItemUpdate{update},
+			Provenance: corpus.takeProvenance(sig, inp.Provenance),
+			stats:      corpus.takeSeedStats(sig),
+			distance:   corpus.distance(inp.Cover),
 		}
 		corpus.progsMap[sig] = item
+		corpus.hits.AddProg(item.Signal)
 		corpus.applyFocusAreas(item, inp.Cover)
-		corpus.saveProgram(inp.Prog, inp.Signal)
+		corpus.applyDirected(item, inp.Cover)
+		corpus.saveItem(item)
+		provenance = item.Provenance
 	}
 	corpus.signal.Merge(inp.Signal)
 	newCover := corpus.cover.MergeDiff(inp.Cover)
@@ -179,10 +216,11 @@ func (corpus *Corpus) Save(inp NewInput) {
 		select {
 		case <-corpus.ctx.Done():
 		case corpus.updates <- NewItemEvent{
-			Sig:      sig,
-			Exists:   exists,
-			ProgData: progData,
-			NewCover: newCover,
+			Sig:        sig,
+			Exists:     exists,
+			ProgData:   progData,
+			NewCover:   newCover,
+			Provenance: provenance,
 		}:
 		}
 	}
@@ -200,7 +238,7 @@ func (corpus *Corpus) applyFocusAreas(item *Item, coverDelta []uint64) {
 		if !matches {
 			continue
 		}
-		area.saveProgram(item.Prog, item.Signal)
+		area.saveItem(item)
 		if item.areas == nil {
 			item.areas = make(map[*focusAreaState]struct{})
 			item.areas[area] = struct{}{}
@@ -214,6 +252,12 @@ func (corpus *Corpus) Signal() signal.Signal {
 	return corpus.signal.Copy()
 }
 
+// HitCounts returns per-element hit counts of the corpus signal.
+// The corpus accounts its programs, the caller may additionally account executions.
+func (corpus *Corpus) HitCounts() *signal.HitCounts {
+	return corpus.hits
+}
+
 func (corpus *Corpus) Items() []*Item {
 	corpus.mu.RLock()
 	defer corpus.mu.RUnlock()
@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
@@ -98,6 +99,49 @@ func TestCorpusSaveConcurrency(t *testing.T) {
 	}
 }
 
+func TestCorpusProvenance(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	ch := make(chan NewItemEvent)
+	corpus := NewMonitoredCorpus(context.Background(), ch)
+	rs := rand.NewSource(0)
+
+	mutated := Provenance{Job: ProvenanceMutate, Operator: "splice", Parent: "parent"}
+	inp1 := generateRangedInput(target, rs, 1, 5)
+	inp1.Provenance = mutated
+	go corpus.Save(inp1)
+	event := <-ch
+	assert.Equal(t, mutated, event.Provenance)
+
+	// Updates of existing items keep the original provenance.
+	inp1.Call = (inp1.Call + 1) % len(inp1.Prog.Calls)
+	inp1.Provenance = Provenance{Job: ProvenanceSmash}
+	go corpus.Save(inp1)
+	event = <-ch
+	assert.True(t, event.Exists)
+	assert.Equal(t, mutated, event.Provenance)
+
+	// Restored provenance takes precedence over the provenance of the triaged candidate.
+	inp2 := generateRangedInput(target, rs, 6, 10)
+	inp2.Provenance = Provenance{Job: ProvenanceCorpus}
+	generated := Provenance{Job: ProvenanceGenerate}
+	corpus.RestoreProvenance(map[string]Provenance{
+		hash.String(inp2.Prog.Serialize()): generated,
+	})
+	go corpus.Save(inp2)
+	event = <-ch
+	assert.Equal(t, generated, event.Provenance)
+
+	inp3 := generateRangedInput(target, rs, 11, 12)
+	inp3.Provenance = mutated
+	go corpus.Save(inp3)
+	<-ch
+
+	assert.Equal(t, []ProvenanceStat{
+		{Job: ProvenanceMutate, Operator: "splice", Progs: 2, Signal: 7},
+		{Job: ProvenanceGenerate, Progs: 1, Signal: 5},
+	}, corpus.ProvenanceStats())
+}
+
 func generateInput(target *prog.Target, rs rand.Source, sizeSig int) NewInput {
 	return generateRangedInput(target, rs, 1, sizeSig)
 }
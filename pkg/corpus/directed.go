@@ -0,0 +1,145 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/syzkaller/pkg/stat"
+)
+
+// Directed configures directed fuzzing (similar to AFLGo): programs that get closer
+// to the target code are chosen for mutation more often.
+type Directed struct {
+	// Targets maps target names to the coverage PCs of the target.
+	Targets map[string]map[uint64]struct{}
+	// Distances maps coverage PCs to the static call graph distance to the nearest target
+	// (see cover.ReportGenerator.Distances). PCs that can't reach any target are not present.
+	Distances map[uint64]uint32
+	// The schedule gradually switches from exploration to exploitation during this time.
+	// During exploration all programs are chosen equally regardless of the distance,
+	// during exploitation the closest programs are strongly preferred.
+	// If zero, the closest programs are preferred from the start.
+	ExploitTime time.Duration
+}
+
+const (
+	// Used for programs that don't cover any PCs with known distance.
+	unknownDistance = math.MaxUint32
+	// Directed power factor is in the range [2^-directedPowerExp/2, 2^directedPowerExp/2].
+	directedPowerExp = 10
+)
+
+type directedState struct {
+	*Directed
+	maxDistance uint32
+	start       time.Time
+	reached     map[string]*Item // protected by Corpus.mu
+	minDistance uint32           // protected by Corpus.mu
+}
+
+func newDirectedState(directed *Directed) *directedState {
+	ds := &directedState{
+		Directed:    directed,
+		start:       time.Now(),
+		reached:     make(map[string]*Item),
+		minDistance: unknownDistance,
+	}
+	for _, dist := range directed.Distances {
+		ds.maxDistance = max(ds.maxDistance, dist)
+	}
+	return ds
+}
+
+func (corpus *Corpus) initDirectedStats() {
+	ds := corpus.directed
+	stat.New("targets reached", "Number of reached directed fuzzing targets", stat.Console,
+		stat.LenOf(&ds.reached, &corpus.mu))
+	stat.New("target distance", "Minimal call graph distance to directed fuzzing targets in the corpus",
+		stat.Console, func() int {
+			corpus.mu.RLock()
+			defer corpus.mu.RUnlock()
+			if ds.minDistance == unknownDistance {
+				return -1
+			}
+			return int(ds.minDistance)
+		})
+}
+
+func (corpus *Corpus) newProgramsList() *ProgramsList {
+	return &ProgramsList{directed: corpus.directed}
+}
+
+// distance returns the minimal distance of the coverage PCs to the targets.
+func (corpus *Corpus) distance(cover []uint64) uint32 {
+	res := uint32(unknownDistance)
+	if corpus.directed == nil {
+		return res
+	}
+	for _, pc := range cover {
+		if dist, ok := corpus.directed.Distances[pc]; ok {
+			res = min(res, dist)
+		}
+	}
+	return res
+}
+
+// applyDirected records the targets reached by the item.
+func (corpus *Corpus) applyDirected(item *Item, cover []uint64) {
+	ds := corpus.directed
+	if ds == nil {
+		return
+	}
+	ds.minDistance = min(ds.minDistance, item.distance)
+	for name, pcs := range ds.Targets {
+		if ds.reached[name] != nil {
+			continue
+		}
+		for _, pc := range cover {
+			if _, ok := pcs[pc]; ok {
+				ds.reached[name] = item
+				break
+			}
+		}
+	}
+}
+
+// power returns the item weight multiplier according to the simulated annealing schedule of AFLGo.
+func (ds *directedState) power(item *Item) float64 {
+	if ds == nil {
+		return 1
+	}
+	dist := 1.0
+	if item.distance != unknownDistance && ds.maxDistance != 0 {
+		dist = float64(item.distance) / float64(ds.maxDistance)
+	}
+	// The temperature goes down from 1 (exploration) to ~0 (exploitation).
+	temp := 0.0
+	if ds.ExploitTime != 0 {
+		temp = math.Pow(20, -float64(time.Since(ds.start))/float64(ds.ExploitTime))
+	}
+	p := (1-dist)*(1-temp) + 0.5*temp
+	return math.Pow(2, directedPowerExp*(p-0.5))
+}
+
+// Distance returns the minimal call graph distance of the item coverage to directed fuzzing targets.
+// The second result is false if the distance is unknown (e.g. directed fuzzing is not enabled).
+func (item *Item) Distance() (uint32, bool) {
+	return item.distance, item.distance != unknownDistance
+}
+
+// DirectedTargets returns corpus items that reached the directed fuzzing targets
+// (the first item per target), unreached targets are not present.
+func (corpus *Corpus) DirectedTargets() map[string]*Item {
+	corpus.mu.RLock()
+	defer corpus.mu.RUnlock()
+	res := make(map[string]*Item)
+	if corpus.directed != nil {
+		for name, item := range corpus.directed.reached {
+			res[name] = item
+		}
+	}
+	return res
+}
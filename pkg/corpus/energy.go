@@ -0,0 +1,109 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// SeedStats describe how much fuzzing effort was spent on a corpus item
+// and how productive it was. They determine the item's energy, i.e. a multiplier
+// for the item's weight in ChooseProgram (similar to AFL-fast power schedules):
+// items that were mutated a lot without giving new signal are chosen less often,
+// items whose mutants give new signal are chosen more often.
+type SeedStats struct {
+	Mutations uint32 // number of executed mutants
+	NewSignal uint32 // number of mutants that gave new signal
+}
+
+const (
+	// The item energy halves after this many mutations that did not give new signal.
+	energyMutations = 1000
+	// Each productive mutant adds this much to the item energy.
+	energyNewSignal = 0.5
+	minEnergy       = 1.0 / 64
+	maxEnergy       = 16.0
+	// Item weights are recalculated after this many ChooseProgram calls.
+	energyUpdatePeriod = 1000
+	// Item rarity scores are more expensive to calculate,
+	// so they are recalculated only on every rarityUpdatePeriod-th weight update.
+	rarityUpdatePeriod = 100
+)
+
+func (s SeedStats) energy() float64 {
+	energy := (1 + energyNewSignal*float64(s.NewSignal)) / (1 + float64(s.Mutations)/energyMutations)
+	return math.Max(minEnergy, math.Min(maxEnergy, energy))
+}
+
+// seedStats are shared between all copies of an Item.
+type seedStats struct {
+	mutations atomic.Uint32
+	newSignal atomic.Uint32
+}
+
+func newSeedStats(init SeedStats) *seedStats {
+	s := new(seedStats)
+	s.mutations.Store(init.Mutations)
+	s.newSignal.Store(init.NewSignal)
+	return s
+}
+
+func (s *seedStats) get() SeedStats {
+	return SeedStats{
+		Mutations: s.mutations.Load(),
+		NewSignal: s.newSignal.Load(),
+	}
+}
+
+// RecordMutation accounts one executed mutant of the item.
+// Unlike the rest of the Item, seed stats are mutable and are shared by all copies of the item.
+func (item *Item) RecordMutation(newSignal bool) {
+	item.stats.mutations.Add(1)
+	if newSignal {
+		item.stats.newSignal.Add(1)
+	}
+}
+
+func (item *Item) SeedStats() SeedStats {
+	return item.stats.get()
+}
+
+// RestoreSeedStats sets initial seed stats for items that will be added to the corpus later
+// (e.g. during triage of programs from the persistent corpus), so that restarts don't reset the schedule.
+func (corpus *Corpus) RestoreSeedStats(stats map[string]SeedStats) {
+	corpus.mu.Lock()
+	defer corpus.mu.Unlock()
+	corpus.restoredStats = stats
+}
+
+func (corpus *Corpus) takeSeedStats(sig string) *seedStats {
+	stats := corpus.restoredStats[sig]
+	delete(corpus.restoredStats, sig)
+	return newSeedStats(stats)
+}
+
+func (corpus *Corpus) maybeUpdateEnergy() {
+	count := corpus.chooseCount.Add(1)
+	if count%energyUpdatePeriod != 0 {
+		return
+	}
+	rarity := count%(energyUpdatePeriod*rarityUpdatePeriod) == 0
+	corpus.mu.Lock()
+	defer corpus.mu.Unlock()
+	for _, pl := range corpus.programLists() {
+		if rarity {
+			pl.updateRarity(corpus.hits)
+		}
+		pl.updateEnergy()
+	}
+}
+
+func (corpus *Corpus) programLists() []*ProgramsList {
+	lists := []*ProgramsList{corpus.ProgramsList}
+	for _, area := range corpus.focusAreas {
+		lists = append(lists, area.ProgramsList)
+	}
+	return lists
+}
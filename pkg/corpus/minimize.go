@@ -38,18 +38,24 @@ func (corpus *Corpus) Minimize(cover bool) {
 	})
 
 	corpus.progsMap = make(map[string]*Item)
+	corpus.hits.ResetProgs()
 
 	// Overwrite the program lists.
-	corpus.ProgramsList = &ProgramsList{}
+	corpus.ProgramsList = corpus.newProgramsList()
 	for _, area := range corpus.focusAreas {
-		area.ProgramsList = &ProgramsList{}
+		area.ProgramsList = corpus.newProgramsList()
 	}
 	for _, ctx := range signal.Minimize(inputs) {
 		inp := ctx.(*Item)
 		corpus.progsMap[inp.Sig] = inp
-		corpus.saveProgram(inp.Prog, inp.Signal)
+		corpus.hits.AddProg(inp.Signal)
+		corpus.saveItem(inp)
 		for area := range inp.areas {
-			area.saveProgram(inp.Prog, inp.Signal)
+			area.saveItem(inp)
 		}
 	}
+	for _, pl := range corpus.programLists() {
+		pl.updateRarity(corpus.hits)
+		pl.updateEnergy()
+	}
 }
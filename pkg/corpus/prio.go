@@ -13,11 +13,22 @@ import (
 
 type ProgramsList struct {
 	progs    []*prog.Prog
+	items    []*Item
+	prios    []float64 // base priorities (signal rarity) not accounting for the item energy
 	sumPrios int64
 	accPrios []int64
+	directed *directedState
 }
 
 func (pl *ProgramsList) chooseProgram(r *rand.Rand) *prog.Prog {
+	item := pl.chooseItem(r)
+	if item == nil {
+		return nil
+	}
+	return item.Prog
+}
+
+func (pl *ProgramsList) chooseItem(r *rand.Rand) *Item {
 	if len(pl.progs) == 0 {
 		return nil
 	}
@@ -25,20 +36,51 @@ func (pl *ProgramsList) chooseProgram(r *rand.Rand) *prog.Prog {
 	idx := sort.Search(len(pl.accPrios), func(i int) bool {
 		return pl.accPrios[i] >= randVal
 	})
-	return pl.progs[idx]
+	return pl.items[idx]
 }
 
-func (pl *ProgramsList) saveProgram(p *prog.Prog, signal signal.Signal) {
-	prio := int64(len(signal))
-	if prio == 0 {
-		prio = 1
-	}
-	pl.sumPrios += prio
+func (pl *ProgramsList) saveItem(item *Item) {
+	// Until the next rarity update we use the signal size,
+	// which is the rarity score of signal that consists of unique elements.
+	pl.prios = append(pl.prios, float64(len(item.Signal)))
+	pl.progs = append(pl.progs, item.Prog)
+	pl.items = append(pl.items, item)
+	pl.sumPrios += pl.weight(len(pl.items) - 1)
 	pl.accPrios = append(pl.accPrios, pl.sumPrios)
-	pl.progs = append(pl.progs, p)
+}
+
+func (pl *ProgramsList) weight(idx int) int64 {
+	item := pl.items[idx]
+	return max(1, int64(pl.prios[idx]*item.stats.get().energy()*pl.directed.power(item)))
+}
+
+func (pl *ProgramsList) updateRarity(hits *signal.HitCounts) {
+	for i, item := range pl.items {
+		pl.prios[i] = hits.Rarity(item.Signal)
+	}
+}
+
+func (pl *ProgramsList) updateEnergy() {
+	pl.sumPrios = 0
+	for i := range pl.items {
+		pl.sumPrios += pl.weight(i)
+		pl.accPrios[i] = pl.sumPrios
+	}
 }
 
 func (corpus *Corpus) ChooseProgram(r *rand.Rand) *prog.Prog {
+	item := corpus.ChooseItem(r)
+	if item == nil {
+		return nil
+	}
+	return item.Prog
+}
+
+// ChooseItem selects a corpus item for mutation. The choice accounts for focus area weights,
+// the item signal rarity (see signal.HitCounts), the item energy (see SeedStats)
+// and the distance to directed fuzzing targets (see Directed).
+func (corpus *Corpus) ChooseItem(r *rand.Rand) *Item {
+	corpus.maybeUpdateEnergy()
 	corpus.mu.RLock()
 	defer corpus.mu.RUnlock()
 	if len(corpus.progsMap) == 0 {
@@ -67,9 +109,9 @@ func (corpus *Corpus) ChooseProgram(r *rand.Rand) *prog.Prog {
 		}
 	}
 	if randArea != nil {
-		return randArea.chooseProgram(r)
+		return randArea.chooseItem(r)
 	}
-	return corpus.chooseProgram(r)
+	return corpus.chooseItem(r)
 }
 
 func (corpus *Corpus) Programs() []*prog.Prog {
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
 	"github.com/stretchr/testify/assert"
@@ -117,3 +118,103 @@ func TestFocusAreas(t *testing.T) {
 	assert.InDelta(t, secondCount, TOTAL*0.3, TOTAL/25)
 	assert.InDelta(t, thirdCount, TOTAL*0.6, TOTAL/25)
 }
+
+func TestChooseProgramEnergy(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewCorpus(context.Background())
+	rs := rand.NewSource(0)
+	exhausted := generateInput(target, rs, 10)
+	productive := generateInput(target, rs, 10)
+	fresh := generateInput(target, rs, 10)
+	corpus.RestoreSeedStats(map[string]SeedStats{
+		hash.String(productive.Prog.Serialize()): {Mutations: 100, NewSignal: 10},
+	})
+	for _, inp := range []NewInput{exhausted, productive, fresh} {
+		corpus.Save(inp)
+	}
+	var exhaustedItem *Item
+	for _, item := range corpus.Items() {
+		switch item.Prog {
+		case exhausted.Prog:
+			exhaustedItem = item
+		case productive.Prog:
+			assert.Equal(t, SeedStats{Mutations: 100, NewSignal: 10}, item.SeedStats())
+		}
+	}
+	for i := 0; i < 10*energyMutations; i++ {
+		exhaustedItem.RecordMutation(false)
+	}
+	corpus.updateEnergy()
+
+	counters := make(map[*prog.Prog]int)
+	rnd := rand.New(rs)
+	for i := 0; i < 1000; i++ {
+		counters[corpus.ChooseProgram(rnd)]++
+	}
+	assert.Less(t, counters[exhausted.Prog], 50)
+	assert.Greater(t, counters[productive.Prog], 2*counters[fresh.Prog])
+}
+
+func TestChooseProgramRarity(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewCorpus(context.Background())
+	rs := rand.NewSource(0)
+	var common []NewInput
+	for i := 0; i < 10; i++ {
+		inp := generateRangedInput(target, rs, 1, 100)
+		common = append(common, inp)
+		corpus.Save(inp)
+	}
+	// The same signal size, but half of the elements are not hit by any other program.
+	rare := generateRangedInput(target, rs, 51, 150)
+	corpus.Save(rare)
+	corpus.updateRarity(corpus.hits)
+	corpus.updateEnergy()
+
+	counters := make(map[*prog.Prog]int)
+	rnd := rand.New(rs)
+	for i := 0; i < 1000; i++ {
+		counters[corpus.ChooseProgram(rnd)]++
+	}
+	for _, inp := range common {
+		assert.Greater(t, counters[rare.Prog], 3*counters[inp.Prog])
+	}
+}
+
+func TestChooseProgramDirected(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewDirectedCorpus(context.Background(), nil, nil, &Directed{
+		Targets: map[string]map[uint64]struct{}{
+			"target": {400: {}},
+		},
+		Distances: map[uint64]uint32{
+			105: 8,
+			205: 1,
+			400: 0,
+		},
+	})
+	rs := rand.NewSource(0)
+	far := generateRangedInput(target, rs, 100, 109)
+	near := generateRangedInput(target, rs, 200, 209)
+	unreachable := generateRangedInput(target, rs, 300, 309)
+	for _, inp := range []NewInput{far, near, unreachable} {
+		corpus.Save(inp)
+	}
+	assert.Empty(t, corpus.DirectedTargets())
+	counters := make(map[*prog.Prog]int)
+	rnd := rand.New(rs)
+	for i := 0; i < 1000; i++ {
+		counters[corpus.ChooseProgram(rnd)]++
+	}
+	assert.Greater(t, counters[near.Prog], 3*counters[far.Prog])
+	assert.Greater(t, counters[far.Prog], counters[unreachable.Prog])
+
+	reaching := generateRangedInput(target, rs, 395, 404)
+	corpus.Save(reaching)
+	items := corpus.DirectedTargets()
+	assert.Len(t, items, 1)
+	assert.Equal(t, reaching.Prog, items["target"].Prog)
+	dist, ok := items["target"].Distance()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0), dist)
+}
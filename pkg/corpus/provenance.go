@@ -0,0 +1,100 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import "sort"
+
+// Provenance describes how a program was produced.
+// It's carried over to the corpus if the program gives new coverage.
+type Provenance struct {
+	// Job that produced the program (one of the Provenance* constants).
+	Job string `json:",omitempty"`
+	// Mutation operator favoured by the scheduling policy for mutated programs.
+	Operator string `json:",omitempty"`
+	// Signature of the corpus program the program was derived from (if any).
+	Parent string `json:",omitempty"`
+}
+
+const (
+	ProvenanceGenerate = "generate"
+	ProvenanceMutate   = "mutate"
+	ProvenanceSmash    = "smash"
+	ProvenanceHints    = "hints"
+	ProvenanceCorpus   = "corpus"
+	ProvenanceSeed     = "seed"
+	ProvenanceHub      = "hub"
+	ProvenancePeer     = "peer"
+	ProvenanceManual   = "manual"
+)
+
+func (p Provenance) String() string {
+	if p.Job == "" {
+		return "unknown"
+	}
+	if p.Operator != "" {
+		return p.Job + "/" + p.Operator
+	}
+	return p.Job
+}
+
+// RestoreProvenance sets provenance for items that will be added to the corpus later
+// (e.g. during triage of programs from the persistent corpus), so that items keep
+// the provenance they got when they were first added to the corpus.
+func (corpus *Corpus) RestoreProvenance(provenance map[string]Provenance) {
+	corpus.mu.Lock()
+	defer corpus.mu.Unlock()
+	corpus.restoredProvenance = provenance
+}
+
+func (corpus *Corpus) takeProvenance(sig string, cur Provenance) Provenance {
+	if prev, ok := corpus.restoredProvenance[sig]; ok {
+		delete(corpus.restoredProvenance, sig)
+		return prev
+	}
+	return cur
+}
+
+// ProvenanceStat aggregates corpus items with the same job and operator.
+type ProvenanceStat struct {
+	Job      string
+	Operator string
+	Progs    int
+	Signal   int
+}
+
+// ProvenanceStats returns statistics of how the current corpus items were produced
+// sorted by the number of programs.
+func (corpus *Corpus) ProvenanceStats() []ProvenanceStat {
+	type key struct {
+		job      string
+		operator string
+	}
+	corpus.mu.RLock()
+	stats := make(map[key]*ProvenanceStat)
+	for _, item := range corpus.progsMap {
+		k := key{item.Provenance.Job, item.Provenance.Operator}
+		stat := stats[k]
+		if stat == nil {
+			stat = &ProvenanceStat{Job: k.job, Operator: k.operator}
+			stats[k] = stat
+		}
+		stat.Progs++
+		stat.Signal += item.Signal.Len()
+	}
+	corpus.mu.RUnlock()
+	var res []ProvenanceStat
+	for _, stat := range stats {
+		res = append(res, *stat)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Progs != res[j].Progs {
+			return res[i].Progs > res[j].Progs
+		}
+		if res[i].Job != res[j].Job {
+			return res[i].Job < res[j].Job
+		}
+		return res[i].Operator < res[j].Operator
+	})
+	return res
+}
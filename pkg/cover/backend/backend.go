@@ -18,6 +18,9 @@ type Impl struct {
 	Symbolize       func(pcs map[*vminfo.KernelModule][]uint64) ([]*Frame, error)
 	CallbackPoints  []uint64
 	PreciseCoverage bool
+	// Calls maps start addresses of symbols to start addresses of symbols they call directly.
+	// It's available only for the main kernel object and only on some architectures.
+	Calls map[uint64][]uint64
 }
 
 type CompileUnit struct {
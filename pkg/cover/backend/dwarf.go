@@ -115,6 +115,7 @@ func makeDWARF(params *dwarfParams) (impl *Impl, err error) {
 type Result struct {
 	CoverPoints [2][]uint64
 	Symbols     []*Symbol
+	CallSites   []callSite
 }
 
 func processModule(params *dwarfParams, module *vminfo.KernelModule, info *symbolInfo,
@@ -126,6 +127,7 @@ func processModule(params *dwarfParams, module *vminfo.KernelModule, info *symbo
 
 	var data []byte
 	var coverPoints [2][]uint64
+	var callSites []callSite
 	if _, ok := arches[target.Arch]; !ok {
 		coverPoints, err = objdump(target, module)
 	} else if module.Name == "" {
@@ -134,6 +136,9 @@ func processModule(params *dwarfParams, module *vminfo.KernelModule, info *symbo
 			return nil, err
 		}
 		coverPoints, err = readCoverPoints(target, info, data)
+		if err == nil {
+			callSites = readCallSites(target, info, data)
+		}
 	} else {
 		coverPoints, err = params.readModuleCoverPoints(target, module, info)
 	}
@@ -144,6 +149,7 @@ func processModule(params *dwarfParams, module *vminfo.KernelModule, info *symbo
 	result := &Result{
 		Symbols:     symbols,
 		CoverPoints: coverPoints,
+		CallSites:   callSites,
 	}
 	return result, nil
 }
@@ -160,12 +166,14 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 	var allSymbols []*Symbol
 	var allRanges []pcRange
 	var allUnits []*CompileUnit
+	var allCallSites []callSite
 	preciseCoverage := true
 	type binResult struct {
 		symbols     []*Symbol
 		coverPoints [2][]uint64
 		ranges      []pcRange
 		units       []*CompileUnit
+		callSites   []callSite
 		err         error
 	}
 	binC := make(chan binResult, len(modules))
@@ -192,7 +200,8 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 				binC <- binResult{err: err}
 				return
 			}
-			binC <- binResult{symbols: result.Symbols, coverPoints: result.CoverPoints, ranges: ranges, units: units}
+			binC <- binResult{symbols: result.Symbols, coverPoints: result.CoverPoints, ranges: ranges, units: units,
+				callSites: result.CallSites}
 		}()
 		if isKcovBrokenInCompiler(params.getCompilerVersion(module.Path)) {
 			preciseCoverage = false
@@ -208,6 +217,7 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 		allCoverPoints[1] = append(allCoverPoints[1], result.coverPoints[1]...)
 		allRanges = append(allRanges, result.ranges...)
 		allUnits = append(allUnits, result.units...)
+		allCallSites = append(allCallSites, result.callSites...)
 	}
 	log.Logf(1, "discovered %v source files, %v symbols", len(allUnits), len(allSymbols))
 	// TODO: need better way to remove symbols having the same Start
@@ -224,6 +234,9 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 	sort.Slice(allSymbols, func(i, j int) bool {
 		return allSymbols[i].Start < allSymbols[j].Start
 	})
+	// Note: buildSymbols drops symbols without coverage points, but functions
+	// that are not instrumented may still transitively call interesting functions.
+	calls := buildCallGraph(allSymbols, allCallSites)
 	sort.Slice(allRanges, func(i, j int) bool {
 		return allRanges[i].start < allRanges[j].start
 	})
@@ -257,6 +270,7 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 		},
 		CallbackPoints:  allCoverPoints[0],
 		PreciseCoverage: preciseCoverage,
+		Calls:           calls,
 	}
 	return impl, nil
 }
@@ -321,6 +335,43 @@ func buildSymbols(symbols []*Symbol, ranges []pcRange, coverPoints [2][]uint64)
 	return symbols
 }
 
+type callSite struct {
+	pc     uint64
+	target uint64
+}
+
+// buildCallGraph maps call sites to the calling and the called symbols.
+// The symbols must be sorted by start address.
+func buildCallGraph(symbols []*Symbol, callSites []callSite) map[uint64][]uint64 {
+	if len(callSites) == 0 {
+		return nil
+	}
+	find := func(pc uint64) *Symbol {
+		idx := sort.Search(len(symbols), func(i int) bool {
+			return symbols[i].End > pc
+		})
+		if idx == len(symbols) || pc < symbols[idx].Start {
+			return nil
+		}
+		return symbols[idx]
+	}
+	edges := make(map[[2]uint64]bool)
+	calls := make(map[uint64][]uint64)
+	for _, site := range callSites {
+		caller, callee := find(site.pc), find(site.target)
+		if caller == nil || callee == nil || caller == callee {
+			continue
+		}
+		edge := [2]uint64{caller.Start, callee.Start}
+		if edges[edge] {
+			continue
+		}
+		edges[edge] = true
+		calls[caller.Start] = append(calls[caller.Start], callee.Start)
+	}
+	return calls
+}
+
 // Regexps to parse compiler version string in isKcovBrokenInCompiler.
 // Some targets (e.g. NetBSD) use g++ instead of gcc.
 var gccRE = regexp.MustCompile(`gcc|GCC|g\+\+`)
@@ -644,6 +695,24 @@ func readCoverPoints(target *targets.Target, info *symbolInfo, data []byte) ([2]
 	return pcs, nil
 }
 
+// readCallSites finds all direct calls (except for calls of coverage callbacks) in the object file.
+func readCallSites(target *targets.Target, info *symbolInfo, data []byte) []callSite {
+	var sites []callSite
+	i := 0
+	arch := arches[target.Arch]
+	for {
+		callTarget, pc := nextCallTarget(arch, info.textAddr, data, &i)
+		if callTarget == 0 {
+			break
+		}
+		if info.tracePC[callTarget] || info.traceCmp[callTarget] {
+			continue
+		}
+		sites = append(sites, callSite{pc: pc, target: callTarget})
+	}
+	return sites
+}
+
 // Source files for Android may be split between two subdirectories: the common AOSP kernel
 // and the device-specific drivers: https://source.android.com/docs/setup/build/building-pixel-kernels.
 // Android build system references these subdirectories in various ways, which often results in
@@ -4,6 +4,7 @@
 package backend
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -174,3 +175,27 @@ func TestNextCallTargetAMD64(t *testing.T) {
 		runNextCallTarget(t, test)
 	}
 }
+
+func TestBuildCallGraph(t *testing.T) {
+	symbols := []*Symbol{
+		{Start: 0x1000, End: 0x1100},
+		{Start: 0x1100, End: 0x1200},
+		{Start: 0x1300, End: 0x1400},
+	}
+	calls := buildCallGraph(symbols, []callSite{
+		{pc: 0x1010, target: 0x1100},
+		{pc: 0x1020, target: 0x1100}, // duplicate edge
+		{pc: 0x1030, target: 0x1300},
+		{pc: 0x1040, target: 0x1000}, // recursion
+		{pc: 0x1110, target: 0x1300},
+		{pc: 0x1210, target: 0x1300}, // the caller is outside of all symbols
+		{pc: 0x1310, target: 0x2000}, // the callee is outside of all symbols
+	})
+	want := map[uint64][]uint64{
+		0x1000: {0x1100, 0x1300},
+		0x1100: {0x1300},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("got call graph %v, want %v", calls, want)
+	}
+}
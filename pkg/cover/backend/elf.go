@@ -4,6 +4,7 @@
 package backend
 
 import (
+	"debug/dwarf"
 	"debug/elf"
 	"encoding/binary"
 	"fmt"
@@ -221,6 +222,38 @@ func elfGetCompilerVersion(path string) string {
 	return string(data[:])
 }
 
+// ElfReadSections returns contents of the sections of the ELF object file
+// with names accepted by the filter.
+func ElfReadSections(path string, filter func(name string) bool) (map[string][]byte, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	sections := make(map[string][]byte)
+	for _, sec := range file.Sections {
+		if sec.Type == elf.SHT_NOBITS || !filter(sec.Name) {
+			continue
+		}
+		data, err := sec.Data()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %v: %w", sec.Name, err)
+		}
+		sections[sec.Name] = data
+	}
+	return sections, nil
+}
+
+// ElfReadDWARF returns DWARF debug info of the ELF object file.
+func ElfReadDWARF(path string) (*dwarf.Data, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return file.DWARF()
+}
+
 func elfReadTextSecRange(module *vminfo.KernelModule) (*SecRange, error) {
 	text, err := elfReadTextSec(module)
 	if err != nil {
@@ -0,0 +1,141 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/vminfo"
+)
+
+// DirectedTargets resolves directed fuzzing targets to coverage callback PCs.
+// A target is either a function name (e.g. "tcp_v4_rcv"),
+// or a source line (e.g. "net/ipv4/tcp_input.c:1234").
+func (rg *ReportGenerator) DirectedTargets(targets []string) (map[string][]uint64, error) {
+	res := make(map[string][]uint64)
+	for _, target := range targets {
+		var pcs []uint64
+		if file, line, ok := parseSourceLine(target); ok {
+			var err error
+			pcs, err = rg.linePCs(file, line)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			for _, sym := range rg.Symbols {
+				if sym.Name == target {
+					pcs = append(pcs, sym.PCs...)
+				}
+			}
+		}
+		if len(pcs) == 0 {
+			return nil, fmt.Errorf("directed target %q does not match any coverage points", target)
+		}
+		res[target] = pcs
+	}
+	return res, nil
+}
+
+func parseSourceLine(target string) (string, int, bool) {
+	pos := strings.LastIndexByte(target, ':')
+	if pos == -1 {
+		return "", 0, false
+	}
+	line, err := strconv.Atoi(target[pos+1:])
+	if err != nil || line <= 0 {
+		return "", 0, false
+	}
+	return target[:pos], line, true
+}
+
+func (rg *ReportGenerator) linePCs(file string, line int) ([]uint64, error) {
+	matchFile := func(name string) bool {
+		return name == file || strings.HasSuffix(name, "/"+file)
+	}
+	pcs := make(map[*vminfo.KernelModule][]uint64)
+	for _, unit := range rg.Units {
+		if matchFile(unit.Name) {
+			pcs[unit.Module] = append(pcs[unit.Module], unit.PCs...)
+		}
+	}
+	if len(pcs) == 0 {
+		return nil, nil
+	}
+	frames, err := rg.Symbolize(pcs)
+	if err != nil {
+		return nil, err
+	}
+	var res []uint64
+	for _, frame := range frames {
+		if frame.StartLine == line && matchFile(frame.Name) {
+			res = append(res, frame.PC)
+		}
+	}
+	return res, nil
+}
+
+// Distances returns static call graph distances from coverage callback PCs to the target PCs.
+// Target PCs have distance 0, other PCs of functions that contain target PCs have distance 1,
+// PCs of functions that can reach such functions via direct calls have distance 1 + the length
+// of the shortest call chain. PCs that can't reach any target are not present in the result.
+// Indirect calls are not accounted, so the distances are only an approximation.
+func (rg *ReportGenerator) Distances(targetPCs []uint64) map[uint64]uint32 {
+	targets := make(map[uint64]bool)
+	for _, pc := range targetPCs {
+		targets[pc] = true
+	}
+	callers := make(map[uint64][]uint64)
+	for caller, callees := range rg.Calls {
+		for _, callee := range callees {
+			callers[callee] = append(callers[callee], caller)
+		}
+	}
+	// Breadth-first search over the reverse call graph starting from the target functions.
+	funcDist := make(map[uint64]uint32)
+	var queue []uint64
+	for _, sym := range rg.Symbols {
+		if _, ok := funcDist[sym.Start]; ok || !containsAny(sym, targets) {
+			continue
+		}
+		funcDist[sym.Start] = 1
+		queue = append(queue, sym.Start)
+	}
+	for len(queue) != 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		for _, caller := range callers[fn] {
+			if _, ok := funcDist[caller]; !ok {
+				funcDist[caller] = funcDist[fn] + 1
+				queue = append(queue, caller)
+			}
+		}
+	}
+	res := make(map[uint64]uint32)
+	for _, sym := range rg.Symbols {
+		dist, ok := funcDist[sym.Start]
+		if !ok {
+			continue
+		}
+		for _, pc := range sym.PCs {
+			if targets[pc] {
+				res[pc] = 0
+			} else {
+				res[pc] = dist
+			}
+		}
+	}
+	return res
+}
+
+func containsAny(sym *backend.Symbol, pcs map[uint64]bool) bool {
+	for _, pc := range sym.PCs {
+		if pcs[pc] {
+			return true
+		}
+	}
+	return false
+}
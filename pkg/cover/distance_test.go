@@ -0,0 +1,61 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistances(t *testing.T) {
+	sym := func(name string, start uint64, pcs ...uint64) *backend.Symbol {
+		return &backend.Symbol{
+			ObjectUnit: backend.ObjectUnit{Name: name, PCs: pcs},
+			Start:      start,
+			End:        start + 0x100,
+		}
+	}
+	rg := &ReportGenerator{
+		Impl: &backend.Impl{
+			Symbols: []*backend.Symbol{
+				sym("syscall", 0x1000, 0x1010, 0x1020),
+				sym("helper", 0x1100, 0x1110),
+				sym("target", 0x1200, 0x1210, 0x1220),
+				sym("unrelated", 0x1300, 0x1310),
+				sym("callee", 0x1400, 0x1410),
+			},
+			Calls: map[uint64][]uint64{
+				0x1000: {0x1100, 0x1300},
+				0x1100: {0x1200},
+				0x1200: {0x1400},
+			},
+		},
+	}
+	targets, err := rg.DirectedTargets([]string{"target"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]uint64{"target": {0x1210, 0x1220}}, targets)
+	_, err = rg.DirectedTargets([]string{"nonexistent"})
+	assert.Error(t, err)
+
+	assert.Equal(t, map[uint64]uint32{
+		0x1010: 3,
+		0x1020: 3,
+		0x1110: 2,
+		0x1210: 1,
+		0x1220: 0,
+	}, rg.Distances([]uint64{0x1220}))
+}
+
+func TestParseSourceLine(t *testing.T) {
+	file, line, ok := parseSourceLine("net/ipv4/tcp_input.c:1234")
+	assert.True(t, ok)
+	assert.Equal(t, "net/ipv4/tcp_input.c", file)
+	assert.Equal(t, 1234, line)
+	_, _, ok = parseSourceLine("tcp_v4_rcv")
+	assert.False(t, ok)
+	_, _, ok = parseSourceLine("foo:bar")
+	assert.False(t, ok)
+}
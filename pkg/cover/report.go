@@ -250,6 +250,14 @@ func fileByFrame(files map[string]*file, frame *backend.Frame) *file {
 	return f
 }
 
+// FormatPC returns the PC in the func+0xoff form, or in the hex form if the function is unknown.
+func (rg *ReportGenerator) FormatPC(pc uint64) string {
+	if sym := rg.findSymbol(pc); sym != nil {
+		return fmt.Sprintf("%v+%#x", sym.Name, pc-sym.Start)
+	}
+	return fmt.Sprintf("%#x", pc)
+}
+
 func (rg *ReportGenerator) findSymbol(pc uint64) *backend.Symbol {
 	idx := sort.Search(len(rg.Symbols), func(i int) bool {
 		return pc < rg.Symbols[i].End
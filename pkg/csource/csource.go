@@ -252,6 +252,12 @@ func (ctx *context) generateProgCalls(p *prog.Prog, trace bool) ([]string, []uin
 func (ctx *context) generateCalls(p prog.ExecProg, trace bool) ([]string, []uint64) {
 	var calls []string
 	csumSeq := 0
+	pinning := false
+	for _, call := range p.Calls {
+		if call.Props.CPU > 0 {
+			pinning = true
+		}
+	}
 	for ci, call := range p.Calls {
 		w := new(bytes.Buffer)
 		// Copyin.
@@ -259,6 +265,11 @@ func (ctx *context) generateCalls(p prog.ExecProg, trace bool) ([]string, []uint
 			ctx.copyin(w, &csumSeq, copyin)
 		}
 
+		// If any call is pinned, calls without a cpu need to undo pinning done by previous calls
+		// executed in the same thread (this does not change affinity of threads that were not pinned).
+		if call.Props.Delay > 0 || call.Props.CPU > 0 || pinning {
+			fmt.Fprintf(w, "\tset_call_schedule(%v, %v);\n", call.Props.CPU, call.Props.Delay)
+		}
 		if call.Props.FailNth > 0 {
 			fmt.Fprintf(w, "\tinject_fault(%v);\n", call.Props.FailNth)
 		}
@@ -106,6 +106,10 @@ func testTarget(t *testing.T, target *prog.Target, full bool) {
 	if len(p.Calls) > 2 {
 		p.Calls[2].Props.Rerun = 4
 	}
+	if len(p.Calls) > 3 {
+		p.Calls[3].Props.Delay = 10
+		p.Calls[3].Props.CPU = 2
+	}
 	for opti, opts := range opts {
 		if testing.Short() && opts.HandleSegv {
 			// HandleSegv can radically increase compilation time/memory consumption on large programs.
@@ -270,6 +274,32 @@ syscall(SYS_csource8, /*num=*/(intptr_t)-1);
 `,
 			target: target32,
 		},
+		{
+			input: `
+csource0(0x1) (async, cpu: 1)
+csource0(0x2) (delay: 100, cpu: 2)
+csource0(0x3)
+`,
+			output: `
+set_call_schedule(1, 0);
+syscall(SYS_csource0, /*num=*/1);
+set_call_schedule(2, 100);
+syscall(SYS_csource0, /*num=*/2);
+set_call_schedule(0, 0);
+syscall(SYS_csource0, /*num=*/3);
+`,
+		},
+		{
+			input: `
+csource0(0x1) (delay: 100)
+csource0(0x2)
+`,
+			output: `
+set_call_schedule(0, 100);
+syscall(SYS_csource0, /*num=*/1);
+syscall(SYS_csource0, /*num=*/2);
+`,
+		},
 	}
 	for i, test := range tests {
 		t.Run(fmt.Sprint(i), func(t *testing.T) {
@@ -17,6 +17,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -26,6 +27,11 @@ import (
 type DB struct {
 	Version uint64            // arbitrary user version (0 for new database)
 	Records map[string]Record // in-memory cache, must not be modified directly
+	// Meta holds auxiliary data attached to records (e.g. fuzzing stats of corpus programs).
+	// It's stored in the same file, but is not part of Records, so that users that expect
+	// only e.g. programs in Records are not affected. Must not be modified directly.
+	// Meta is kept in memory even after DiscardData.
+	Meta map[string][]byte
 
 	filename      string
 	uncompacted   int           // number of records in the file
@@ -47,6 +53,7 @@ func Open(filename string, repair bool) (*DB, error) {
 	}
 	var deserializeErr error
 	db.Version, db.Records, db.uncompacted, deserializeErr = deserializeFile(db.filename)
+	db.Meta = splitMeta(db.Records)
 	// Deserialization error is considered a "soft" error if repair == true,
 	// but compact below ensures that the file is at least writable.
 	if deserializeErr != nil && !repair {
@@ -81,6 +88,51 @@ func (db *DB) Delete(key string) {
 	delete(db.Records, key)
 	db.serialize(key, nil, seqDeleted)
 	db.uncompacted++
+	db.DeleteMeta(key)
+}
+
+// SaveMeta sets auxiliary data for the record with the key (see DB.Meta).
+// The meta is deleted along with the record.
+func (db *DB) SaveMeta(key string, val []byte) {
+	if _, ok := db.Records[key]; !ok {
+		panic("saving meta for a missing record")
+	}
+	if old, ok := db.Meta[key]; ok && bytes.Equal(old, val) {
+		return
+	}
+	db.serialize(metaKeyPrefix+key, val, 0)
+	db.Meta[key] = val
+	db.uncompacted++
+}
+
+func (db *DB) DeleteMeta(key string) {
+	if _, ok := db.Meta[key]; !ok {
+		return
+	}
+	delete(db.Meta, key)
+	db.serialize(metaKeyPrefix+key, nil, seqDeleted)
+	db.uncompacted++
+}
+
+// Meta records are stored as usual records with this key prefix
+// (record keys are usually hashes, so they don't clash with it).
+const metaKeyPrefix = "\x00meta:"
+
+// splitMeta removes meta records from records and returns meta of the remaining records.
+func splitMeta(records map[string]Record) map[string][]byte {
+	meta := make(map[string][]byte)
+	for key, rec := range records {
+		if strings.HasPrefix(key, metaKeyPrefix) {
+			meta[strings.TrimPrefix(key, metaKeyPrefix)] = rec.Val
+			delete(records, key)
+		}
+	}
+	for key := range meta {
+		if _, ok := records[key]; !ok {
+			delete(meta, key)
+		}
+	}
+	return meta
 }
 
 // DiscardData discards all record's values from memory.
@@ -107,7 +159,7 @@ func (db *DB) Flush() error {
 		return err
 	}
 	db.pending = nil
-	if db.uncompacted/10*9 < len(db.Records) {
+	if db.uncompacted/10*9 < len(db.Records)+len(db.Meta) {
 		return nil
 	}
 	return db.compact()
@@ -135,12 +187,16 @@ func (db *DB) compact() error {
 		if err != nil {
 			return err
 		}
+		splitMeta(records)
 	}
 	buf := new(bytes.Buffer)
 	serializeHeader(buf, db.Version)
 	for key, rec := range records {
 		serializeRecord(buf, key, rec.Val, rec.Seq)
 	}
+	for key, val := range db.Meta {
+		serializeRecord(buf, metaKeyPrefix+key, val, 0)
+	}
 	f, err := os.Create(db.filename + ".tmp")
 	if err != nil {
 		return err
@@ -153,7 +209,7 @@ func (db *DB) compact() error {
 	if err := osutil.Rename(f.Name(), db.filename); err != nil {
 		return err
 	}
-	db.uncompacted = len(records)
+	db.uncompacted = len(records) + len(db.Meta)
 	return nil
 }
 
@@ -177,6 +177,72 @@ func TestDiscardData(t *testing.T) {
 	}
 }
 
+func TestMeta(t *testing.T) {
+	fn := tempFile(t)
+	defer os.Remove(fn)
+	db, err := Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	db.Save("1", []byte("11"), 1)
+	db.Save("2", []byte("22"), 2)
+	db.SaveMeta("1", []byte("meta1"))
+	db.SaveMeta("2", []byte("meta2"))
+	db.DeleteMeta("2")
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	wantRecords := map[string]Record{
+		"1": {Val: []byte("11"), Seq: 1},
+		"2": {Val: []byte("22"), Seq: 2},
+	}
+	if !reflect.DeepEqual(db.Records, wantRecords) {
+		t.Fatalf("bad db records: %v, want: %v", db.Records, wantRecords)
+	}
+	wantMeta := map[string][]byte{
+		"1": []byte("meta1"),
+	}
+	if !reflect.DeepEqual(db.Meta, wantMeta) {
+		t.Fatalf("bad db meta: %q, want: %q", db.Meta, wantMeta)
+	}
+	// Meta must survive compaction of a database with discarded data.
+	db.DiscardData()
+	for i := 0; i < 100; i++ {
+		db.SaveMeta("2", []byte(fmt.Sprint(i)))
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if !reflect.DeepEqual(db.Records, wantRecords) {
+		t.Fatalf("bad db records: %v, want: %v", db.Records, wantRecords)
+	}
+	wantMeta["2"] = []byte("99")
+	if !reflect.DeepEqual(db.Meta, wantMeta) {
+		t.Fatalf("bad db meta: %q, want: %q", db.Meta, wantMeta)
+	}
+	// Meta is deleted along with the record.
+	db.Delete("1")
+	if err := db.Flush(); err != nil {
+		t.Fatalf("failed to flush db: %v", err)
+	}
+	db, err = Open(fn, false)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	delete(wantMeta, "1")
+	if !reflect.DeepEqual(db.Meta, wantMeta) {
+		t.Fatalf("bad db meta: %q, want: %q", db.Meta, wantMeta)
+	}
+}
+
 func TestOpenInvalid(t *testing.T) {
 	f, err := os.CreateTemp("", "syz-db-test")
 	if err != nil {
@@ -0,0 +1,86 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package dictionary extracts interesting values (string literals, magic constants, enum values)
+// from kernel binaries and stores them in a per-kernel dictionary file.
+// The dictionary is used by the fuzzer to mutate data and integer arguments (see prog.Dictionary).
+//
+// The dictionary file is a text file with one value per line, e.g.:
+//
+//	int 0xef53
+//	str "ReIsEr2Fs"
+//
+// Strings use Go quoting, so they may contain arbitrary bytes.
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+func Load(file string) (*prog.Dictionary, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*prog.Dictionary, error) {
+	dict := new(prog.Dictionary)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		ln := strings.TrimSpace(s.Text())
+		if ln == "" || ln[0] == '#' {
+			continue
+		}
+		kind, val, _ := strings.Cut(ln, " ")
+		switch kind {
+		case "int":
+			v, err := strconv.ParseUint(val, 0, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: bad int value %q: %w", line, val, err)
+			}
+			dict.Ints = append(dict.Ints, v)
+		case "str":
+			str, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: bad string value %v: %w", line, val, err)
+			}
+			dict.Strings = append(dict.Strings, []byte(str))
+		default:
+			return nil, fmt.Errorf("line %v: unknown value kind %q", line, kind)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return dict, nil
+}
+
+func Save(file string, dict *prog.Dictionary) error {
+	buf := new(bytes.Buffer)
+	if err := Write(buf, dict); err != nil {
+		return err
+	}
+	return osutil.WriteFile(file, buf.Bytes())
+}
+
+func Write(w io.Writer, dict *prog.Dictionary) error {
+	bw := bufio.NewWriter(w)
+	for _, v := range dict.Ints {
+		fmt.Fprintf(bw, "int %#x\n", v)
+	}
+	for _, str := range dict.Strings {
+		fmt.Fprintf(bw, "str %q\n", str)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dictionary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerialize(t *testing.T) {
+	dict := &prog.Dictionary{
+		Strings: [][]byte{[]byte("ReIsEr2Fs"), []byte("a b\x00\xff\"")},
+		Ints:    []uint64{0xef53, 0xffffffffffff0000},
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, Write(buf, dict))
+	dict1, err := Parse(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, dict, dict1)
+
+	_, err = Parse([]byte("int foo\n"))
+	assert.Error(t, err)
+	_, err = Parse([]byte("float 1.0\n"))
+	assert.Error(t, err)
+}
+
+func TestExtractStrings(t *testing.T) {
+	strs := make(map[string]int)
+	extractStrings([]byte("\x00abc\x00ReIsEr2Fs\x00\x01%s: failed\n\x00nl80211\x00ReIsEr2Fs\x00noterm"), strs)
+	assert.Equal(t, map[string]int{
+		"ReIsEr2Fs": 2,
+		"nl80211":   1,
+	}, strs)
+}
+
+func TestParseCmpImmediate(t *testing.T) {
+	tests := []struct {
+		arch string
+		line string
+		val  uint64
+		ok   bool
+	}{
+		{targets.AMD64, "ffffffff81a3b4c5:\tcmpw   $0xef53,0x38(%rbx)", 0xef53, true},
+		{targets.AMD64, "ffffffff81a3b4c5:\tcmp    $0x58465342,%eax", 0x58465342, true},
+		{targets.AMD64, "ffffffff81a3b4c5:\tcmp    %eax,%ebx", 0, false},
+		{targets.AMD64, "ffffffff81a3b4c5:\tmov    $0xef53,%eax", 0, false},
+		{targets.ARM64, "ffff80000812b4c0:\tcmp\tw1, #0x5c", 0x5c, true},
+		{targets.ARM64, "ffff80000812b4c0:\tcmp\tx1, x2", 0, false},
+	}
+	for _, test := range tests {
+		val, ok := parseCmpImmediate(cmpRegexps[test.arch], []byte(test.line))
+		assert.Equal(t, test.ok, ok, test.line)
+		assert.Equal(t, test.val, val, test.line)
+	}
+}
+
+func TestAddInt(t *testing.T) {
+	ints := make(map[uint64]int)
+	for _, v := range []uint64{0, 0x10, 0xef53, 0xef53, ^uint64(0), 0xffffffff81000000, 0xcafef00d} {
+		addInt(ints, v)
+	}
+	assert.Equal(t, map[uint64]int{
+		0xef53:     2,
+		0xcafef00d: 1,
+	}, ints)
+	assert.Equal(t, []uint64{0xef53, 0xcafef00d}, mostFrequent(ints, 10, func(a, b uint64) bool { return a < b }))
+	assert.Equal(t, []uint64{0xef53}, mostFrequent(ints, 1, func(a, b uint64) bool { return a < b }))
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"debug/dwarf"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+const (
+	// The most frequent values are kept if there are more.
+	maxStrings = 50000
+	maxInts    = 50000
+
+	minStringLen = 4
+	maxStringLen = 64
+)
+
+// Extract extracts the dictionary from the kernel object file (e.g. vmlinux):
+//   - string literals from the read-only data sections,
+//   - enum values from DWARF debug info,
+//   - immediate operands of comparison instructions (requires objdump for the target).
+func Extract(target *targets.Target, kernelObject string) (*prog.Dictionary, error) {
+	sections, err := backend.ElfReadSections(kernelObject, func(name string) bool {
+		return name == ".rodata" || strings.HasPrefix(name, ".rodata.str")
+	})
+	if err != nil {
+		return nil, err
+	}
+	strs := make(map[string]int)
+	for _, data := range sections {
+		extractStrings(data, strs)
+	}
+	ints := make(map[uint64]int)
+	if debugInfo, err := backend.ElfReadDWARF(kernelObject); err == nil {
+		if err := extractEnums(debugInfo, ints); err != nil {
+			return nil, err
+		}
+	}
+	if re := cmpRegexps[target.Arch]; re != nil && target.Objdump != "" {
+		if err := extractCmpImmediates(target, kernelObject, re, ints); err != nil {
+			return nil, err
+		}
+	}
+	dict := &prog.Dictionary{
+		Ints: mostFrequent(ints, maxInts, func(a, b uint64) bool { return a < b }),
+	}
+	for _, str := range mostFrequent(strs, maxStrings, func(a, b string) bool { return a < b }) {
+		dict.Strings = append(dict.Strings, []byte(str))
+	}
+	return dict, nil
+}
+
+// extractStrings extracts NUL-terminated identifier-like strings.
+// Format strings, messages and file paths are not interesting since the kernel
+// does not compare inputs against them, so strings with spaces and % are skipped.
+func extractStrings(data []byte, strs map[string]int) {
+	start := 0
+	for i, v := range data {
+		if v >= 0x21 && v <= 0x7e && v != '%' {
+			continue
+		}
+		if v == 0 && i-start >= minStringLen && i-start <= maxStringLen {
+			strs[string(data[start:i])]++
+		}
+		start = i + 1
+	}
+}
+
+func extractEnums(debugInfo *dwarf.Data, ints map[uint64]int) error {
+	for r := debugInfo.Reader(); ; {
+		ent, err := r.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read DWARF: %w", err)
+		}
+		if ent == nil {
+			return nil
+		}
+		if ent.Tag != dwarf.TagEnumerator {
+			continue
+		}
+		switch v := ent.Val(dwarf.AttrConstValue).(type) {
+		case int64:
+			addInt(ints, uint64(v))
+		case uint64:
+			addInt(ints, v)
+		}
+	}
+}
+
+var cmpRegexps = map[string]*regexp.Regexp{
+	// ffffffff81a3b4c5:	cmpw   $0xef53,0x38(%rbx)
+	targets.AMD64: regexp.MustCompile(`\tcmp[bwlq]?\s+\$0x([0-9a-f]+),`),
+	targets.I386:  regexp.MustCompile(`\tcmp[bwl]?\s+\$0x([0-9a-f]+),`),
+	// ffff80000812b4c0:	cmp	w1, #0x5c
+	targets.ARM64: regexp.MustCompile(`\tcmp\s+[wx]\d+, #0x([0-9a-f]+)`),
+}
+
+func extractCmpImmediates(target *targets.Target, kernelObject string, re *regexp.Regexp,
+	ints map[uint64]int) error {
+	cmd := osutil.Command(target.Objdump, "-d", "--no-show-raw-insn", kernelObject)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	defer stdout.Close()
+	// Stderr is collected into a buffer, so that objdump does not block on a full stderr pipe
+	// while we are reading stdout.
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run objdump on %v: %w", kernelObject, err)
+	}
+	waited := false
+	defer func() {
+		// If we stopped reading stdout early, objdump may be blocked on a full pipe.
+		if !waited {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}
+	}()
+	s := bufio.NewScanner(stdout)
+	for s.Scan() {
+		if v, ok := parseCmpImmediate(re, s.Bytes()); ok {
+			addInt(ints, v)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("failed to read objdump output for %v: %w", kernelObject, err)
+	}
+	// Wait must be called only after all reads from stdout have completed.
+	waited = true
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to run objdump on %v: %w\n%s", kernelObject, err, stderr.Bytes())
+	}
+	return nil
+}
+
+func parseCmpImmediate(re *regexp.Regexp, ln []byte) (uint64, bool) {
+	match := re.FindSubmatch(ln)
+	if match == nil {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(string(match[1]), 16, 64)
+	return v, err == nil
+}
+
+// addInt adds v to the dictionary if it's interesting.
+// Small values (both positive and negative) are produced by the random generation anyway.
+func addInt(ints map[uint64]int, v uint64) {
+	const small = 0xff
+	if v <= small || -v <= small {
+		return
+	}
+	// Skip kernel addresses.
+	if v >= 0xffff800000000000 {
+		return
+	}
+	ints[v]++
+}
+
+func mostFrequent[T comparable](m map[T]int, n int, less func(a, b T) bool) []T {
+	res := make([]T, 0, len(m))
+	for v := range m {
+		res = append(res, v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if m[res[i]] != m[res[j]] {
+			return m[res[i]] > m[res[j]]
+		}
+		return less(res[i], res[j])
+	})
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
@@ -12,6 +12,7 @@ type Const uint64
 
 const (
 	ConstSnapshotDoorbellSize Const = 4096
+	ConstSignalBitmapChunk    Const = 65536
 	ConstMaxInputSize         Const = 4198400
 	ConstMaxOutputSize        Const = 14680064
 	ConstSnapshotShmemSize    Const = 33554432
@@ -19,6 +20,7 @@ const (
 
 var EnumNamesConst = map[Const]string{
 	ConstSnapshotDoorbellSize: "SnapshotDoorbellSize",
+	ConstSignalBitmapChunk:    "SignalBitmapChunk",
 	ConstMaxInputSize:         "MaxInputSize",
 	ConstMaxOutputSize:        "MaxOutputSize",
 	ConstSnapshotShmemSize:    "SnapshotShmemSize",
@@ -26,6 +28,7 @@ var EnumNamesConst = map[Const]string{
 
 var EnumValuesConst = map[string]Const{
 	"SnapshotDoorbellSize": ConstSnapshotDoorbellSize,
+	"SignalBitmapChunk":    ConstSignalBitmapChunk,
 	"MaxInputSize":         ConstMaxInputSize,
 	"MaxOutputSize":        ConstMaxOutputSize,
 	"SnapshotShmemSize":    ConstSnapshotShmemSize,
@@ -1961,6 +1964,7 @@ type ExecRequestRawT struct {
 	ExecOpts  *ExecOptsRawT `json:"exec_opts"`
 	Flags     RequestFlag   `json:"flags"`
 	AllSignal []int32       `json:"all_signal"`
+	TimeoutMs int32         `json:"timeout_ms"`
 }
 
 func (t *ExecRequestRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -1989,6 +1993,7 @@ func (t *ExecRequestRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffset
 	ExecRequestRawAddExecOpts(builder, execOptsOffset)
 	ExecRequestRawAddFlags(builder, t.Flags)
 	ExecRequestRawAddAllSignal(builder, allSignalOffset)
+	ExecRequestRawAddTimeoutMs(builder, t.TimeoutMs)
 	return ExecRequestRawEnd(builder)
 }
 
@@ -2004,6 +2009,7 @@ func (rcv *ExecRequestRaw) UnPackTo(t *ExecRequestRawT) {
 	for j := 0; j < allSignalLength; j++ {
 		t.AllSignal[j] = rcv.AllSignal(j)
 	}
+	t.TimeoutMs = rcv.TimeoutMs()
 }
 
 func (rcv *ExecRequestRaw) UnPack() *ExecRequestRawT {
@@ -2163,8 +2169,20 @@ func (rcv *ExecRequestRaw) MutateAllSignal(j int, n int32) bool {
 	return false
 }
 
+func (rcv *ExecRequestRaw) TimeoutMs() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *ExecRequestRaw) MutateTimeoutMs(n int32) bool {
+	return rcv._tab.MutateInt32Slot(18, n)
+}
+
 func ExecRequestRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(7)
+	builder.StartObject(8)
 }
 func ExecRequestRawAddId(builder *flatbuffers.Builder, id int64) {
 	builder.PrependInt64Slot(0, id, 0)
@@ -2193,12 +2211,168 @@ func ExecRequestRawAddAllSignal(builder *flatbuffers.Builder, allSignal flatbuff
 func ExecRequestRawStartAllSignalVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(4, numElems, 4)
 }
+func ExecRequestRawAddTimeoutMs(builder *flatbuffers.Builder, timeoutMs int32) {
+	builder.PrependInt32Slot(7, timeoutMs, 0)
+}
 func ExecRequestRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
 
+type SignalBitmapRawT struct {
+	Offsets []uint64 `json:"offsets"`
+	Bitmap  []byte   `json:"bitmap"`
+}
+
+func (t *SignalBitmapRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	if t == nil {
+		return 0
+	}
+	offsetsOffset := flatbuffers.UOffsetT(0)
+	if t.Offsets != nil {
+		offsetsLength := len(t.Offsets)
+		SignalBitmapRawStartOffsetsVector(builder, offsetsLength)
+		for j := offsetsLength - 1; j >= 0; j-- {
+			builder.PrependUint64(t.Offsets[j])
+		}
+		offsetsOffset = builder.EndVector(offsetsLength)
+	}
+	bitmapOffset := flatbuffers.UOffsetT(0)
+	if t.Bitmap != nil {
+		bitmapOffset = builder.CreateByteString(t.Bitmap)
+	}
+	SignalBitmapRawStart(builder)
+	SignalBitmapRawAddOffsets(builder, offsetsOffset)
+	SignalBitmapRawAddBitmap(builder, bitmapOffset)
+	return SignalBitmapRawEnd(builder)
+}
+
+func (rcv *SignalBitmapRaw) UnPackTo(t *SignalBitmapRawT) {
+	offsetsLength := rcv.OffsetsLength()
+	t.Offsets = make([]uint64, offsetsLength)
+	for j := 0; j < offsetsLength; j++ {
+		t.Offsets[j] = rcv.Offsets(j)
+	}
+	t.Bitmap = rcv.BitmapBytes()
+}
+
+func (rcv *SignalBitmapRaw) UnPack() *SignalBitmapRawT {
+	if rcv == nil {
+		return nil
+	}
+	t := &SignalBitmapRawT{}
+	rcv.UnPackTo(t)
+	return t
+}
+
+type SignalBitmapRaw struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsSignalBitmapRaw(buf []byte, offset flatbuffers.UOffsetT) *SignalBitmapRaw {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &SignalBitmapRaw{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func GetSizePrefixedRootAsSignalBitmapRaw(buf []byte, offset flatbuffers.UOffsetT) *SignalBitmapRaw {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &SignalBitmapRaw{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *SignalBitmapRaw) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *SignalBitmapRaw) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *SignalBitmapRaw) Offsets(j int) uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetUint64(a + flatbuffers.UOffsetT(j*8))
+	}
+	return 0
+}
+
+func (rcv *SignalBitmapRaw) OffsetsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *SignalBitmapRaw) MutateOffsets(j int, n uint64) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateUint64(a+flatbuffers.UOffsetT(j*8), n)
+	}
+	return false
+}
+
+func (rcv *SignalBitmapRaw) Bitmap(j int) byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1))
+	}
+	return 0
+}
+
+func (rcv *SignalBitmapRaw) BitmapLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *SignalBitmapRaw) BitmapBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *SignalBitmapRaw) MutateBitmap(j int, n byte) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), n)
+	}
+	return false
+}
+
+func SignalBitmapRawStart(builder *flatbuffers.Builder) {
+	builder.StartObject(2)
+}
+func SignalBitmapRawAddOffsets(builder *flatbuffers.Builder, offsets flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(offsets), 0)
+}
+func SignalBitmapRawStartOffsetsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(8, numElems, 8)
+}
+func SignalBitmapRawAddBitmap(builder *flatbuffers.Builder, bitmap flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(bitmap), 0)
+}
+func SignalBitmapRawStartBitmapVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
+func SignalBitmapRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
 type SignalUpdateRawT struct {
-	NewMax []uint64 `json:"new_max"`
+	NewMax       []uint64          `json:"new_max"`
+	NewMaxBitmap *SignalBitmapRawT `json:"new_max_bitmap"`
 }
 
 func (t *SignalUpdateRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -2214,8 +2388,10 @@ func (t *SignalUpdateRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffse
 		}
 		newMaxOffset = builder.EndVector(newMaxLength)
 	}
+	newMaxBitmapOffset := t.NewMaxBitmap.Pack(builder)
 	SignalUpdateRawStart(builder)
 	SignalUpdateRawAddNewMax(builder, newMaxOffset)
+	SignalUpdateRawAddNewMaxBitmap(builder, newMaxBitmapOffset)
 	return SignalUpdateRawEnd(builder)
 }
 
@@ -2225,6 +2401,7 @@ func (rcv *SignalUpdateRaw) UnPackTo(t *SignalUpdateRawT) {
 	for j := 0; j < newMaxLength; j++ {
 		t.NewMax[j] = rcv.NewMax(j)
 	}
+	t.NewMaxBitmap = rcv.NewMaxBitmap(nil).UnPack()
 }
 
 func (rcv *SignalUpdateRaw) UnPack() *SignalUpdateRawT {
@@ -2289,8 +2466,21 @@ func (rcv *SignalUpdateRaw) MutateNewMax(j int, n uint64) bool {
 	return false
 }
 
+func (rcv *SignalUpdateRaw) NewMaxBitmap(obj *SignalBitmapRaw) *SignalBitmapRaw {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		x := rcv._tab.Indirect(o + rcv._tab.Pos)
+		if obj == nil {
+			obj = new(SignalBitmapRaw)
+		}
+		obj.Init(rcv._tab.Bytes, x)
+		return obj
+	}
+	return nil
+}
+
 func SignalUpdateRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(1)
+	builder.StartObject(2)
 }
 func SignalUpdateRawAddNewMax(builder *flatbuffers.Builder, newMax flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(newMax), 0)
@@ -2298,6 +2488,9 @@ func SignalUpdateRawAddNewMax(builder *flatbuffers.Builder, newMax flatbuffers.U
 func SignalUpdateRawStartNewMaxVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(8, numElems, 8)
 }
+func SignalUpdateRawAddNewMaxBitmap(builder *flatbuffers.Builder, newMaxBitmap flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(newMaxBitmap), 0)
+}
 func SignalUpdateRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -2546,11 +2739,12 @@ func ExecutingMessageRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 }
 
 type CallInfoRawT struct {
-	Flags  CallFlag          `json:"flags"`
-	Error  int32             `json:"error"`
-	Signal []uint64          `json:"signal"`
-	Cover  []uint64          `json:"cover"`
-	Comps  []*ComparisonRawT `json:"comps"`
+	Flags     CallFlag          `json:"flags"`
+	Error     int32             `json:"error"`
+	Signal    []uint64          `json:"signal"`
+	Cover     []uint64          `json:"cover"`
+	Comps     []*ComparisonRawT `json:"comps"`
+	ElapsedMs uint32            `json:"elapsed_ms"`
 }
 
 func (t *CallInfoRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -2590,6 +2784,7 @@ func (t *CallInfoRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	CallInfoRawAddSignal(builder, signalOffset)
 	CallInfoRawAddCover(builder, coverOffset)
 	CallInfoRawAddComps(builder, compsOffset)
+	CallInfoRawAddElapsedMs(builder, t.ElapsedMs)
 	return CallInfoRawEnd(builder)
 }
 
@@ -2613,6 +2808,7 @@ func (rcv *CallInfoRaw) UnPackTo(t *CallInfoRawT) {
 		rcv.Comps(&x, j)
 		t.Comps[j] = x.UnPack()
 	}
+	t.ElapsedMs = rcv.ElapsedMs()
 }
 
 func (rcv *CallInfoRaw) UnPack() *CallInfoRawT {
@@ -2746,8 +2942,20 @@ func (rcv *CallInfoRaw) CompsLength() int {
 	return 0
 }
 
+func (rcv *CallInfoRaw) ElapsedMs() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *CallInfoRaw) MutateElapsedMs(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(14, n)
+}
+
 func CallInfoRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(5)
+	builder.StartObject(6)
 }
 func CallInfoRawAddFlags(builder *flatbuffers.Builder, flags CallFlag) {
 	builder.PrependByteSlot(0, byte(flags), 0)
@@ -2773,6 +2981,9 @@ func CallInfoRawAddComps(builder *flatbuffers.Builder, comps flatbuffers.UOffset
 func CallInfoRawStartCompsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(32, numElems, 8)
 }
+func CallInfoRawAddElapsedMs(builder *flatbuffers.Builder, elapsedMs uint32) {
+	builder.PrependUint32Slot(5, elapsedMs, 0)
+}
 func CallInfoRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
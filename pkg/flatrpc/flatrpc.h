@@ -61,6 +61,10 @@ struct ExecRequestRaw;
 struct ExecRequestRawBuilder;
 struct ExecRequestRawT;
 
+struct SignalBitmapRaw;
+struct SignalBitmapRawBuilder;
+struct SignalBitmapRawT;
+
 struct SignalUpdateRaw;
 struct SignalUpdateRawBuilder;
 struct SignalUpdateRawT;
@@ -109,6 +113,7 @@ struct SnapshotRequestT;
 
 enum class Const : uint64_t {
   SnapshotDoorbellSize = 4096ULL,
+  SignalBitmapChunk = 65536ULL,
   MaxInputSize = 4198400ULL,
   MaxOutputSize = 14680064ULL,
   SnapshotShmemSize = 33554432ULL,
@@ -116,9 +121,10 @@ enum class Const : uint64_t {
   MAX = SnapshotShmemSize
 };
 
-inline const Const (&EnumValuesConst())[4] {
+inline const Const (&EnumValuesConst())[5] {
   static const Const values[] = {
     Const::SnapshotDoorbellSize,
+    Const::SignalBitmapChunk,
     Const::MaxInputSize,
     Const::MaxOutputSize,
     Const::SnapshotShmemSize
@@ -129,6 +135,7 @@ inline const Const (&EnumValuesConst())[4] {
 inline const char *EnumNameConst(Const e) {
   switch (e) {
     case Const::SnapshotDoorbellSize: return "SnapshotDoorbellSize";
+    case Const::SignalBitmapChunk: return "SignalBitmapChunk";
     case Const::MaxInputSize: return "MaxInputSize";
     case Const::MaxOutputSize: return "MaxOutputSize";
     case Const::SnapshotShmemSize: return "SnapshotShmemSize";
@@ -1849,6 +1856,7 @@ struct ExecRequestRawT : public flatbuffers::NativeTable {
   std::unique_ptr<rpc::ExecOptsRaw> exec_opts{};
   rpc::RequestFlag flags = static_cast<rpc::RequestFlag>(0);
   std::vector<int32_t> all_signal{};
+  int32_t timeout_ms = 0;
   ExecRequestRawT() = default;
   ExecRequestRawT(const ExecRequestRawT &o);
   ExecRequestRawT(ExecRequestRawT&&) FLATBUFFERS_NOEXCEPT = default;
@@ -1865,7 +1873,8 @@ struct ExecRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_DATA = 10,
     VT_EXEC_OPTS = 12,
     VT_FLAGS = 14,
-    VT_ALL_SIGNAL = 16
+    VT_ALL_SIGNAL = 16,
+    VT_TIMEOUT_MS = 18
   };
   int64_t id() const {
     return GetField<int64_t>(VT_ID, 0);
@@ -1888,6 +1897,9 @@ struct ExecRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::Vector<int32_t> *all_signal() const {
     return GetPointer<const flatbuffers::Vector<int32_t> *>(VT_ALL_SIGNAL);
   }
+  int32_t timeout_ms() const {
+    return GetField<int32_t>(VT_TIMEOUT_MS, 0);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<int64_t>(verifier, VT_ID, 8) &&
@@ -1899,6 +1911,7 @@ struct ExecRequestRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            VerifyField<uint64_t>(verifier, VT_FLAGS, 8) &&
            VerifyOffset(verifier, VT_ALL_SIGNAL) &&
            verifier.VerifyVector(all_signal()) &&
+           VerifyField<int32_t>(verifier, VT_TIMEOUT_MS, 4) &&
            verifier.EndTable();
   }
   ExecRequestRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -1931,6 +1944,9 @@ struct ExecRequestRawBuilder {
   void add_all_signal(flatbuffers::Offset<flatbuffers::Vector<int32_t>> all_signal) {
     fbb_.AddOffset(ExecRequestRaw::VT_ALL_SIGNAL, all_signal);
   }
+  void add_timeout_ms(int32_t timeout_ms) {
+    fbb_.AddElement<int32_t>(ExecRequestRaw::VT_TIMEOUT_MS, timeout_ms, 0);
+  }
   explicit ExecRequestRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -1950,12 +1966,14 @@ inline flatbuffers::Offset<ExecRequestRaw> CreateExecRequestRaw(
     flatbuffers::Offset<flatbuffers::Vector<uint8_t>> data = 0,
     const rpc::ExecOptsRaw *exec_opts = nullptr,
     rpc::RequestFlag flags = static_cast<rpc::RequestFlag>(0),
-    flatbuffers::Offset<flatbuffers::Vector<int32_t>> all_signal = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<int32_t>> all_signal = 0,
+    int32_t timeout_ms = 0) {
   ExecRequestRawBuilder builder_(_fbb);
   builder_.add_flags(flags);
   builder_.add_avoid(avoid);
   builder_.add_type(type);
   builder_.add_id(id);
+  builder_.add_timeout_ms(timeout_ms);
   builder_.add_all_signal(all_signal);
   builder_.add_exec_opts(exec_opts);
   builder_.add_data(data);
@@ -1970,7 +1988,8 @@ inline flatbuffers::Offset<ExecRequestRaw> CreateExecRequestRawDirect(
     const std::vector<uint8_t> *data = nullptr,
     const rpc::ExecOptsRaw *exec_opts = nullptr,
     rpc::RequestFlag flags = static_cast<rpc::RequestFlag>(0),
-    const std::vector<int32_t> *all_signal = nullptr) {
+    const std::vector<int32_t> *all_signal = nullptr,
+    int32_t timeout_ms = 0) {
   auto data__ = data ? _fbb.CreateVector<uint8_t>(*data) : 0;
   auto all_signal__ = all_signal ? _fbb.CreateVector<int32_t>(*all_signal) : 0;
   return rpc::CreateExecRequestRaw(
@@ -1981,29 +2000,118 @@ inline flatbuffers::Offset<ExecRequestRaw> CreateExecRequestRawDirect(
       data__,
       exec_opts,
       flags,
-      all_signal__);
+      all_signal__,
+      timeout_ms);
 }
 
 flatbuffers::Offset<ExecRequestRaw> CreateExecRequestRaw(flatbuffers::FlatBufferBuilder &_fbb, const ExecRequestRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
 
+struct SignalBitmapRawT : public flatbuffers::NativeTable {
+  typedef SignalBitmapRaw TableType;
+  std::vector<uint64_t> offsets{};
+  std::vector<uint8_t> bitmap{};
+};
+
+struct SignalBitmapRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
+  typedef SignalBitmapRawT NativeTableType;
+  typedef SignalBitmapRawBuilder Builder;
+  enum FlatBuffersVTableOffset FLATBUFFERS_VTABLE_UNDERLYING_TYPE {
+    VT_OFFSETS = 4,
+    VT_BITMAP = 6
+  };
+  const flatbuffers::Vector<uint64_t> *offsets() const {
+    return GetPointer<const flatbuffers::Vector<uint64_t> *>(VT_OFFSETS);
+  }
+  const flatbuffers::Vector<uint8_t> *bitmap() const {
+    return GetPointer<const flatbuffers::Vector<uint8_t> *>(VT_BITMAP);
+  }
+  bool Verify(flatbuffers::Verifier &verifier) const {
+    return VerifyTableStart(verifier) &&
+           VerifyOffset(verifier, VT_OFFSETS) &&
+           verifier.VerifyVector(offsets()) &&
+           VerifyOffset(verifier, VT_BITMAP) &&
+           verifier.VerifyVector(bitmap()) &&
+           verifier.EndTable();
+  }
+  SignalBitmapRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
+  void UnPackTo(SignalBitmapRawT *_o, const flatbuffers::resolver_function_t *_resolver = nullptr) const;
+  static flatbuffers::Offset<SignalBitmapRaw> Pack(flatbuffers::FlatBufferBuilder &_fbb, const SignalBitmapRawT* _o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
+};
+
+struct SignalBitmapRawBuilder {
+  typedef SignalBitmapRaw Table;
+  flatbuffers::FlatBufferBuilder &fbb_;
+  flatbuffers::uoffset_t start_;
+  void add_offsets(flatbuffers::Offset<flatbuffers::Vector<uint64_t>> offsets) {
+    fbb_.AddOffset(SignalBitmapRaw::VT_OFFSETS, offsets);
+  }
+  void add_bitmap(flatbuffers::Offset<flatbuffers::Vector<uint8_t>> bitmap) {
+    fbb_.AddOffset(SignalBitmapRaw::VT_BITMAP, bitmap);
+  }
+  explicit SignalBitmapRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
+        : fbb_(_fbb) {
+    start_ = fbb_.StartTable();
+  }
+  flatbuffers::Offset<SignalBitmapRaw> Finish() {
+    const auto end = fbb_.EndTable(start_);
+    auto o = flatbuffers::Offset<SignalBitmapRaw>(end);
+    return o;
+  }
+};
+
+inline flatbuffers::Offset<SignalBitmapRaw> CreateSignalBitmapRaw(
+    flatbuffers::FlatBufferBuilder &_fbb,
+    flatbuffers::Offset<flatbuffers::Vector<uint64_t>> offsets = 0,
+    flatbuffers::Offset<flatbuffers::Vector<uint8_t>> bitmap = 0) {
+  SignalBitmapRawBuilder builder_(_fbb);
+  builder_.add_bitmap(bitmap);
+  builder_.add_offsets(offsets);
+  return builder_.Finish();
+}
+
+inline flatbuffers::Offset<SignalBitmapRaw> CreateSignalBitmapRawDirect(
+    flatbuffers::FlatBufferBuilder &_fbb,
+    const std::vector<uint64_t> *offsets = nullptr,
+    const std::vector<uint8_t> *bitmap = nullptr) {
+  auto offsets__ = offsets ? _fbb.CreateVector<uint64_t>(*offsets) : 0;
+  auto bitmap__ = bitmap ? _fbb.CreateVector<uint8_t>(*bitmap) : 0;
+  return rpc::CreateSignalBitmapRaw(
+      _fbb,
+      offsets__,
+      bitmap__);
+}
+
+flatbuffers::Offset<SignalBitmapRaw> CreateSignalBitmapRaw(flatbuffers::FlatBufferBuilder &_fbb, const SignalBitmapRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
+
 struct SignalUpdateRawT : public flatbuffers::NativeTable {
   typedef SignalUpdateRaw TableType;
   std::vector<uint64_t> new_max{};
+  std::unique_ptr<rpc::SignalBitmapRawT> new_max_bitmap{};
+  SignalUpdateRawT() = default;
+  SignalUpdateRawT(const SignalUpdateRawT &o);
+  SignalUpdateRawT(SignalUpdateRawT&&) FLATBUFFERS_NOEXCEPT = default;
+  SignalUpdateRawT &operator=(SignalUpdateRawT o) FLATBUFFERS_NOEXCEPT;
 };
 
 struct SignalUpdateRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   typedef SignalUpdateRawT NativeTableType;
   typedef SignalUpdateRawBuilder Builder;
   enum FlatBuffersVTableOffset FLATBUFFERS_VTABLE_UNDERLYING_TYPE {
-    VT_NEW_MAX = 4
+    VT_NEW_MAX = 4,
+    VT_NEW_MAX_BITMAP = 6
   };
   const flatbuffers::Vector<uint64_t> *new_max() const {
     return GetPointer<const flatbuffers::Vector<uint64_t> *>(VT_NEW_MAX);
   }
+  const rpc::SignalBitmapRaw *new_max_bitmap() const {
+    return GetPointer<const rpc::SignalBitmapRaw *>(VT_NEW_MAX_BITMAP);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyOffset(verifier, VT_NEW_MAX) &&
            verifier.VerifyVector(new_max()) &&
+           VerifyOffset(verifier, VT_NEW_MAX_BITMAP) &&
+           verifier.VerifyTable(new_max_bitmap()) &&
            verifier.EndTable();
   }
   SignalUpdateRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -2018,6 +2126,9 @@ struct SignalUpdateRawBuilder {
   void add_new_max(flatbuffers::Offset<flatbuffers::Vector<uint64_t>> new_max) {
     fbb_.AddOffset(SignalUpdateRaw::VT_NEW_MAX, new_max);
   }
+  void add_new_max_bitmap(flatbuffers::Offset<rpc::SignalBitmapRaw> new_max_bitmap) {
+    fbb_.AddOffset(SignalUpdateRaw::VT_NEW_MAX_BITMAP, new_max_bitmap);
+  }
   explicit SignalUpdateRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -2031,19 +2142,23 @@ struct SignalUpdateRawBuilder {
 
 inline flatbuffers::Offset<SignalUpdateRaw> CreateSignalUpdateRaw(
     flatbuffers::FlatBufferBuilder &_fbb,
-    flatbuffers::Offset<flatbuffers::Vector<uint64_t>> new_max = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<uint64_t>> new_max = 0,
+    flatbuffers::Offset<rpc::SignalBitmapRaw> new_max_bitmap = 0) {
   SignalUpdateRawBuilder builder_(_fbb);
+  builder_.add_new_max_bitmap(new_max_bitmap);
   builder_.add_new_max(new_max);
   return builder_.Finish();
 }
 
 inline flatbuffers::Offset<SignalUpdateRaw> CreateSignalUpdateRawDirect(
     flatbuffers::FlatBufferBuilder &_fbb,
-    const std::vector<uint64_t> *new_max = nullptr) {
+    const std::vector<uint64_t> *new_max = nullptr,
+    flatbuffers::Offset<rpc::SignalBitmapRaw> new_max_bitmap = 0) {
   auto new_max__ = new_max ? _fbb.CreateVector<uint64_t>(*new_max) : 0;
   return rpc::CreateSignalUpdateRaw(
       _fbb,
-      new_max__);
+      new_max__,
+      new_max_bitmap);
 }
 
 flatbuffers::Offset<SignalUpdateRaw> CreateSignalUpdateRaw(flatbuffers::FlatBufferBuilder &_fbb, const SignalUpdateRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -2218,6 +2333,7 @@ struct CallInfoRawT : public flatbuffers::NativeTable {
   std::vector<uint64_t> signal{};
   std::vector<uint64_t> cover{};
   std::vector<rpc::ComparisonRaw> comps{};
+  uint32_t elapsed_ms = 0;
 };
 
 struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -2228,7 +2344,8 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_ERROR = 6,
     VT_SIGNAL = 8,
     VT_COVER = 10,
-    VT_COMPS = 12
+    VT_COMPS = 12,
+    VT_ELAPSED_MS = 14
   };
   rpc::CallFlag flags() const {
     return static_cast<rpc::CallFlag>(GetField<uint8_t>(VT_FLAGS, 0));
@@ -2245,6 +2362,9 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::Vector<const rpc::ComparisonRaw *> *comps() const {
     return GetPointer<const flatbuffers::Vector<const rpc::ComparisonRaw *> *>(VT_COMPS);
   }
+  uint32_t elapsed_ms() const {
+    return GetField<uint32_t>(VT_ELAPSED_MS, 0);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<uint8_t>(verifier, VT_FLAGS, 1) &&
@@ -2255,6 +2375,7 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            verifier.VerifyVector(cover()) &&
            VerifyOffset(verifier, VT_COMPS) &&
            verifier.VerifyVector(comps()) &&
+           VerifyField<uint32_t>(verifier, VT_ELAPSED_MS, 4) &&
            verifier.EndTable();
   }
   CallInfoRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -2281,6 +2402,9 @@ struct CallInfoRawBuilder {
   void add_comps(flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps) {
     fbb_.AddOffset(CallInfoRaw::VT_COMPS, comps);
   }
+  void add_elapsed_ms(uint32_t elapsed_ms) {
+    fbb_.AddElement<uint32_t>(CallInfoRaw::VT_ELAPSED_MS, elapsed_ms, 0);
+  }
   explicit CallInfoRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -2298,8 +2422,10 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(
     int32_t error = 0,
     flatbuffers::Offset<flatbuffers::Vector<uint64_t>> signal = 0,
     flatbuffers::Offset<flatbuffers::Vector<uint64_t>> cover = 0,
-    flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps = 0,
+    uint32_t elapsed_ms = 0) {
   CallInfoRawBuilder builder_(_fbb);
+  builder_.add_elapsed_ms(elapsed_ms);
   builder_.add_comps(comps);
   builder_.add_cover(cover);
   builder_.add_signal(signal);
@@ -2314,7 +2440,8 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRawDirect(
     int32_t error = 0,
     const std::vector<uint64_t> *signal = nullptr,
     const std::vector<uint64_t> *cover = nullptr,
-    const std::vector<rpc::ComparisonRaw> *comps = nullptr) {
+    const std::vector<rpc::ComparisonRaw> *comps = nullptr,
+    uint32_t elapsed_ms = 0) {
   auto signal__ = signal ? _fbb.CreateVector<uint64_t>(*signal) : 0;
   auto cover__ = cover ? _fbb.CreateVector<uint64_t>(*cover) : 0;
   auto comps__ = comps ? _fbb.CreateVectorOfStructs<rpc::ComparisonRaw>(*comps) : 0;
@@ -2324,7 +2451,8 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRawDirect(
       error,
       signal__,
       cover__,
-      comps__);
+      comps__,
+      elapsed_ms);
 }
 
 flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(flatbuffers::FlatBufferBuilder &_fbb, const CallInfoRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -3322,7 +3450,8 @@ inline ExecRequestRawT::ExecRequestRawT(const ExecRequestRawT &o)
         data(o.data),
         exec_opts((o.exec_opts) ? new rpc::ExecOptsRaw(*o.exec_opts) : nullptr),
         flags(o.flags),
-        all_signal(o.all_signal) {
+        all_signal(o.all_signal),
+        timeout_ms(o.timeout_ms) {
 }
 
 inline ExecRequestRawT &ExecRequestRawT::operator=(ExecRequestRawT o) FLATBUFFERS_NOEXCEPT {
@@ -3333,6 +3462,7 @@ inline ExecRequestRawT &ExecRequestRawT::operator=(ExecRequestRawT o) FLATBUFFER
   std::swap(exec_opts, o.exec_opts);
   std::swap(flags, o.flags);
   std::swap(all_signal, o.all_signal);
+  std::swap(timeout_ms, o.timeout_ms);
   return *this;
 }
 
@@ -3352,6 +3482,7 @@ inline void ExecRequestRaw::UnPackTo(ExecRequestRawT *_o, const flatbuffers::res
   { auto _e = exec_opts(); if (_e) _o->exec_opts = std::unique_ptr<rpc::ExecOptsRaw>(new rpc::ExecOptsRaw(*_e)); }
   { auto _e = flags(); _o->flags = _e; }
   { auto _e = all_signal(); if (_e) { _o->all_signal.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->all_signal[_i] = _e->Get(_i); } } }
+  { auto _e = timeout_ms(); _o->timeout_ms = _e; }
 }
 
 inline flatbuffers::Offset<ExecRequestRaw> ExecRequestRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const ExecRequestRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3369,6 +3500,7 @@ inline flatbuffers::Offset<ExecRequestRaw> CreateExecRequestRaw(flatbuffers::Fla
   auto _exec_opts = _o->exec_opts ? _o->exec_opts.get() : nullptr;
   auto _flags = _o->flags;
   auto _all_signal = _o->all_signal.size() ? _fbb.CreateVector(_o->all_signal) : 0;
+  auto _timeout_ms = _o->timeout_ms;
   return rpc::CreateExecRequestRaw(
       _fbb,
       _id,
@@ -3377,7 +3509,48 @@ inline flatbuffers::Offset<ExecRequestRaw> CreateExecRequestRaw(flatbuffers::Fla
       _data,
       _exec_opts,
       _flags,
-      _all_signal);
+      _all_signal,
+      _timeout_ms);
+}
+
+inline SignalBitmapRawT *SignalBitmapRaw::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
+  auto _o = std::unique_ptr<SignalBitmapRawT>(new SignalBitmapRawT());
+  UnPackTo(_o.get(), _resolver);
+  return _o.release();
+}
+
+inline void SignalBitmapRaw::UnPackTo(SignalBitmapRawT *_o, const flatbuffers::resolver_function_t *_resolver) const {
+  (void)_o;
+  (void)_resolver;
+  { auto _e = offsets(); if (_e) { _o->offsets.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->offsets[_i] = _e->Get(_i); } } }
+  { auto _e = bitmap(); if (_e) { _o->bitmap.resize(_e->size()); std::copy(_e->begin(), _e->end(), _o->bitmap.begin()); } }
+}
+
+inline flatbuffers::Offset<SignalBitmapRaw> SignalBitmapRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const SignalBitmapRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
+  return CreateSignalBitmapRaw(_fbb, _o, _rehasher);
+}
+
+inline flatbuffers::Offset<SignalBitmapRaw> CreateSignalBitmapRaw(flatbuffers::FlatBufferBuilder &_fbb, const SignalBitmapRawT *_o, const flatbuffers::rehasher_function_t *_rehasher) {
+  (void)_rehasher;
+  (void)_o;
+  struct _VectorArgs { flatbuffers::FlatBufferBuilder *__fbb; const SignalBitmapRawT* __o; const flatbuffers::rehasher_function_t *__rehasher; } _va = { &_fbb, _o, _rehasher}; (void)_va;
+  auto _offsets = _o->offsets.size() ? _fbb.CreateVector(_o->offsets) : 0;
+  auto _bitmap = _o->bitmap.size() ? _fbb.CreateVector(_o->bitmap) : 0;
+  return rpc::CreateSignalBitmapRaw(
+      _fbb,
+      _offsets,
+      _bitmap);
+}
+
+inline SignalUpdateRawT::SignalUpdateRawT(const SignalUpdateRawT &o)
+      : new_max(o.new_max),
+        new_max_bitmap((o.new_max_bitmap) ? new rpc::SignalBitmapRawT(*o.new_max_bitmap) : nullptr) {
+}
+
+inline SignalUpdateRawT &SignalUpdateRawT::operator=(SignalUpdateRawT o) FLATBUFFERS_NOEXCEPT {
+  std::swap(new_max, o.new_max);
+  std::swap(new_max_bitmap, o.new_max_bitmap);
+  return *this;
 }
 
 inline SignalUpdateRawT *SignalUpdateRaw::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
@@ -3390,6 +3563,7 @@ inline void SignalUpdateRaw::UnPackTo(SignalUpdateRawT *_o, const flatbuffers::r
   (void)_o;
   (void)_resolver;
   { auto _e = new_max(); if (_e) { _o->new_max.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->new_max[_i] = _e->Get(_i); } } }
+  { auto _e = new_max_bitmap(); if (_e) _o->new_max_bitmap = std::unique_ptr<rpc::SignalBitmapRawT>(_e->UnPack(_resolver)); }
 }
 
 inline flatbuffers::Offset<SignalUpdateRaw> SignalUpdateRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const SignalUpdateRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3401,9 +3575,11 @@ inline flatbuffers::Offset<SignalUpdateRaw> CreateSignalUpdateRaw(flatbuffers::F
   (void)_o;
   struct _VectorArgs { flatbuffers::FlatBufferBuilder *__fbb; const SignalUpdateRawT* __o; const flatbuffers::rehasher_function_t *__rehasher; } _va = { &_fbb, _o, _rehasher}; (void)_va;
   auto _new_max = _o->new_max.size() ? _fbb.CreateVector(_o->new_max) : 0;
+  auto _new_max_bitmap = _o->new_max_bitmap ? CreateSignalBitmapRaw(_fbb, _o->new_max_bitmap.get(), _rehasher) : 0;
   return rpc::CreateSignalUpdateRaw(
       _fbb,
-      _new_max);
+      _new_max,
+      _new_max_bitmap);
 }
 
 inline CorpusTriagedRawT *CorpusTriagedRaw::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
@@ -3501,6 +3677,7 @@ inline void CallInfoRaw::UnPackTo(CallInfoRawT *_o, const flatbuffers::resolver_
   { auto _e = signal(); if (_e) { _o->signal.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->signal[_i] = _e->Get(_i); } } }
   { auto _e = cover(); if (_e) { _o->cover.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->cover[_i] = _e->Get(_i); } } }
   { auto _e = comps(); if (_e) { _o->comps.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->comps[_i] = *_e->Get(_i); } } }
+  { auto _e = elapsed_ms(); _o->elapsed_ms = _e; }
 }
 
 inline flatbuffers::Offset<CallInfoRaw> CallInfoRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const CallInfoRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3516,13 +3693,15 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(flatbuffers::FlatBuffe
   auto _signal = _o->signal.size() ? _fbb.CreateVector(_o->signal) : 0;
   auto _cover = _o->cover.size() ? _fbb.CreateVector(_o->cover) : 0;
   auto _comps = _o->comps.size() ? _fbb.CreateVectorOfStructs(_o->comps) : 0;
+  auto _elapsed_ms = _o->elapsed_ms;
   return rpc::CreateCallInfoRaw(
       _fbb,
       _flags,
       _error,
       _signal,
       _cover,
-      _comps);
+      _comps,
+      _elapsed_ms);
 }
 
 inline ProgInfoRawT::ProgInfoRawT(const ProgInfoRawT &o)
@@ -33,6 +33,7 @@ type ExecutorMessage = ExecutorMessageRawT
 type ExecRequest = ExecRequestRawT
 type StateRequest = StateRequestRawT
 type SignalUpdate = SignalUpdateRawT
+type SignalBitmap = SignalBitmapRawT
 type CorpusTriaged = CorpusTriagedRawT
 type ExecutingMessage = ExecutingMessageRawT
 type CallInfo = CallInfoRawT
@@ -41,6 +42,8 @@ type ExecOpts = ExecOptsRawT
 type ProgInfo = ProgInfoRawT
 type ExecResult = ExecResultRawT
 type StateResult = StateResultRawT
+type PeerHello = PeerHelloRawT
+type PeerSync = PeerSyncRawT
 
 func init() {
 	var req ExecRequest
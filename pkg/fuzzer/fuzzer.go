@@ -33,6 +33,7 @@ type Fuzzer struct {
 	target       *prog.Target
 	hintsLimiter prog.HintsLimiter
 	runningJobs  map[jobIntrospector]struct{}
+	policy       SchedulingPolicy
 
 	ct           *prog.ChoiceTable
 	ctProgs      int
@@ -58,6 +59,7 @@ func NewFuzzer(ctx context.Context, cfg *Config, rnd *rand.Rand,
 		rnd:         rnd,
 		target:      target,
 		runningJobs: map[jobIntrospector]struct{}{},
+		policy:      newSchedulingPolicy(cfg),
 
 		// We're okay to lose some of the messages -- if we are already
 		// regenerating the table, we don't want to repeat it right away.
@@ -87,22 +89,19 @@ func newExecQueues(fuzzer *Fuzzer) execQueues {
 		triageQueue:          queue.DynamicOrder(),
 		smashQueue:           queue.Plain(),
 	}
-	// Alternate smash jobs with exec/fuzz to spread attention to the wider area.
-	skipQueue := 3
-	if fuzzer.Config.PatchTest {
-		// When we do patch fuzzing, we do not focus on finding and persisting
-		// new coverage that much, so it's reasonable to spend more time just
-		// mutating various corpus programs.
-		skipQueue = 2
-	}
 	// Sources are listed in the order, in which they will be polled.
-	ret.source = queue.Order(
+	sources := []queue.Source{
 		ret.triageCandidateQueue,
 		ret.candidateQueue,
 		ret.triageQueue,
-		queue.Alternate(ret.smashQueue, skipQueue),
-		queue.Callback(fuzzer.genFuzz),
-	)
+	}
+	if fixed, ok := fuzzer.policy.(*fixedPolicy); ok {
+		// Alternate smash jobs with exec/fuzz to spread attention to the wider area.
+		sources = append(sources, queue.Alternate(ret.smashQueue, fixed.skipSmash))
+	}
+	// Otherwise the choice between smash jobs and fuzzing is done by the scheduling policy.
+	sources = append(sources, queue.Callback(fuzzer.genFuzz))
+	ret.source = queue.Order(sources...)
 	return ret
 }
 
@@ -115,26 +114,46 @@ func (fuzzer *Fuzzer) CandidateTriageFinished() bool {
 }
 
 func (fuzzer *Fuzzer) execute(executor queue.Executor, req *queue.Request) *queue.Result {
-	return fuzzer.executeWithFlags(executor, req, 0)
+	return fuzzer.executeWithFlags(executor, req, 0, corpus.Provenance{})
 }
 
-func (fuzzer *Fuzzer) executeWithFlags(executor queue.Executor, req *queue.Request, flags ProgFlags) *queue.Result {
-	fuzzer.enqueue(executor, req, flags, 0)
+// executeWithFlags executes the request, prov is attributed to the program
+// if it gives new coverage and is added to the corpus.
+func (fuzzer *Fuzzer) executeWithFlags(executor queue.Executor, req *queue.Request, flags ProgFlags,
+	prov corpus.Provenance) *queue.Result {
+	fuzzer.enqueue(executor, req, flags, 0, prov)
+	return req.Wait(fuzzer.ctx)
+}
+
+// executeScheduled executes a request produced by a scheduling policy choice
+// and reports the outcome back to the policy.
+func (fuzzer *Fuzzer) executeScheduled(executor queue.Executor, req *queue.Request,
+	choice schedChoice) *queue.Result {
+	fuzzer.prepareScheduled(req, choice)
+	executor.Submit(req)
 	return req.Wait(fuzzer.ctx)
 }
 
-func (fuzzer *Fuzzer) prepare(req *queue.Request, flags ProgFlags, attempt int) {
+func (fuzzer *Fuzzer) prepare(req *queue.Request, flags ProgFlags, attempt int, prov corpus.Provenance) {
 	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
-		return fuzzer.processResult(req, res, flags, attempt)
+		return fuzzer.processResult(req, res, flags, attempt, prov, nil)
 	})
 }
 
-func (fuzzer *Fuzzer) enqueue(executor queue.Executor, req *queue.Request, flags ProgFlags, attempt int) {
-	fuzzer.prepare(req, flags, attempt)
+func (fuzzer *Fuzzer) prepareScheduled(req *queue.Request, choice schedChoice) {
+	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
+		return fuzzer.processResult(req, res, 0, 0, choice.provenance, &choice)
+	})
+}
+
+func (fuzzer *Fuzzer) enqueue(executor queue.Executor, req *queue.Request, flags ProgFlags, attempt int,
+	prov corpus.Provenance) {
+	fuzzer.prepare(req, flags, attempt, prov)
 	executor.Submit(req)
 }
 
-func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags ProgFlags, attempt int) bool {
+func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags ProgFlags, attempt int,
+	prov corpus.Provenance, choice *schedChoice) bool {
 	// If we are already triaging this exact prog, this is flaky coverage.
 	// Hanged programs are harmful as they consume executor procs.
 	dontTriage := flags&progInTriage > 0 || res.Status == queue.Hanged
@@ -142,7 +161,8 @@ func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags
 	// We do it before unblocking the waiting threads because
 	// it may result it concurrent modification of req.Prog.
 	var triage map[int]*triageCall
-	if req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectSignal > 0 && res.Info != nil && !dontTriage {
+	collectSignal := req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectSignal > 0
+	if collectSignal && res.Info != nil && !dontTriage {
 		for call, info := range res.Info.Calls {
 			fuzzer.triageProgCall(req.Prog, info, call, &triage)
 		}
@@ -154,11 +174,12 @@ func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags
 				queue, stat = fuzzer.triageCandidateQueue, fuzzer.statJobsTriageCandidate
 			}
 			job := &triageJob{
-				p:        req.Prog.Clone(),
-				executor: res.Executor,
-				flags:    flags,
-				queue:    queue.Append(),
-				calls:    triage,
+				p:          req.Prog.Clone(),
+				executor:   res.Executor,
+				flags:      flags,
+				provenance: prov,
+				queue:      queue.AppendBoosted(fuzzer.triageBoost(triage)),
+				calls:      triage,
 				info: &JobInfo{
 					Name: req.Prog.String(),
 					Type: "triage",
@@ -171,7 +192,15 @@ func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags
 			fuzzer.startJob(stat, job)
 		}
 	}
+	if choice != nil && collectSignal && res.Info != nil {
+		newSignal := len(triage) != 0
+		fuzzer.policy.Reward(choice.source, choice.operator, newSignal)
+		if choice.seed != nil {
+			choice.seed.RecordMutation(newSignal)
+		}
+	}
 
+	fuzzer.recordExecTime(req.Prog, res)
 	if res.Info != nil {
 		fuzzer.statExecTime.Add(int(res.Info.Elapsed / 1e6))
 		for call, info := range res.Info.Calls {
@@ -191,7 +220,7 @@ func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags
 		}
 	}
 	if len(triage) == 0 && flags&ProgFromCorpus != 0 && attempt < maxCandidateAttempts {
-		fuzzer.enqueue(fuzzer.candidateQueue, req, flags, attempt+1)
+		fuzzer.enqueue(fuzzer.candidateQueue, req, flags, attempt+1, prov)
 		return false
 	}
 	if flags&progCandidate != 0 {
@@ -214,6 +243,35 @@ type Config struct {
 	FetchRawCover  bool
 	NewInputFilter func(call string) bool
 	PatchTest      bool
+	// SchedulingPolicy is one of PolicyFixed (default) or PolicyBandit.
+	SchedulingPolicy string
+	// Dictionary is an optional set of interesting values for mutations.
+	Dictionary *prog.Dictionary
+	// HintsCache is an optional cache of comparison operands collected by hints jobs.
+	// If set, hints jobs add comparison operands to it and mutations use them.
+	HintsCache *prog.HintsCache
+	// CallModel is an optional n-gram model of call sequences.
+	// If set, it's trained on new corpus inputs and used to choose calls for generation/insertion.
+	CallModel *prog.CallModel
+}
+
+// maxTriageBoost limits how far triage of a program can move ahead in the queue,
+// otherwise programs with lots of new signal would starve all other triage jobs.
+const maxTriageBoost = 100
+
+// triageBoost moves triage of programs with rare new signal ahead in the queue.
+// All new signal elements are equal for max signal, but some of them may be already
+// hit by lots of corpus programs (e.g. if only the signal priority has changed).
+func (fuzzer *Fuzzer) triageBoost(triage map[int]*triageCall) int {
+	if fuzzer.Config.Corpus == nil {
+		return 0
+	}
+	hits := fuzzer.Config.Corpus.HitCounts()
+	rarity := 0.0
+	for _, info := range triage {
+		rarity += hits.Rarity(info.newSignal)
+	}
+	return int(min(rarity, maxTriageBoost))
 }
 
 func (fuzzer *Fuzzer) triageProgCall(p *prog.Prog, info *flatrpc.CallInfo, call int, triage *map[int]*triageCall) {
@@ -269,19 +327,35 @@ func signalPrio(p *prog.Prog, info *flatrpc.CallInfo, call int) (prio uint8) {
 }
 
 func (fuzzer *Fuzzer) genFuzz() *queue.Request {
-	// Either generate a new input or mutate an existing one.
-	mutateRate := 0.95
-	if !fuzzer.Config.Coverage {
-		// If we don't have real coverage signal, generate programs
-		// more frequently because fallback signal is weak.
-		mutateRate = 0.5
+	// Either serve a smash job request, generate a new input or mutate an existing one.
+	rnd := fuzzer.rand()
+	source := fuzzer.policy.ChooseSource(rnd, fuzzer.smashQueue.Len() != 0)
+	if source == SourceSmash {
+		if req := fuzzer.smashQueue.Next(); req != nil {
+			return req
+		}
+		// Somebody has taken the last request in the meantime.
+		source = fuzzer.policy.ChooseSource(rnd, false)
 	}
+	choice := schedChoice{source: source, operator: OperatorNone}
 	var req *queue.Request
-	rnd := fuzzer.rand()
-	if rnd.Float64() < mutateRate {
-		req = mutateProgRequest(fuzzer, rnd)
+	if source == SourceMutate {
+		choice.operator = fuzzer.policy.ChooseOperator(rnd)
+		req, choice.seed = mutateProgRequest(fuzzer, rnd, choice.operator)
+		if req != nil {
+			choice.provenance = corpus.Provenance{
+				Job:      corpus.ProvenanceMutate,
+				Operator: choice.operator.String(),
+				Parent:   choice.seed.Sig,
+			}
+		}
 	}
 	if req == nil {
+		choice = schedChoice{
+			source:     SourceGenerate,
+			operator:   OperatorNone,
+			provenance: corpus.Provenance{Job: corpus.ProvenanceGenerate},
+		}
 		req = genProgRequest(fuzzer, rnd)
 	}
 	if fuzzer.Config.Collide && rnd.Intn(3) == 0 {
@@ -290,7 +364,8 @@ func (fuzzer *Fuzzer) genFuzz() *queue.Request {
 			Stat: fuzzer.statExecCollide,
 		}
 	}
-	fuzzer.prepare(req, 0, 0)
+	fuzzer.prepareScheduled(req, choice)
+	fuzzer.setTimeout(req)
 	return req
 }
 
@@ -348,8 +423,9 @@ const (
 )
 
 type Candidate struct {
-	Prog  *prog.Prog
-	Flags ProgFlags
+	Prog       *prog.Prog
+	Flags      ProgFlags
+	Provenance corpus.Provenance
 }
 
 func (fuzzer *Fuzzer) AddCandidates(candidates []Candidate) {
@@ -361,7 +437,7 @@ func (fuzzer *Fuzzer) AddCandidates(candidates []Candidate) {
 			Stat:      fuzzer.statExecCandidate,
 			Important: true,
 		}
-		fuzzer.enqueue(fuzzer.candidateQueue, req, candidate.Flags|progCandidate, 0)
+		fuzzer.enqueue(fuzzer.candidateQueue, req, candidate.Flags|progCandidate, 0, candidate.Provenance)
 	}
 }
 
@@ -373,6 +449,9 @@ func (fuzzer *Fuzzer) rand() *rand.Rand {
 
 func (fuzzer *Fuzzer) updateChoiceTable(programs []*prog.Prog) {
 	newCt := fuzzer.target.BuildChoiceTable(programs, fuzzer.Config.EnabledCalls)
+	if fuzzer.Config.CallModel != nil {
+		newCt = newCt.WithCallModel(fuzzer.Config.CallModel)
+	}
 
 	fuzzer.ctMu.Lock()
 	defer fuzzer.ctMu.Unlock()
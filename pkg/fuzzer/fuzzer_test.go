@@ -22,6 +22,7 @@ import (
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
 	"github.com/google/syzkaller/pkg/rpcserver"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/testutil"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
@@ -90,6 +91,25 @@ func TestFuzz(t *testing.T) {
 		t.Logf("-----")
 		t.Logf("%s", p.Serialize())
 	}
+	for _, item := range fuzzer.Config.Corpus.Items() {
+		assert.NotEmpty(t, item.Provenance.Job, "corpus program without provenance:\n%s", item.Prog)
+	}
+}
+
+func TestTriageBoost(t *testing.T) {
+	raw := make([]uint64, 1000)
+	for i := range raw {
+		raw[i] = uint64(i)
+	}
+	triage := map[int]*triageCall{0: {newSignal: signal.FromRaw(raw, 0)}}
+	// Without corpus there is nothing to compare with.
+	fuzzer := &Fuzzer{Config: &Config{}}
+	assert.Equal(t, 0, fuzzer.triageBoost(triage))
+	// All elements are unique, but the boost is limited.
+	fuzzer.Config.Corpus = corpus.NewCorpus(context.Background())
+	assert.Equal(t, maxTriageBoost, fuzzer.triageBoost(triage))
+	triage[0].newSignal = signal.FromRaw(raw[:10], 0)
+	assert.Equal(t, 10, fuzzer.triageBoost(triage))
 }
 
 func BenchmarkFuzzer(b *testing.B) {
@@ -16,6 +16,7 @@ import (
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/prog"
 )
@@ -52,23 +53,24 @@ func genProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
 	}
 }
 
-func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
-	p := fuzzer.Config.Corpus.ChooseProgram(rnd)
-	if p == nil {
-		return nil
+func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand, op MutateOperator) (*queue.Request, *corpus.Item) {
+	item := fuzzer.Config.Corpus.ChooseItem(rnd)
+	if item == nil {
+		return nil, nil
 	}
-	newP := p.Clone()
-	newP.Mutate(rnd,
+	newP := item.Prog.Clone()
+	newP.MutateWithOpts(rnd,
 		prog.RecommendedCalls,
 		fuzzer.ChoiceTable(),
 		fuzzer.Config.NoMutateCalls,
 		fuzzer.Config.Corpus.Programs(),
+		fuzzer.mutateOpts(op),
 	)
 	return &queue.Request{
 		Prog:     newP,
 		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
 		Stat:     fuzzer.statExecFuzz,
-	}
+	}, item
 }
 
 // triageJob are programs for which we noticed potential new coverage during
@@ -76,11 +78,12 @@ func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
 // During triage we understand if these programs in fact give new coverage,
 // and if yes, minimize them and add to corpus.
 type triageJob struct {
-	p        *prog.Prog
-	executor queue.ExecutorID
-	flags    ProgFlags
-	fuzzer   *Fuzzer
-	queue    queue.Executor
+	p          *prog.Prog
+	executor   queue.ExecutorID
+	flags      ProgFlags
+	provenance corpus.Provenance
+	fuzzer     *Fuzzer
+	queue      queue.Executor
 	// Set of calls that gave potential new coverage.
 	calls map[int]*triageCall
 
@@ -133,7 +136,7 @@ const (
 func (job *triageJob) execute(req *queue.Request, flags ProgFlags) *queue.Result {
 	defer job.info.Execs.Add(1)
 	req.Important = true // All triage executions are important.
-	return job.fuzzer.executeWithFlags(job.queue, req, flags)
+	return job.fuzzer.executeWithFlags(job.queue, req, flags, job.provenance)
 }
 
 func (job *triageJob) run(fuzzer *Fuzzer) {
@@ -178,9 +181,11 @@ func (job *triageJob) handleCall(call int, info *triageCall) {
 		return
 	}
 	if job.flags&ProgSmashed == 0 {
+		sig := hash.String(p.Serialize())
 		job.fuzzer.startJob(job.fuzzer.statJobsSmash, &smashJob{
-			exec: job.fuzzer.smashQueue,
-			p:    p.Clone(),
+			exec:   job.fuzzer.smashQueue,
+			p:      p.Clone(),
+			parent: sig,
 			info: &JobInfo{
 				Name:  p.String(),
 				Type:  "smash",
@@ -189,9 +194,10 @@ func (job *triageJob) handleCall(call int, info *triageCall) {
 		})
 		if job.fuzzer.Config.Comparisons && call >= 0 {
 			job.fuzzer.startJob(job.fuzzer.statJobsHints, &hintsJob{
-				exec: job.fuzzer.smashQueue,
-				p:    p.Clone(),
-				call: call,
+				exec:   job.fuzzer.smashQueue,
+				p:      p.Clone(),
+				call:   call,
+				parent: sig,
 				info: &JobInfo{
 					Name:  p.String(),
 					Type:  "hints",
@@ -209,13 +215,17 @@ func (job *triageJob) handleCall(call int, info *triageCall) {
 	}
 	job.fuzzer.Logf(2, "added new input for %v to the corpus: %s", callName, p)
 	input := corpus.NewInput{
-		Prog:     p,
-		Call:     call,
-		Signal:   info.stableSignal,
-		Cover:    info.cover.Serialize(),
-		RawCover: info.rawCover,
+		Prog:       p,
+		Call:       call,
+		Signal:     info.stableSignal,
+		Cover:      info.cover.Serialize(),
+		RawCover:   info.rawCover,
+		Provenance: job.provenance,
 	}
 	job.fuzzer.Config.Corpus.Save(input)
+	if model := job.fuzzer.Config.CallModel; model != nil {
+		model.Train(p)
+	}
 }
 
 func (job *triageJob) deflake(exec func(*queue.Request, ProgFlags) *queue.Result) (stop bool) {
@@ -263,6 +273,10 @@ func (job *triageJob) deflake(exec func(*queue.Request, ProgFlags) *queue.Result
 			if info == nil || res == nil {
 				return
 			}
+			// Triage runs return all signal of the call, so they can be used to count element hits.
+			if corpus := job.fuzzer.Config.Corpus; corpus != nil {
+				corpus.HitCounts().AddExec(res.Signal)
+			}
 			if len(info.rawCover) == 0 && job.fuzzer.Config.FetchRawCover {
 				info.rawCover = res.Cover
 			}
@@ -439,9 +453,10 @@ func (job *triageJob) getInfo() *JobInfo {
 }
 
 type smashJob struct {
-	exec queue.Executor
-	p    *prog.Prog
-	info *JobInfo
+	exec   queue.Executor
+	p      *prog.Prog
+	parent string // signature of p in the corpus
+	info   *JobInfo
 }
 
 func (job *smashJob) run(fuzzer *Fuzzer) {
@@ -452,14 +467,24 @@ func (job *smashJob) run(fuzzer *Fuzzer) {
 	rnd := fuzzer.rand()
 	for i := 0; i < iters; i++ {
 		p := job.p.Clone()
-		p.Mutate(rnd, prog.RecommendedCalls,
+		op := fuzzer.policy.ChooseOperator(rnd)
+		p.MutateWithOpts(rnd, prog.RecommendedCalls,
 			fuzzer.ChoiceTable(),
 			fuzzer.Config.NoMutateCalls,
-			fuzzer.Config.Corpus.Programs())
-		result := fuzzer.execute(job.exec, &queue.Request{
+			fuzzer.Config.Corpus.Programs(),
+			fuzzer.mutateOpts(op))
+		result := fuzzer.executeScheduled(job.exec, &queue.Request{
 			Prog:     p,
 			ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
 			Stat:     fuzzer.statExecSmash,
+		}, schedChoice{
+			source:   SourceSmash,
+			operator: op,
+			provenance: corpus.Provenance{
+				Job:      corpus.ProvenanceSmash,
+				Operator: op.String(),
+				Parent:   job.parent,
+			},
 		})
 		if result.Stop() {
 			return
@@ -522,10 +547,11 @@ func (job *faultInjectionJob) run(fuzzer *Fuzzer) {
 }
 
 type hintsJob struct {
-	exec queue.Executor
-	p    *prog.Prog
-	call int
-	info *JobInfo
+	exec   queue.Executor
+	p      *prog.Prog
+	call   int
+	parent string // signature of p in the corpus
+	info   *JobInfo
 }
 
 func (job *hintsJob) run(fuzzer *Fuzzer) {
@@ -560,6 +586,9 @@ func (job *hintsJob) run(fuzzer *Fuzzer) {
 	}
 
 	job.info.Logf("stable comps: %d", comps.Len())
+	if fuzzer.Config.HintsCache != nil && comps.Len() != 0 {
+		fuzzer.Config.HintsCache.Add(p, job.call, comps)
+	}
 	fuzzer.hintsLimiter.Limit(comps)
 	job.info.Logf("stable comps (after the hints limiter): %d", comps.Len())
 
@@ -569,10 +598,17 @@ func (job *hintsJob) run(fuzzer *Fuzzer) {
 	p.MutateWithHints(job.call, comps,
 		func(p *prog.Prog) bool {
 			defer job.info.Execs.Add(1)
-			result := fuzzer.execute(job.exec, &queue.Request{
+			result := fuzzer.executeScheduled(job.exec, &queue.Request{
 				Prog:     p,
 				ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
 				Stat:     fuzzer.statExecHint,
+			}, schedChoice{
+				source:   SourceHints,
+				operator: OperatorNone,
+				provenance: corpus.Provenance{
+					Job:    corpus.ProvenanceHints,
+					Parent: job.parent,
+				},
 			})
 			return !result.Stop()
 		})
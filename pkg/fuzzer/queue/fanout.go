@@ -0,0 +1,133 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"sync"
+)
+
+// FanoutCtl executes every request from the source on several executors
+// (e.g. VM pools that run different kernels). Every executor gets its own copy of the request.
+// Once all copies are finished, the done callback is invoked with all results,
+// and the original request is finished with the first result that is not Restarted.
+type FanoutCtl struct {
+	mu     sync.Mutex
+	source Source
+	queues []*PlainQueue
+	done   func(req *Request, results []*Result)
+	// Number of unfinished copies per executor and the limits for them.
+	pending    []int
+	maxPending []int
+}
+
+// Fanout creates a fanout for len(maxPending) executors.
+// Slow executors throttle the faster ones: no new requests are taken from the source
+// while any executor has maxPending[i] unfinished copies.
+func Fanout(source Source, maxPending []int, done func(req *Request, results []*Result)) *FanoutCtl {
+	f := &FanoutCtl{
+		source:     source,
+		done:       done,
+		pending:    make([]int, len(maxPending)),
+		maxPending: maxPending,
+	}
+	for range maxPending {
+		f.queues = append(f.queues, Plain())
+	}
+	return f
+}
+
+// Source returns the source for the i-th executor.
+func (f *FanoutCtl) Source(i int) Source {
+	return Callback(func() *Request {
+		return f.next(i)
+	})
+}
+
+type fanoutState struct {
+	mu      sync.Mutex
+	req     *Request
+	results []*Result
+	pending int
+}
+
+func (f *FanoutCtl) next(idx int) *Request {
+	if req := f.queues[idx].Next(); req != nil {
+		return req
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, pending := range f.pending {
+		if pending >= f.maxPending[i] {
+			return nil
+		}
+	}
+	req := f.source.Next()
+	if req == nil {
+		return nil
+	}
+	state := &fanoutState{
+		req:     req,
+		results: make([]*Result, len(f.queues)),
+		pending: len(f.queues),
+	}
+	var ret *Request
+	for i, q := range f.queues {
+		f.pending[i]++
+		clone := fanoutClone(req)
+		clone.OnDone(func(_ *Request, res *Result) bool {
+			f.finish(state, i, res)
+			return true
+		})
+		if i == idx {
+			ret = clone
+		} else {
+			q.Submit(clone)
+		}
+	}
+	return ret
+}
+
+func (f *FanoutCtl) finish(state *fanoutState, idx int, res *Result) {
+	f.mu.Lock()
+	f.pending[idx]--
+	f.mu.Unlock()
+	state.mu.Lock()
+	state.results[idx] = res
+	state.pending--
+	last := state.pending == 0
+	state.mu.Unlock()
+	if !last {
+		return
+	}
+	if f.done != nil {
+		f.done(state.req, state.results)
+	}
+	res = state.results[0]
+	for _, r := range state.results {
+		if r.Status != Restarted {
+			res = r
+			break
+		}
+	}
+	state.req.Done(res)
+}
+
+func fanoutClone(req *Request) *Request {
+	clone := &Request{
+		Type:            req.Type,
+		ExecOpts:        req.ExecOpts,
+		BinaryFile:      req.BinaryFile,
+		GlobPattern:     req.GlobPattern,
+		ReturnAllSignal: req.ReturnAllSignal,
+		ReturnError:     req.ReturnError,
+		ReturnOutput:    req.ReturnOutput,
+		Important:       req.Important,
+		Timeout:         req.Timeout,
+		HangProne:       req.HangProne,
+	}
+	if req.Prog != nil {
+		clone.Prog = req.Prog.Clone()
+	}
+	return clone
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanout(t *testing.T) {
+	src := Plain()
+	var gotReq *Request
+	var gotResults []*Result
+	f := Fanout(src, []int{10, 10, 10}, func(req *Request, results []*Result) {
+		gotReq = req
+		gotResults = results
+	})
+	req1 := &Request{ReturnOutput: true}
+	req2 := &Request{}
+	src.Submit(req1)
+	src.Submit(req2)
+
+	copy1 := f.Source(1).Next()
+	assert.True(t, copy1.ReturnOutput)
+	assert.NotEqual(t, req1, copy1)
+	copy0 := f.Source(0).Next()
+	assert.True(t, copy0.ReturnOutput)
+	// Executor 2 gets its pending copy of req1 first.
+	copy2 := f.Source(2).Next()
+	assert.True(t, copy2.ReturnOutput)
+	assert.False(t, f.Source(2).Next().ReturnOutput)
+
+	copy2.Done(&Result{Status: Hanged})
+	copy0.Done(&Result{Status: Success})
+	assert.Nil(t, gotReq)
+	copy1.Done(&Result{Status: ExecFailure})
+	assert.Equal(t, req1, gotReq)
+	assert.Equal(t, []Status{Success, ExecFailure, Hanged},
+		[]Status{gotResults[0].Status, gotResults[1].Status, gotResults[2].Status})
+	res := req1.Wait(t.Context())
+	assert.Equal(t, Success, res.Status)
+}
+
+func TestFanoutRestarted(t *testing.T) {
+	src := Plain()
+	f := Fanout(src, []int{10, 10}, nil)
+	req := &Request{}
+	src.Submit(req)
+	f.Source(0).Next().Done(&Result{Status: Restarted})
+	f.Source(1).Next().Done(&Result{Status: Success})
+	res := req.Wait(t.Context())
+	assert.Equal(t, Success, res.Status)
+}
+
+func TestFanoutThrottle(t *testing.T) {
+	src := Callback(func() *Request {
+		return &Request{ExecOpts: flatrpc.ExecOpts{ExecFlags: flatrpc.ExecFlagCollectSignal}}
+	})
+	f := Fanout(src, []int{4, 2}, nil)
+	var reqs0 []*Request
+	for i := 0; i < 2; i++ {
+		reqs0 = append(reqs0, f.Source(0).Next())
+	}
+	// Executor 1 is too slow, so executor 0 must wait.
+	assert.Nil(t, f.Source(0).Next())
+	// Copies that are being executed are still pending.
+	req1 := f.Source(1).Next()
+	assert.Equal(t, flatrpc.ExecFlagCollectSignal, req1.ExecOpts.ExecFlags)
+	assert.Nil(t, f.Source(0).Next())
+	req1.Done(&Result{Status: Success})
+	assert.NotNil(t, f.Source(0).Next())
+	assert.Nil(t, f.Source(0).Next())
+	// Executor 0 reaches its own limit.
+	f.Source(1).Next().Done(&Result{Status: Success})
+	f.Source(1).Next().Done(&Result{Status: Success})
+	assert.NotNil(t, f.Source(0).Next())
+	assert.Nil(t, f.Source(0).Next())
+	reqs0[0].Done(&Result{Status: Success})
+	assert.NotNil(t, f.Source(0).Next())
+}
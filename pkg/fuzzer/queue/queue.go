@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/hash"
@@ -46,6 +47,13 @@ type Request struct {
 	// The restriction is soft since there can be only one executor at all or available right now.
 	Avoid []ExecutorID
 
+	// Timeout overrides the default program timeout if non-zero.
+	// It's a hint: the executor extends too short timeouts and shortens too long ones.
+	Timeout time.Duration
+	// HangProne requests are likely to hang, they are executed on a dedicated proc
+	// (if possible) so that they don't stall other requests.
+	HangProne bool
+
 	// The callback will be called on request completion in the LIFO order.
 	// If it returns false, all further processing will be stopped.
 	// It allows wrappers to intercept Done() requests.
@@ -365,12 +373,19 @@ func DynamicOrder() *DynamicOrderer {
 }
 
 func (do *DynamicOrderer) Append() Executor {
+	return do.AppendBoosted(0)
+}
+
+// AppendBoosted is like Append, but the new nested queue is ordered as if it was
+// appended boost Append() calls earlier. It allows to serve more important work
+// first, while the older work is still not starved forever.
+func (do *DynamicOrderer) AppendBoosted(boost int) Executor {
 	do.mu.Lock()
 	defer do.mu.Unlock()
 	do.currPrio++
 	return &dynamicOrdererItem{
 		parent: do,
-		prio:   do.currPrio,
+		prio:   do.currPrio - boost,
 	}
 }
 
@@ -44,6 +44,26 @@ func TestPrioQueue(t *testing.T) {
 	assert.Equal(t, req3, pq.Next())
 }
 
+func TestPrioQueueBoosted(t *testing.T) {
+	req1, req2, req3, req4 :=
+		&Request{}, &Request{}, &Request{}, &Request{}
+	pq := DynamicOrder()
+
+	pq1 := pq.Append()
+	pq2 := pq.Append()
+	pq3 := pq.AppendBoosted(5)
+	pq4 := pq.AppendBoosted(1)
+
+	pq4.Submit(req4)
+	pq2.Submit(req2)
+	pq1.Submit(req1)
+	pq3.Submit(req3)
+	assert.Equal(t, req3, pq.Next())
+	assert.Equal(t, req1, pq.Next())
+	assert.Equal(t, req2, pq.Next())
+	assert.Equal(t, req4, pq.Next())
+}
+
 func TestGlobFiles(t *testing.T) {
 	r := &Result{}
 	assert.Equal(t, r.GlobFiles(), []string(nil))
@@ -0,0 +1,358 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/prog"
+)
+
+// SchedulingPolicy decides where new fuzzing inputs come from once all triage
+// and candidate queues are drained, and which mutation operator to favour.
+// Implementations must be safe for concurrent use.
+type SchedulingPolicy interface {
+	// ChooseSource selects the source for the next input.
+	// haveSmash says if there are pending requests in the smash queue,
+	// if it's false, SourceSmash must not be returned.
+	ChooseSource(rnd *rand.Rand, haveSmash bool) SchedSource
+	// ChooseOperator selects the mutation operator for the next mutated input.
+	ChooseOperator(rnd *rand.Rand) MutateOperator
+	// Reward is called after an input produced by src/op has been executed.
+	// newSignal tells if the execution gave new max signal.
+	Reward(src SchedSource, op MutateOperator, newSignal bool)
+}
+
+const (
+	PolicyBandit = "bandit"
+	PolicyFixed  = "fixed"
+)
+
+// SchedSource identifies a source of fuzzing inputs.
+type SchedSource int
+
+const (
+	// SourceSmash is the queue of smash/hints/fault injection jobs for new corpus inputs.
+	SourceSmash SchedSource = iota
+	SourceMutate
+	SourceGenerate
+	// SourceHints is used for requests of hints jobs. They are served from the smash queue,
+	// so policies never choose it and reward it as SourceSmash, but it has separate stats
+	// since hints mutations behave very differently from random smash mutations.
+	SourceHints
+	sourceCount
+)
+
+var sourceNames = [sourceCount]string{
+	SourceSmash:    "smash",
+	SourceMutate:   "mutate",
+	SourceGenerate: "generate",
+	SourceHints:    "hints",
+}
+
+func (src SchedSource) String() string {
+	return sourceNames[src]
+}
+
+// MutateOperator identifies a mutation operator from prog.MutateOpts.
+// OperatorDefault means the stock mix of all operators (prog.DefaultMutateOpts),
+// OperatorNone is used for inputs that were not produced by mutation.
+type MutateOperator int
+
+const (
+	OperatorNone MutateOperator = iota - 1
+	OperatorDefault
+	OperatorSquash
+	OperatorSplice
+	OperatorInsert
+	OperatorMutateArg
+	OperatorRemoveCall
+	OperatorCrossover
+	operatorCount
+)
+
+var operatorNames = [operatorCount]string{
+	OperatorDefault:    "default",
+	OperatorSquash:     "squash",
+	OperatorSplice:     "splice",
+	OperatorInsert:     "insert",
+	OperatorMutateArg:  "mutate arg",
+	OperatorRemoveCall: "remove call",
+	OperatorCrossover:  "crossover",
+}
+
+func (op MutateOperator) String() string {
+	if op == OperatorNone {
+		return "none"
+	}
+	return operatorNames[op]
+}
+
+// MutateOpts returns mutation options that favour the operator.
+// The chosen operator receives as much weight as all the others together,
+// other operators are still used since a mutation usually consists of
+// several steps and not every operator is applicable to every program.
+func (op MutateOperator) MutateOpts() prog.MutateOpts {
+	opts := prog.DefaultMutateOpts
+	total := opts.SquashWeight + opts.SpliceWeight + opts.InsertWeight +
+		opts.MutateArgWeight + opts.RemoveCallWeight + opts.CrossoverWeight
+	switch op {
+	case OperatorSquash:
+		opts.SquashWeight = total
+	case OperatorSplice:
+		opts.SpliceWeight = total
+	case OperatorInsert:
+		opts.InsertWeight = total
+	case OperatorMutateArg:
+		opts.MutateArgWeight = total
+	case OperatorRemoveCall:
+		opts.RemoveCallWeight = total
+	case OperatorCrossover:
+		opts.CrossoverWeight = total
+	}
+	return opts
+}
+
+func (fuzzer *Fuzzer) mutateOpts(op MutateOperator) prog.MutateOpts {
+	opts := op.MutateOpts()
+	opts.Dictionary = fuzzer.Config.Dictionary
+	opts.HintsCache = fuzzer.Config.HintsCache
+	return opts
+}
+
+// schedChoice records what the scheduling policy has chosen for a request.
+type schedChoice struct {
+	source   SchedSource
+	operator MutateOperator
+	seed     *corpus.Item // the mutated corpus item, if any
+	// Provenance of the program if it's added to the corpus.
+	provenance corpus.Provenance
+}
+
+func newSchedulingPolicy(cfg *Config) SchedulingPolicy {
+	switch cfg.SchedulingPolicy {
+	case "", PolicyFixed:
+		return newFixedPolicy(cfg)
+	case PolicyBandit:
+		return newBanditPolicy()
+	default:
+		panic(fmt.Sprintf("unknown scheduling policy %q", cfg.SchedulingPolicy))
+	}
+}
+
+// fixedPolicy is the original hard-coded schedule: smash jobs are alternated with
+// fuzzing, and fuzzing mutates corpus programs with a fixed probability.
+// The alternation is done by the smash queue source (see newExecQueues),
+// so ChooseSource is called only when it's time to fuzz and never returns SourceSmash.
+type fixedPolicy struct {
+	stats      schedStats
+	skipSmash  int
+	mutateRate float64
+}
+
+func newFixedPolicy(cfg *Config) *fixedPolicy {
+	// Alternate smash jobs with exec/fuzz to spread attention to the wider area.
+	skipSmash := 3
+	if cfg.PatchTest {
+		// When we do patch fuzzing, we do not focus on finding and persisting
+		// new coverage that much, so it's reasonable to spend more time just
+		// mutating various corpus programs.
+		skipSmash = 2
+	}
+	mutateRate := 0.95
+	if !cfg.Coverage {
+		// If we don't have real coverage signal, generate programs
+		// more frequently because fallback signal is weak.
+		mutateRate = 0.5
+	}
+	return &fixedPolicy{
+		stats:      newSchedStats(),
+		skipSmash:  skipSmash,
+		mutateRate: mutateRate,
+	}
+}
+
+func (fp *fixedPolicy) ChooseSource(rnd *rand.Rand, haveSmash bool) SchedSource {
+	if rnd.Float64() < fp.mutateRate {
+		return SourceMutate
+	}
+	return SourceGenerate
+}
+
+func (fp *fixedPolicy) ChooseOperator(rnd *rand.Rand) MutateOperator {
+	return OperatorDefault
+}
+
+func (fp *fixedPolicy) Reward(src SchedSource, op MutateOperator, newSignal bool) {
+	fp.stats.record(src, op, newSignal)
+}
+
+// banditPolicy learns online which sources and mutation operators give new signal
+// using Thompson sampling over Bernoulli rewards (an execution either gives
+// new signal or not). Since the fuzzing process is not stationary (e.g. smashing
+// is very productive right after corpus growth), old observations are discounted.
+type banditPolicy struct {
+	stats     schedStats
+	mu        sync.Mutex
+	sources   bandit
+	operators bandit
+}
+
+const (
+	// Probability of choosing a random arm regardless of the learned statistics.
+	// Besides exploration, it guarantees that no source is starved completely
+	// (e.g. smash jobs wait for execution of their requests).
+	banditExplore = 0.05
+	// Discount applied to all arm statistics on every observation.
+	banditDiscount = 1 - 1e-5
+)
+
+func newBanditPolicy() *banditPolicy {
+	return &banditPolicy{
+		stats:     newSchedStats(),
+		sources:   make(bandit, SourceHints),
+		operators: make(bandit, operatorCount),
+	}
+}
+
+func (bp *banditPolicy) ChooseSource(rnd *rand.Rand, haveSmash bool) SchedSource {
+	exclude := -1
+	if !haveSmash {
+		exclude = int(SourceSmash)
+	}
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	return SchedSource(bp.sources.choose(rnd, exclude))
+}
+
+func (bp *banditPolicy) ChooseOperator(rnd *rand.Rand) MutateOperator {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	return MutateOperator(bp.operators.choose(rnd, -1))
+}
+
+func (bp *banditPolicy) Reward(src SchedSource, op MutateOperator, newSignal bool) {
+	bp.stats.record(src, op, newSignal)
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if src == SourceHints {
+		src = SourceSmash
+	}
+	bp.sources.update(int(src), newSignal)
+	if op != OperatorNone {
+		bp.operators.update(int(op), newSignal)
+	}
+}
+
+type bandit []banditArm
+
+type banditArm struct {
+	// Discounted number of successful and failed pulls.
+	success float64
+	failure float64
+}
+
+// choose returns the arm to pull next, the exclude arm is never returned.
+func (b bandit) choose(rnd *rand.Rand, exclude int) int {
+	if rnd.Float64() < banditExplore {
+		for {
+			if arm := rnd.Intn(len(b)); arm != exclude {
+				return arm
+			}
+		}
+	}
+	best, bestVal := 0, -1.0
+	for i, arm := range b {
+		if i == exclude {
+			continue
+		}
+		// Sample from the Beta(1+success, 1+failure) posterior.
+		x := sampleGamma(rnd, 1+arm.success)
+		y := sampleGamma(rnd, 1+arm.failure)
+		if val := x / (x + y); val > bestVal {
+			best, bestVal = i, val
+		}
+	}
+	return best
+}
+
+func (b bandit) update(arm int, success bool) {
+	for i := range b {
+		b[i].success *= banditDiscount
+		b[i].failure *= banditDiscount
+	}
+	if success {
+		b[arm].success++
+	} else {
+		b[arm].failure++
+	}
+}
+
+// sampleGamma samples Gamma(shape, 1) distribution for shape >= 1
+// using the Marsaglia and Tsang method.
+func sampleGamma(rnd *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rnd.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rnd.Float64()
+		if math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// schedStats exports per-arm statistics, so that policies can be compared
+// (e.g. with syz-testbed).
+type schedStats struct {
+	sourceExecs    [sourceCount]*stat.Val
+	sourceSignal   [sourceCount]*stat.Val
+	operatorExecs  [operatorCount]*stat.Val
+	operatorSignal [operatorCount]*stat.Val
+}
+
+func newSchedStats() schedStats {
+	var s schedStats
+	for src := SchedSource(0); src < sourceCount; src++ {
+		s.sourceExecs[src] = stat.New(fmt.Sprintf("sched %v execs", src),
+			fmt.Sprintf("Executions of inputs from the %v source", src),
+			stat.Rate{}, stat.StackedGraph("sched execs"))
+		s.sourceSignal[src] = stat.New(fmt.Sprintf("sched %v new signal", src),
+			fmt.Sprintf("Executions of inputs from the %v source that gave new signal", src),
+			stat.Rate{}, stat.StackedGraph("sched new signal"))
+	}
+	for op := MutateOperator(0); op < operatorCount; op++ {
+		s.operatorExecs[op] = stat.New(fmt.Sprintf("sched op %v execs", op),
+			fmt.Sprintf("Executions of inputs mutated with the %v operator", op),
+			stat.Rate{}, stat.StackedGraph("sched op execs"))
+		s.operatorSignal[op] = stat.New(fmt.Sprintf("sched op %v new signal", op),
+			fmt.Sprintf("Executions of inputs mutated with the %v operator that gave new signal", op),
+			stat.Rate{}, stat.StackedGraph("sched op new signal"))
+	}
+	return s
+}
+
+func (s *schedStats) record(src SchedSource, op MutateOperator, newSignal bool) {
+	s.sourceExecs[src].Add(1)
+	if newSignal {
+		s.sourceSignal[src].Add(1)
+	}
+	if op == OperatorNone {
+		return
+	}
+	s.operatorExecs[op].Add(1)
+	if newSignal {
+		s.operatorSignal[op].Add(1)
+	}
+}
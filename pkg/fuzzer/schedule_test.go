@@ -0,0 +1,127 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBanditPolicy(t *testing.T) {
+	rnd := rand.New(testutil.RandSource(t))
+	policy := newBanditPolicy()
+	// Mutation with splice gives new signal in 10% of cases, everything else in 1%.
+	for i := 0; i < 20000; i++ {
+		src := policy.ChooseSource(rnd, true)
+		op := OperatorNone
+		if src != SourceGenerate {
+			op = policy.ChooseOperator(rnd)
+		}
+		prob := 0.01
+		if src == SourceMutate && op == OperatorSplice {
+			prob = 0.1
+		}
+		policy.Reward(src, op, rnd.Float64() < prob)
+	}
+	sources := make(map[SchedSource]int)
+	operators := make(map[MutateOperator]int)
+	for i := 0; i < 1000; i++ {
+		sources[policy.ChooseSource(rnd, true)]++
+		operators[policy.ChooseOperator(rnd)]++
+	}
+	assert.Greater(t, sources[SourceMutate], 800)
+	assert.Greater(t, operators[OperatorSplice], 800)
+	// Exploration must not starve the other arms completely.
+	assert.Greater(t, sources[SourceSmash], 0)
+	assert.Greater(t, operators[OperatorDefault], 0)
+}
+
+func TestBanditPolicyHints(t *testing.T) {
+	rnd := rand.New(testutil.RandSource(t))
+	policy := newBanditPolicy()
+	// Hints requests are served from the smash queue, so they reward the smash source.
+	for i := 0; i < 1000; i++ {
+		policy.Reward(SourceHints, OperatorNone, true)
+	}
+	sources := make(map[SchedSource]int)
+	for i := 0; i < 1000; i++ {
+		sources[policy.ChooseSource(rnd, true)]++
+	}
+	assert.Equal(t, 0, sources[SourceHints])
+	assert.Greater(t, sources[SourceSmash], 800)
+}
+
+func TestBanditPolicyNoSmash(t *testing.T) {
+	rnd := rand.New(testutil.RandSource(t))
+	policy := newBanditPolicy()
+	for i := 0; i < 1000; i++ {
+		policy.Reward(SourceSmash, OperatorNone, true)
+	}
+	for i := 0; i < 1000; i++ {
+		assert.NotEqual(t, SourceSmash, policy.ChooseSource(rnd, false))
+	}
+}
+
+func TestFixedPolicy(t *testing.T) {
+	rnd := rand.New(testutil.RandSource(t))
+	policy := newFixedPolicy(&Config{Coverage: true})
+	sources := make(map[SchedSource]int)
+	for i := 0; i < 3000; i++ {
+		sources[policy.ChooseSource(rnd, true)]++
+		assert.Equal(t, OperatorDefault, policy.ChooseOperator(rnd))
+	}
+	// Smash jobs are alternated with fuzzing by the queue.
+	assert.Equal(t, 0, sources[SourceSmash])
+	assert.Greater(t, sources[SourceMutate], sources[SourceGenerate])
+}
+
+func TestFixedPolicySmashRatio(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := map[*prog.Syscall]bool{}
+	for _, c := range target.Syscalls {
+		calls[c] = true
+	}
+	rnd := rand.New(testutil.RandSource(t))
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus:           corpus.NewCorpus(ctx),
+		Coverage:         true,
+		EnabledCalls:     calls,
+		SchedulingPolicy: PolicyFixed,
+	}, rnd, target)
+	smash := make(map[*queue.Request]bool)
+	for i := 0; i < 3000; i++ {
+		req := &queue.Request{Prog: target.Generate(rnd, 3, fuzzer.ChoiceTable())}
+		smash[req] = true
+		fuzzer.smashQueue.Submit(req)
+	}
+	// Every 3rd request is a fuzzing request.
+	smashed := 0
+	for i := 0; i < 3000; i++ {
+		if smash[fuzzer.Next()] {
+			smashed++
+		}
+	}
+	assert.Equal(t, 2000, smashed)
+}
+
+func TestMutateOperatorOpts(t *testing.T) {
+	opts := OperatorDefault.MutateOpts()
+	spliceOpts := OperatorSplice.MutateOpts()
+	assert.Equal(t, opts.SquashWeight, spliceOpts.SquashWeight)
+	assert.Equal(t, opts.SquashWeight+opts.SpliceWeight+opts.InsertWeight+
+		opts.MutateArgWeight+opts.RemoveCallWeight+opts.CrossoverWeight, spliceOpts.SpliceWeight)
+}
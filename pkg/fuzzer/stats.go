@@ -40,6 +40,10 @@ type SyscallStats struct {
 	CoverOverflows atomic.Uint64
 	// Number of times comparisons buffer for this syscall has overflowed.
 	CompsOverflows atomic.Uint64
+	// Histogram of execution time of this syscall (see execTimeBucket).
+	ExecTime [execTimeBuckets]atomic.Uint64
+	// Number of times the syscall was still running when the program timed out.
+	Hangs atomic.Uint64
 }
 
 func newStats(target *prog.Target) Stats {
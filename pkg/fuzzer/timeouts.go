@@ -0,0 +1,130 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/bits"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+)
+
+// The fuzzer learns execution time distribution of each syscall and uses it to derive
+// program timeouts: most programs execute in few milliseconds, and waiting for the default
+// program timeout (several seconds) on each hanged program wastes lots of executor time.
+// Programs that include syscalls that frequently hang are marked as hang-prone,
+// such programs use the default timeout and are executed on a dedicated proc.
+const (
+	// Bucket i of SyscallStats.ExecTime holds executions that took [2^(i-1), 2^i) ms,
+	// bucket 0 holds executions that took less than 1ms.
+	execTimeBuckets = 18
+	// Syscalls need that many executions before we trust their execution time distribution.
+	minExecTimeSamples = 100
+	// A syscall is hang-prone if it hangs at least once per hangProneRate executions.
+	hangProneRate = 100
+	minHangs      = 3
+	// Program timeout is the sum of the 99th percentiles of execution time of its syscalls
+	// multiplied by the slack factor.
+	execTimeSlack = 3
+)
+
+func execTimeBucket(ms uint32) int {
+	return min(bits.Len32(ms), execTimeBuckets-1)
+}
+
+// Execs returns the number of executions of the syscall with known execution time.
+func (stat *SyscallStats) Execs() uint64 {
+	total := uint64(0)
+	for i := range stat.ExecTime {
+		total += stat.ExecTime[i].Load()
+	}
+	return total
+}
+
+// ExecTimeQuantile returns an upper bound for the q-th quantile of the syscall execution time,
+// or 0 if the syscall was not executed yet.
+func (stat *SyscallStats) ExecTimeQuantile(q float64) time.Duration {
+	var hist [execTimeBuckets]uint64
+	total := uint64(0)
+	for i := range stat.ExecTime {
+		hist[i] = stat.ExecTime[i].Load()
+		total += hist[i]
+	}
+	if total == 0 {
+		return 0
+	}
+	need := uint64(q * float64(total))
+	sum := uint64(0)
+	for i, count := range hist {
+		sum += count
+		if sum >= need && sum != 0 {
+			return time.Duration(1<<i) * time.Millisecond
+		}
+	}
+	return time.Duration(1<<(execTimeBuckets-1)) * time.Millisecond
+}
+
+func (stat *SyscallStats) hangProne() bool {
+	hangs := stat.Hangs.Load()
+	return hangs >= minHangs && hangs*hangProneRate >= stat.Execs()+hangs
+}
+
+// recordExecTime updates execution time distributions of the program syscalls.
+func (stats *Stats) recordExecTime(p *prog.Prog, res *queue.Result) {
+	if p == nil || res.Info == nil || res.Status != queue.Success && res.Status != queue.Hanged {
+		return
+	}
+	for i, info := range res.Info.Calls {
+		if i >= len(p.Calls) {
+			break
+		}
+		stat := &stats.Syscalls[p.Calls[i].Meta.ID]
+		switch info.Flags & (flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished) {
+		case flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished:
+			stat.ExecTime[execTimeBucket(info.ElapsedMs)].Add(1)
+		case flatrpc.CallFlagExecuted:
+			// Calls that are still blocked when the program finishes are normal
+			// (e.g. read on an empty pipe), the executor just stops waiting for them.
+			// Only calls that were still running when the program timed out are hangs.
+			// Calls that were not executed at all don't tell us anything:
+			// we don't know what blocked the program.
+			if res.Status == queue.Hanged {
+				stat.Hangs.Add(1)
+			}
+		}
+	}
+}
+
+// progTimeout returns the timeout for the program derived from the learned distributions
+// (0 if we don't know enough to derive it), and whether the program is likely to hang.
+func (stats *Stats) progTimeout(p *prog.Prog) (time.Duration, bool) {
+	learned, hangProne := true, false
+	total := time.Duration(0)
+	for _, call := range p.Calls {
+		stat := &stats.Syscalls[call.Meta.ID]
+		hangProne = hangProne || stat.hangProne()
+		// Calls with explicit timeouts are expected to run for a long time.
+		if call.Meta.Attrs.Timeout != 0 || call.Meta.Attrs.ProgTimeout != 0 ||
+			stat.Execs() < minExecTimeSamples {
+			learned = false
+			continue
+		}
+		total += stat.ExecTimeQuantile(0.99)
+	}
+	if !learned || hangProne {
+		return 0, hangProne
+	}
+	return execTimeSlack * total, false
+}
+
+// setTimeout is supposed to be used only for generated and mutated programs:
+// other requests (triage, smash, candidates, etc) are too valuable to be killed early.
+func (stats *Stats) setTimeout(req *queue.Request) {
+	if req.Type != flatrpc.RequestTypeProgram || req.Prog == nil {
+		return
+	}
+	req.Timeout, req.HangProne = stats.progTimeout(req.Prog)
+}
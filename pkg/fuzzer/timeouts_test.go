@@ -0,0 +1,97 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecTimeQuantile(t *testing.T) {
+	var stat SyscallStats
+	assert.Equal(t, time.Duration(0), stat.ExecTimeQuantile(0.5))
+	for i := 0; i < 90; i++ {
+		stat.ExecTime[execTimeBucket(0)].Add(1)
+	}
+	for i := 0; i < 9; i++ {
+		stat.ExecTime[execTimeBucket(5)].Add(1)
+	}
+	stat.ExecTime[execTimeBucket(1<<30)].Add(1)
+	assert.Equal(t, uint64(100), stat.Execs())
+	assert.Equal(t, time.Millisecond, stat.ExecTimeQuantile(0.5))
+	assert.Equal(t, 8*time.Millisecond, stat.ExecTimeQuantile(0.95))
+	assert.Equal(t, 8*time.Millisecond, stat.ExecTimeQuantile(0.99))
+	assert.Equal(t, time.Duration(1<<17)*time.Millisecond, stat.ExecTimeQuantile(1))
+}
+
+func TestProgTimeout(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("test()\ntest$res0()\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &Stats{Syscalls: make([]SyscallStats, len(target.Syscalls)+1)}
+	const finished = flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished
+	execute := func(status queue.Status, calls ...*flatrpc.CallInfo) {
+		stats.recordExecTime(p, &queue.Result{
+			Status: status,
+			Info:   &flatrpc.ProgInfo{Calls: calls},
+		})
+	}
+	for i := 0; i < minExecTimeSamples-1; i++ {
+		execute(queue.Success,
+			&flatrpc.CallInfo{Flags: finished, ElapsedMs: 1},
+			&flatrpc.CallInfo{Flags: finished, ElapsedMs: 10})
+	}
+	// Not enough samples yet.
+	timeout, hangProne := stats.progTimeout(p)
+	assert.Equal(t, time.Duration(0), timeout)
+	assert.False(t, hangProne)
+
+	// Results of crashed programs are not meaningful.
+	execute(queue.Crashed, &flatrpc.CallInfo{}, &flatrpc.CallInfo{})
+	// Calls that were not executed are not blamed.
+	execute(queue.Hanged, &flatrpc.CallInfo{Flags: finished, ElapsedMs: 1}, &flatrpc.CallInfo{})
+	execute(queue.Success,
+		&flatrpc.CallInfo{Flags: finished, ElapsedMs: 1},
+		&flatrpc.CallInfo{Flags: finished, ElapsedMs: 12})
+	timeout, hangProne = stats.progTimeout(p)
+	assert.Equal(t, execTimeSlack*(2+16)*time.Millisecond, timeout)
+	assert.False(t, hangProne)
+	req := &queue.Request{Prog: p}
+	stats.setTimeout(req)
+	assert.Equal(t, timeout, req.Timeout)
+
+	// Calls blocked when the program finishes normally are not hangs.
+	for i := 0; i < 2*minHangs; i++ {
+		execute(queue.Success,
+			&flatrpc.CallInfo{Flags: finished, ElapsedMs: 1},
+			&flatrpc.CallInfo{Flags: flatrpc.CallFlagExecuted, ElapsedMs: 1000})
+	}
+	assert.Equal(t, uint64(0), stats.Syscalls[p.Calls[1].Meta.ID].Hangs.Load())
+	_, hangProne = stats.progTimeout(p)
+	assert.False(t, hangProne)
+
+	// The second call is still running when the program times out several times.
+	for i := 0; i < minHangs; i++ {
+		execute(queue.Hanged,
+			&flatrpc.CallInfo{Flags: finished, ElapsedMs: 1},
+			&flatrpc.CallInfo{Flags: flatrpc.CallFlagExecuted, ElapsedMs: 1000})
+	}
+	assert.Equal(t, uint64(0), stats.Syscalls[p.Calls[0].Meta.ID].Hangs.Load())
+	assert.Equal(t, uint64(minHangs), stats.Syscalls[p.Calls[1].Meta.ID].Hangs.Load())
+	assert.Equal(t, uint64(minExecTimeSamples), stats.Syscalls[p.Calls[1].Meta.ID].Execs())
+	timeout, hangProne = stats.progTimeout(p)
+	assert.Equal(t, time.Duration(0), timeout)
+	assert.True(t, hangProne)
+}
@@ -89,6 +89,13 @@ func (cs *CrashStore) SaveCrash(crash *Crash) (bool, error) {
 	writeOrRemove("tag", []byte(cs.Tag))
 	writeOrRemove("report", crash.Report.Report)
 	writeOrRemove("machineInfo", crash.MachineInfo)
+	sessionFile := filepath.Join(dir, fmt.Sprintf("session%v.jsonl", oldestI))
+	os.Remove(sessionFile)
+	if crash.SessionFile != "" {
+		if err := osutil.Rename(crash.SessionFile, sessionFile); err != nil {
+			log.Logf(0, "failed to save session file: %v", err)
+		}
+	}
 
 	return first, nil
 }
@@ -173,6 +180,42 @@ func (cs *CrashStore) SaveRepro(res *ReproResult, progText, cProgText []byte) er
 	return nil
 }
 
+// CrashLogs returns up to max most recent stored logs of the crash with the given title, the newest first.
+func (cs *CrashStore) CrashLogs(title string, max int) ([][]byte, error) {
+	dir := cs.path(title)
+	files, err := osutil.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type logFile struct {
+		name  string
+		mtime time.Time
+	}
+	var logs []logFile
+	for _, f := range files {
+		if _, err := strconv.Atoi(strings.TrimPrefix(f, "log")); err != nil || !strings.HasPrefix(f, "log") {
+			continue
+		}
+		stat, err := os.Stat(filepath.Join(dir, f))
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, logFile{f, stat.ModTime()})
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].mtime.After(logs[j].mtime)
+	})
+	var ret [][]byte
+	for i := 0; i < len(logs) && i < max; i++ {
+		data, err := os.ReadFile(filepath.Join(dir, logs[i].name))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, data)
+	}
+	return ret, nil
+}
+
 type BugReport struct {
 	Title  string
 	Tag    string
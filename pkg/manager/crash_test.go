@@ -4,6 +4,9 @@
 package manager
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/syzkaller/pkg/report"
@@ -81,6 +84,49 @@ func TestMaxCrashLogs(t *testing.T) {
 	assert.Len(t, info.Crashes, 5)
 }
 
+func TestCrashLogs(t *testing.T) {
+	crashStore := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 5,
+	}
+	for i := 0; i < 3; i++ {
+		_, err := crashStore.SaveCrash(&Crash{Report: &report.Report{
+			Title:  "Title A",
+			Output: []byte(fmt.Sprintf("log %v", i)),
+		}})
+		assert.NoError(t, err)
+	}
+	logs, err := crashStore.CrashLogs("Title A", 10)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, [][]byte{[]byte("log 0"), []byte("log 1"), []byte("log 2")}, logs)
+	logs, err = crashStore.CrashLogs("Title A", 2)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	_, err = crashStore.CrashLogs("Title B", 2)
+	assert.Error(t, err)
+}
+
+func TestCrashSession(t *testing.T) {
+	crashStore := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 5,
+	}
+	session := filepath.Join(t.TempDir(), "vm0-crash.jsonl")
+	assert.NoError(t, os.WriteFile(session, []byte("session"), 0644))
+	_, err := crashStore.SaveCrash(&Crash{
+		SessionFile: session,
+		Report: &report.Report{
+			Title:  "Title A",
+			Output: []byte("ABCD"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoFileExists(t, session)
+	data, err := os.ReadFile(filepath.Join(crashStore.path("Title A"), "session0.jsonl"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("session"), data)
+}
+
 func TestCrashRepro(t *testing.T) {
 	crashStore := &CrashStore{
 		Tag:          "abcd",
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"os"
 	"sort"
 	"strings"
 	"sync"
@@ -336,7 +337,7 @@ func (dc *diffContext) RunRepro(ctx context.Context, crash *Crash) *ReproResult
 	dc.reproAttempts[crash.Title]++
 	dc.mu.Unlock()
 
-	res, stats, err := repro.Run(ctx, crash.Output, repro.Environment{
+	res, stats, err := repro.Run(ctx, [][]byte{crash.Output}, repro.Environment{
 		Config:   dc.new.cfg,
 		Features: dc.new.features,
 		Reporter: dc.new.reporter,
@@ -388,6 +389,8 @@ type kernelContext struct {
 	http          *HTTPServer
 	source        queue.Source
 	duplicateInto queue.Executor
+	// If set, it's called on machine check instead of the default fuzzer/source setup.
+	machineChecked func(syscalls map[*prog.Syscall]bool) queue.Source
 }
 
 func setup(name string, cfg *mgrconfig.Config, debug bool) (*kernelContext, error) {
@@ -466,7 +469,9 @@ func (kc *kernelContext) MachineChecked(features flatrpc.Feature,
 	kc.features = features
 
 	var source queue.Source
-	if kc.source == nil {
+	if kc.machineChecked != nil {
+		source = kc.machineChecked(syscalls)
+	} else if kc.source == nil {
 		source = queue.Tee(kc.setupFuzzer(features, syscalls), kc.duplicateInto)
 	} else {
 		source = kc.source
@@ -482,12 +487,13 @@ func (kc *kernelContext) setupFuzzer(features flatrpc.Feature, syscalls map[*pro
 		Corpus:   corpusObj,
 		Coverage: kc.cfg.Cover,
 		// Fault injection may bring instaibility into bug reproducibility, which may lead to false positives.
-		FaultInjection: false,
-		Comparisons:    features&flatrpc.FeatureComparisons != 0,
-		Collide:        true,
-		EnabledCalls:   syscalls,
-		NoMutateCalls:  kc.cfg.NoMutateCalls,
-		PatchTest:      true,
+		FaultInjection:   false,
+		Comparisons:      features&flatrpc.FeatureComparisons != 0,
+		Collide:          true,
+		EnabledCalls:     syscalls,
+		NoMutateCalls:    kc.cfg.NoMutateCalls,
+		PatchTest:        true,
+		SchedulingPolicy: kc.cfg.Experimental.SchedulingPolicy,
 		Logf: func(level int, msg string, args ...interface{}) {
 			if level != 0 {
 				return
@@ -568,7 +574,10 @@ func (kc *kernelContext) fuzzerInstance(ctx context.Context, inst *vm.Instance,
 	injectExec := make(chan bool, 10)
 	kc.serv.CreateInstance(index, injectExec, updInfo)
 	rep, err := kc.runInstance(ctx, inst, injectExec)
-	lastExec, _ := kc.serv.ShutdownInstance(index, rep != nil)
+	lastExec, _, session := kc.serv.ShutdownInstance(index, rep != nil)
+	if session != "" {
+		os.Remove(session)
+	}
 	if rep != nil {
 		rpcserver.PrependExecuting(rep, lastExec)
 		select {
@@ -0,0 +1,296 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/prog"
+	"golang.org/x/sync/errgroup"
+)
+
+type DifferentialConfig struct {
+	Debug bool
+	// Compare output of programs in addition to errnos of calls.
+	CompareOutput bool
+	// Confirmed mismatches and kernel crashes are saved into the store.
+	Store *CrashStore
+}
+
+// RunDifferential fuzzes the kernel described by the first config and executes every program
+// on all kernels (e.g. an LTS and the mainline kernel). Programs that behave differently
+// on the kernels (calls return different errnos, or programs produce different output)
+// are re-executed to confirm the difference, and are then saved as crashes into the store.
+func RunDifferential(ctx context.Context, cfgs []*mgrconfig.Config, cfg DifferentialConfig) error {
+	if len(cfgs) < 2 {
+		return fmt.Errorf("differential execution needs at least 2 kernels")
+	}
+	var kernels []*kernelContext
+	var maxPending []int
+	for i, kernelCfg := range cfgs {
+		name := kernelCfg.Name
+		if name == "" {
+			name = fmt.Sprintf("kernel%v", i)
+		}
+		kc, err := setup(name, kernelCfg, cfg.Debug)
+		if err != nil {
+			return err
+		}
+		kernels = append(kernels, kc)
+		// Enough to keep all procs of the kernel busy.
+		maxPending = append(maxPending, 2*kc.pool.Total()*kernelCfg.Procs)
+	}
+	d := newDifferential(cfg, maxPending)
+	d.kernels = kernels
+	for i, kc := range kernels {
+		kc.machineChecked = func(syscalls map[*prog.Syscall]bool) queue.Source {
+			return d.machineChecked(i, syscalls)
+		}
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		info, err := LoadSeeds(cfgs[0], true)
+		if err != nil {
+			return err
+		}
+		select {
+		case d.kernels[0].candidates <- info.Candidates:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	for _, kc := range d.kernels {
+		eg.Go(func() error {
+			return kc.Loop(ctx)
+		})
+		eg.Go(func() error {
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case rep := <-kc.crashes:
+					log.Logf(0, "%v: crashed: %v", kc.name, rep.Title)
+					rep.Title = fmt.Sprintf("%v: %v", kc.name, rep.Title)
+					if _, err := d.cfg.Store.SaveCrash(&Crash{Report: rep}); err != nil {
+						log.Errorf("failed to save crash: %v", err)
+					}
+				}
+			}
+		})
+	}
+	return eg.Wait()
+}
+
+func newDifferential(cfg DifferentialConfig, maxPending []int) *differential {
+	kernels := len(maxPending)
+	d := &differential{
+		cfg:       cfg,
+		fuzzer:    queue.DynamicSource(queue.Callback(func() *queue.Request { return nil })),
+		confirm:   queue.Plain(),
+		syscalls:  make([]map[*prog.Syscall]bool, kernels),
+		confirmed: make(map[string]int),
+		pending:   make(map[*queue.Request]*diffMismatch),
+		statMismatches: stat.New("diff mismatches", "Programs that behaved differently on the kernels",
+			stat.Rate{}, stat.Graph("differential")),
+		statConfirmed: stat.New("diff confirmed", "Unique mismatches confirmed by re-execution",
+			stat.Graph("differential")),
+	}
+	d.fanout = queue.Fanout(queue.Order(d.confirm, queue.Callback(d.nextFuzzer)), maxPending, d.compare)
+	return d
+}
+
+type differential struct {
+	cfg     DifferentialConfig
+	kernels []*kernelContext
+	fanout  *queue.FanoutCtl
+	fuzzer  *queue.DynamicSourceCtl
+	confirm *queue.PlainQueue
+
+	mu       sync.Mutex
+	syscalls []map[*prog.Syscall]bool
+	// Number of times each confirmed mismatch was observed.
+	confirmed map[string]int
+	// Mismatches that are being re-executed for confirmation.
+	pending map[*queue.Request]*diffMismatch
+
+	statMismatches *stat.Val
+	statConfirmed  *stat.Val
+}
+
+type diffMismatch struct {
+	title  string
+	report []byte
+}
+
+func (d *differential) machineChecked(idx int, syscalls map[*prog.Syscall]bool) queue.Source {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.syscalls[idx] = syscalls
+	for _, calls := range d.syscalls {
+		if calls == nil {
+			return d.fanout.Source(idx)
+		}
+	}
+	// All kernels have been checked, so we can start fuzzing.
+	// Only syscalls enabled on all kernels are used, otherwise we would get
+	// lots of trivial mismatches due to missing syscalls.
+	common := make(map[*prog.Syscall]bool)
+	for call := range d.syscalls[0] {
+		enabled := true
+		for _, calls := range d.syscalls[1:] {
+			enabled = enabled && calls[call]
+		}
+		if enabled {
+			common[call] = true
+		}
+	}
+	log.Logf(0, "differential: %v syscalls enabled on all kernels", len(common))
+	base := d.kernels[0]
+	go func() {
+		// Note: setupFuzzer blocks until candidates are loaded.
+		d.fuzzer.Store(base.setupFuzzer(base.features, common))
+	}()
+	return d.fanout.Source(idx)
+}
+
+func (d *differential) nextFuzzer() *queue.Request {
+	req := d.fuzzer.Next()
+	if req != nil && d.cfg.CompareOutput {
+		req.ReturnOutput = true
+	}
+	return req
+}
+
+func (d *differential) compare(req *queue.Request, results []*queue.Result) {
+	mismatch := d.findMismatch(req, results)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	orig, confirming := d.pending[req]
+	delete(d.pending, req)
+	if mismatch == nil {
+		if confirming {
+			log.Logf(1, "differential: flaky mismatch: %v", orig.title)
+		}
+		return
+	}
+	if !confirming {
+		d.statMismatches.Add(1)
+	}
+	if !confirming && d.confirmed[mismatch.title] == 0 {
+		confirm := &queue.Request{
+			Prog:         req.Prog.Clone(),
+			ExecOpts:     req.ExecOpts,
+			ReturnOutput: req.ReturnOutput,
+			Important:    true,
+		}
+		d.pending[confirm] = mismatch
+		d.confirm.Submit(confirm)
+		return
+	}
+	if confirming && orig.title != mismatch.title {
+		log.Logf(1, "differential: flaky mismatch: %v -> %v", orig.title, mismatch.title)
+		return
+	}
+	if d.confirmed[mismatch.title] == 0 {
+		log.Logf(0, "differential: %v", mismatch.title)
+		d.statConfirmed.Add(1)
+	}
+	d.confirmed[mismatch.title]++
+	if d.confirmed[mismatch.title] > d.cfg.Store.MaxCrashLogs {
+		// Don't overwrite the saved logs for frequent mismatches.
+		return
+	}
+	crash := &Crash{
+		Report: &report.Report{
+			Title:  mismatch.title,
+			Report: mismatch.report,
+			Output: req.Prog.Serialize(),
+		},
+	}
+	if _, err := d.cfg.Store.SaveCrash(crash); err != nil {
+		log.Errorf("failed to save mismatch: %v", err)
+	}
+}
+
+// findMismatch compares results of the program on all kernels.
+// Only the first call with different errnos is reported since subsequent calls
+// frequently fail as the consequence.
+func (d *differential) findMismatch(req *queue.Request, results []*queue.Result) *diffMismatch {
+	if req.Type != flatrpc.RequestTypeProgram {
+		return nil
+	}
+	for _, res := range results {
+		if res.Status != queue.Success || res.Info == nil {
+			// Crashes and hangs are reported separately,
+			// and comparing partial results is not meaningful.
+			return nil
+		}
+	}
+	const finished = flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished
+	for i, call := range req.Prog.Calls {
+		errnos := make([]int32, len(results))
+		complete := true
+		for k, res := range results {
+			info := res.Info.Calls[i]
+			complete = complete && info.Flags&finished == finished
+			errnos[k] = info.Error
+		}
+		if !complete {
+			// Blocked calls return non-deterministic results.
+			continue
+		}
+		differ := false
+		for _, errno := range errnos[1:] {
+			differ = differ || errno != errnos[0]
+		}
+		if !differ {
+			continue
+		}
+		var vals []string
+		for k, errno := range errnos {
+			vals = append(vals, fmt.Sprintf("%v=%v", d.kernels[k].name, errno))
+		}
+		return &diffMismatch{
+			title:  fmt.Sprintf("errno mismatch in %v (%v)", call.Meta.Name, strings.Join(vals, " ")),
+			report: d.formatReport(req.Prog, results),
+		}
+	}
+	if d.cfg.CompareOutput {
+		for _, res := range results[1:] {
+			if !bytes.Equal(res.Output, results[0].Output) {
+				return &diffMismatch{
+					title:  "output mismatch",
+					report: d.formatReport(req.Prog, results),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (d *differential) formatReport(p *prog.Prog, results []*queue.Result) []byte {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s\n", p.Serialize())
+	for k, res := range results {
+		fmt.Fprintf(buf, "%v:", d.kernels[k].name)
+		for _, call := range res.Info.Calls {
+			fmt.Fprintf(buf, " %v", call.Error)
+		}
+		fmt.Fprintf(buf, "\n")
+		if d.cfg.CompareOutput {
+			fmt.Fprintf(buf, "%s\n", res.Output)
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDifferential(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 2,
+	}
+	d := newDifferential(DifferentialConfig{Store: store}, []int{10, 10})
+	d.kernels = []*kernelContext{{name: "lts"}, {name: "mainline"}}
+	progs := queue.Plain()
+	d.fuzzer.Store(progs)
+
+	p, err := target.Deserialize([]byte("test()\ntest$res0()\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Execute the next request on both kernels with the given errnos of the second call.
+	execute := func(errno0, errno1 int32, status queue.Status) {
+		for k, errno := range []int32{errno0, errno1} {
+			req := d.fanout.Source(k).Next()
+			if req == nil {
+				t.Fatalf("no request for kernel %v", k)
+			}
+			req.Done(&queue.Result{
+				Status: status,
+				Info: &flatrpc.ProgInfo{
+					Calls: []*flatrpc.CallInfo{
+						{Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished},
+						{Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished, Error: errno},
+					},
+				},
+			})
+		}
+	}
+	bugs := func() []string {
+		list, err := store.BugList()
+		assert.NoError(t, err)
+		var titles []string
+		for _, bug := range list {
+			titles = append(titles, bug.Title)
+		}
+		return titles
+	}
+
+	// Same results.
+	progs.Submit(&queue.Request{Prog: p})
+	execute(1, 1, queue.Success)
+	// Hangs/crashes are not compared.
+	progs.Submit(&queue.Request{Prog: p})
+	execute(1, 2, queue.Crashed)
+	assert.Empty(t, bugs())
+
+	// A flaky mismatch is not confirmed.
+	progs.Submit(&queue.Request{Prog: p})
+	execute(1, 2, queue.Success)
+	execute(1, 1, queue.Success)
+	assert.Empty(t, bugs())
+
+	// A stable mismatch is confirmed and saved.
+	progs.Submit(&queue.Request{Prog: p})
+	execute(1, 2, queue.Success)
+	execute(1, 2, queue.Success)
+	title := "errno mismatch in test$res0 (lts=1 mainline=2)"
+	assert.Equal(t, []string{title}, bugs())
+
+	// Subsequent occurrences don't need confirmation.
+	for i := 0; i < 3; i++ {
+		progs.Submit(&queue.Request{Prog: p})
+		execute(1, 2, queue.Success)
+	}
+	info, err := store.BugInfo(crashHash(title), true)
+	assert.NoError(t, err)
+	assert.Len(t, info.Crashes, 2)
+	assert.Equal(t, 4, d.confirmed[title])
+	assert.Nil(t, d.fanout.Source(0).Next())
+}
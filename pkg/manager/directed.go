@@ -0,0 +1,75 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// The directed fuzzing schedule switches from exploration to exploitation during this time.
+const directedExploitTime = time.Hour
+
+// PrepareDirected resolves directed fuzzing targets from the config
+// and computes static call graph distances to them.
+func PrepareDirected(source *ReportGeneratorWrapper, cfg *mgrconfig.Config) (*corpus.Directed, error) {
+	rg, err := source.Get()
+	if err != nil {
+		return nil, err
+	}
+	targets, err := rg.DirectedTargets(cfg.Experimental.DirectedTargets)
+	if err != nil {
+		return nil, err
+	}
+	// KCOV will point to the next instruction, so we need to adjust the PCs.
+	coverPC := func(pc uint64) uint64 {
+		return backend.NextInstructionPC(cfg.SysTarget, cfg.Type, pc)
+	}
+	directed := &corpus.Directed{
+		Targets:     make(map[string]map[uint64]struct{}),
+		Distances:   make(map[uint64]uint32),
+		ExploitTime: directedExploitTime,
+	}
+	var targetPCs []uint64
+	for name, pcs := range targets {
+		covPCs := make(map[uint64]struct{})
+		for _, pc := range pcs {
+			covPCs[coverPC(pc)] = struct{}{}
+		}
+		directed.Targets[name] = covPCs
+		targetPCs = append(targetPCs, pcs...)
+	}
+	for pc, dist := range rg.Distances(targetPCs) {
+		directed.Distances[coverPC(pc)] = dist
+	}
+	if len(rg.Calls) == 0 {
+		log.Logf(0, "directed fuzzing: call graph is not available for %v, only target functions are accounted",
+			cfg.SysTarget.Arch)
+	}
+	log.Logf(0, "directed fuzzing: %v targets (%v PCs), %v PCs with known distance",
+		len(targets), len(targetPCs), len(directed.Distances))
+	return directed, nil
+}
+
+var directedFileRe = regexp.MustCompile(`[^a-zA-Z0-9_.\-]`)
+
+// SaveDirectedProg saves the program that reached the directed fuzzing target to workdir/directed.
+func SaveDirectedProg(workdir, target string, data []byte) (string, error) {
+	file := filepath.Join(workdir, "directed", directedFileRe.ReplaceAllString(target, "_"))
+	if err := osutil.MkdirAll(filepath.Dir(file)); err != nil {
+		return "", fmt.Errorf("failed to create directed dir: %w", err)
+	}
+	if err := osutil.WriteFile(file, data); err != nil {
+		return "", fmt.Errorf("failed to save directed program: %w", err)
+	}
+	return file, nil
+}
@@ -29,10 +29,12 @@ import (
 	"github.com/google/syzkaller/pkg/html/pages"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
 	"github.com/google/syzkaller/pkg/vcs"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
 	"github.com/google/syzkaller/vm"
 	"github.com/google/syzkaller/vm/dispatcher"
 	"github.com/gorilla/handlers"
@@ -94,10 +96,13 @@ func (serv *HTTPServer) Serve(ctx context.Context) error {
 	handle("/funccover", serv.httpFuncCover)
 	handle("/input", serv.httpInput)
 	handle("/jobs", serv.httpJobs)
+	handle("/lineage", serv.httpLineage)
 	handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}).ServeHTTP)
 	handle("/modulecover", serv.httpModuleCover)
 	handle("/modules", serv.modulesInfo)
 	handle("/prio", serv.httpPrio)
+	handle("/provenance", serv.httpProvenance)
+	handle("/rarity", serv.httpRarity)
 	handle("/rawcover", serv.httpRawCover)
 	handle("/rawcoverfiles", serv.httpRawCoverFiles)
 	handle("/stats", serv.httpStats)
@@ -235,24 +240,27 @@ func (serv *HTTPServer) httpSyscalls(w http.ResponseWriter, r *http.Request) {
 		if syscall, ok := serv.Cfg.Target.SyscallMap[c]; ok {
 			syscallID = &syscall.ID
 		}
-		coverOverflows, compsOverflows := 0, 0
+		call := UICallType{
+			Name:   c,
+			ID:     syscallID,
+			Inputs: cc.Count,
+			Total:  total[c],
+			Cover:  len(cc.Cover),
+		}
 		if fuzzerObj != nil {
 			idx := len(serv.Cfg.Target.Syscalls)
 			if c != prog.ExtraCallName {
 				idx = serv.Cfg.Target.SyscallMap[c].ID
 			}
-			coverOverflows = int(fuzzerObj.Syscalls[idx].CoverOverflows.Load())
-			compsOverflows = int(fuzzerObj.Syscalls[idx].CompsOverflows.Load())
+			stat := &fuzzerObj.Syscalls[idx]
+			call.CoverOverflows = int(stat.CoverOverflows.Load())
+			call.CompsOverflows = int(stat.CompsOverflows.Load())
+			call.Execs = stat.Execs()
+			call.ExecTimeP50 = stat.ExecTimeQuantile(0.5).Milliseconds()
+			call.ExecTimeP99 = stat.ExecTimeQuantile(0.99).Milliseconds()
+			call.Hangs = stat.Hangs.Load()
 		}
-		data.Calls = append(data.Calls, UICallType{
-			Name:           c,
-			ID:             syscallID,
-			Inputs:         cc.Count,
-			Total:          total[c],
-			Cover:          len(cc.Cover),
-			CoverOverflows: coverOverflows,
-			CompsOverflows: compsOverflows,
-		})
+		data.Calls = append(data.Calls, call)
 	}
 	sort.Slice(data.Calls, func(i, j int) bool {
 		return data.Calls[i].Name < data.Calls[j].Name
@@ -404,9 +412,10 @@ func (serv *HTTPServer) httpCorpus(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 		data.Inputs = append(data.Inputs, UIInput{
-			Sig:   inp.Sig,
-			Short: inp.Prog.String(),
-			Cover: len(inp.Cover),
+			Sig:        inp.Sig,
+			Short:      inp.Prog.String(),
+			Cover:      len(inp.Cover),
+			Provenance: inp.Provenance.String(),
 		})
 	}
 	sort.Slice(data.Inputs, func(i, j int) bool {
@@ -682,6 +691,134 @@ func (serv *HTTPServer) httpPrio(w http.ResponseWriter, r *http.Request) {
 	executeTemplate(w, prioTemplate, data)
 }
 
+// The max number of elements shown on the rarity page.
+const maxRarityElems = 1000
+
+func (serv *HTTPServer) httpRarity(w http.ResponseWriter, r *http.Request) {
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		http.Error(w, "the corpus information is not yet available", http.StatusInternalServerError)
+		return
+	}
+	count := 100
+	if val := r.FormValue("n"); val != "" {
+		var err error
+		if count, err = strconv.Atoi(val); err != nil || count <= 0 {
+			http.Error(w, fmt.Sprintf("invalid n: %q", val), http.StatusBadRequest)
+			return
+		}
+		count = min(count, maxRarityElems)
+	}
+	data := &UIRarityData{
+		UIPageHeader: serv.pageHeader(r, "rarest signal"),
+	}
+	formatPC := func(pc uint64) string {
+		return fmt.Sprintf("%#x", pc)
+	}
+	if coverInfo := serv.Cover.Load(); coverInfo != nil {
+		if rg, err := coverInfo.ReportGenerator.Get(); err == nil {
+			formatPC = rg.FormatPC
+		}
+	}
+	edges := serv.Cfg.Experimental.CoverEdges
+	hashed := serv.Cfg.TargetOS != targets.TestOS
+	rarest := corpus.HitCounts().Rarest(count)
+	// Index of the first corpus program that has each of the rarest elements.
+	items := corpus.Items()
+	itemFor := make(map[uint64]int, len(rarest))
+	for _, elem := range rarest {
+		itemFor[elem.Elem] = -1
+	}
+	for i, item := range items {
+		for elem := range item.Signal {
+			if idx, ok := itemFor[uint64(elem)]; ok && idx == -1 {
+				itemFor[uint64(elem)] = i
+			}
+		}
+	}
+	for _, elem := range rarest {
+		ui := UIRareElem{
+			Elem:  fmt.Sprintf("%#x", elem.Elem),
+			Progs: elem.Progs,
+			Execs: elem.Execs,
+		}
+		if idx := itemFor[elem.Elem]; idx != -1 {
+			item := items[idx]
+			ui.Sig = item.Sig
+			ui.Short = item.Prog.String()
+			// Signal elements are (hashed) coverage edges, show the PCs they were produced from.
+			if serv.Cfg.Cover {
+				if prev, pc, ok := signal.ElemEdge(elem.Elem, item.Cover, edges, hashed); ok {
+					ui.Elem = formatPC(pc)
+					if prev != 0 {
+						ui.Elem = formatPC(prev) + " -> " + ui.Elem
+					}
+				}
+			}
+		}
+		data.Elems = append(data.Elems, ui)
+	}
+	executeTemplate(w, rarityTemplate, data)
+}
+
+func (serv *HTTPServer) httpProvenance(w http.ResponseWriter, r *http.Request) {
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		http.Error(w, "the corpus information is not yet available", http.StatusInternalServerError)
+		return
+	}
+	data := &UIProvenanceData{
+		UIPageHeader: serv.pageHeader(r, "provenance"),
+	}
+	for _, stat := range corpus.ProvenanceStats() {
+		data.Total += stat.Progs
+		job := stat.Job
+		if job == "" {
+			job = "unknown"
+		}
+		data.Stats = append(data.Stats, UIProvenanceStat{
+			Job:      job,
+			Operator: stat.Operator,
+			Progs:    stat.Progs,
+			Signal:   stat.Signal,
+		})
+	}
+	executeTemplate(w, provenanceTemplate, data)
+}
+
+// httpLineage exports the lineage graph of corpus programs.
+// Parameters: format=json|dot (json by default), min=N to show only programs with at least N descendants,
+// raw=1 to get the plain text without the HTML page.
+func (serv *HTTPServer) httpLineage(w http.ResponseWriter, r *http.Request) {
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		http.Error(w, "the corpus information is not yet available", http.StatusInternalServerError)
+		return
+	}
+	minDescendants := 0
+	if val := r.FormValue("min"); val != "" {
+		var err error
+		if minDescendants, err = strconv.Atoi(val); err != nil || minDescendants < 0 {
+			http.Error(w, fmt.Sprintf("invalid min: %q", val), http.StatusBadRequest)
+			return
+		}
+	}
+	lineage := BuildLineage(corpus.Items()).Filter(minDescendants)
+	switch r.FormValue("format") {
+	case "", "json":
+		serv.jsonPage(w, r, "lineage", lineage)
+	case "dot":
+		buf := new(bytes.Buffer)
+		if err := lineage.WriteDOT(buf); err != nil {
+			http.Error(w, fmt.Sprintf("failed to generate DOT: %v", err), http.StatusInternalServerError)
+			return
+		}
+		serv.textPage(w, r, "lineage", buf.Bytes())
+	default:
+		http.Error(w, fmt.Sprintf("unknown format: %q", r.FormValue("format")), http.StatusBadRequest)
+	}
+}
+
 func (serv *HTTPServer) httpFile(w http.ResponseWriter, r *http.Request) {
 	file := filepath.Clean(r.FormValue("name"))
 	if !strings.HasPrefix(file, "crashes/") && !strings.HasPrefix(file, "corpus/") {
@@ -708,6 +845,16 @@ func (serv *HTTPServer) httpInput(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if prov := inp.Provenance; prov.Job != "" {
+		fmt.Fprintf(w, "# job: %v\n", prov.Job)
+		if prov.Operator != "" {
+			fmt.Fprintf(w, "# operator: %v\n", prov.Operator)
+		}
+		if prov.Parent != "" {
+			fmt.Fprintf(w, "# parent: %v\n", prov.Parent)
+		}
+		fmt.Fprintf(w, "\n")
+	}
 	w.Write(inp.Prog.Serialize())
 }
 
@@ -801,8 +948,9 @@ func (serv *HTTPServer) httpAddCandidate(w http.ResponseWriter, r *http.Request)
 	flags |= fuzzer.ProgMinimized
 	flags |= fuzzer.ProgSmashed
 	candidates := []fuzzer.Candidate{{
-		Prog:  prog,
-		Flags: flags,
+		Prog:       prog,
+		Flags:      flags,
+		Provenance: corpus.Provenance{Job: corpus.ProvenanceManual},
 	}}
 	serv.Fuzzer.Load().AddCandidates(candidates)
 }
@@ -1057,6 +1205,10 @@ type UICallType struct {
 	Cover          int
 	CoverOverflows int
 	CompsOverflows int
+	Execs          uint64
+	ExecTimeP50    int64 // in ms
+	ExecTimeP99    int64 // in ms
+	Hangs          uint64
 }
 
 type UICorpusPage struct {
@@ -1067,9 +1219,10 @@ type UICorpusPage struct {
 }
 
 type UIInput struct {
-	Sig   string
-	Short string
-	Cover int
+	Sig        string
+	Short      string
+	Cover      int
+	Provenance string
 }
 
 type UIPageHeader struct {
@@ -1133,6 +1286,32 @@ type UIPrio struct {
 	Prio int32
 }
 
+type UIRarityData struct {
+	UIPageHeader
+	Elems []UIRareElem
+}
+
+type UIRareElem struct {
+	Elem  string // the symbolized edge or PC, if it's known
+	Progs uint32
+	Execs uint32
+	Sig   string // one of the corpus programs that hit the element
+	Short string
+}
+
+type UIProvenanceData struct {
+	UIPageHeader
+	Total int
+	Stats []UIProvenanceStat
+}
+
+type UIProvenanceStat struct {
+	Job      string
+	Operator string
+	Progs    int
+	Signal   int
+}
+
 type UIFallbackCoverData struct {
 	UIPageHeader
 	Calls []UIFallbackCall
@@ -1180,6 +1359,8 @@ var (
 	crashTemplate         = createPage("crash", UICrashPage{})
 	corpusTemplate        = createPage("corpus", UICorpusPage{})
 	prioTemplate          = createPage("prio", UIPrioData{})
+	rarityTemplate        = createPage("rarity", UIRarityData{})
+	provenanceTemplate    = createPage("provenance", UIProvenanceData{})
 	fallbackCoverTemplate = createPage("fallback_cover", UIFallbackCoverData{})
 	rawCoverTemplate      = createPage("raw_cover", UIRawCoverPage{})
 	jobListTemplate       = createPage("job_list", UIJobList{})
@@ -0,0 +1,137 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/corpus"
+)
+
+// Lineage is the parent->child graph of corpus programs built from their provenance
+// (see corpus.Provenance.Parent). Parents that are no longer in the corpus
+// (e.g. were removed during corpus minimization) are present with InCorpus=false.
+type Lineage struct {
+	Nodes []*LineageNode `json:"nodes"`
+}
+
+type LineageNode struct {
+	Sig        string `json:"sig"`
+	InCorpus   bool   `json:"in_corpus"`
+	Call       string `json:"call,omitempty"`
+	Provenance string `json:"provenance,omitempty"`
+	Parent     string `json:"parent,omitempty"`
+	Signal     int    `json:"signal"`
+	// Number of direct children and all descendants of the program in the corpus.
+	// Programs with lots of descendants are the most productive ancestors.
+	Children    int `json:"children"`
+	Descendants int `json:"descendants"`
+
+	children []*LineageNode
+}
+
+// BuildLineage builds the lineage graph of the corpus items.
+// Nodes are sorted by the number of descendants.
+func BuildLineage(items []*corpus.Item) *Lineage {
+	nodes := make(map[string]*LineageNode)
+	for _, item := range items {
+		nodes[item.Sig] = &LineageNode{
+			Sig:        item.Sig,
+			InCorpus:   true,
+			Call:       item.StringCall(),
+			Provenance: item.Provenance.String(),
+			Parent:     item.Provenance.Parent,
+			Signal:     item.Signal.Len(),
+		}
+	}
+	for _, item := range items {
+		parentSig := item.Provenance.Parent
+		if parentSig == "" {
+			continue
+		}
+		parent := nodes[parentSig]
+		if parent == nil {
+			parent = &LineageNode{Sig: parentSig}
+			nodes[parentSig] = parent
+		}
+		parent.children = append(parent.children, nodes[item.Sig])
+		parent.Children++
+	}
+	// Provenance of restored programs may form cycles (a program may be removed from the corpus
+	// and later found again as a descendant of its own child), so we need to be careful.
+	visiting := make(map[*LineageNode]bool)
+	done := make(map[*LineageNode]bool)
+	var countDescendants func(node *LineageNode) int
+	countDescendants = func(node *LineageNode) int {
+		if done[node] || visiting[node] {
+			return node.Descendants
+		}
+		visiting[node] = true
+		for _, child := range node.children {
+			if visiting[child] {
+				continue
+			}
+			node.Descendants += 1 + countDescendants(child)
+		}
+		visiting[node] = false
+		done[node] = true
+		return node.Descendants
+	}
+	res := &Lineage{}
+	for _, node := range nodes {
+		countDescendants(node)
+		res.Nodes = append(res.Nodes, node)
+	}
+	sort.Slice(res.Nodes, func(i, j int) bool {
+		a, b := res.Nodes[i], res.Nodes[j]
+		if a.Descendants != b.Descendants {
+			return a.Descendants > b.Descendants
+		}
+		return a.Sig < b.Sig
+	})
+	return res
+}
+
+// Filter leaves only nodes with at least minDescendants descendants.
+// Since ancestors have more descendants than their children, the result stays connected.
+func (l *Lineage) Filter(minDescendants int) *Lineage {
+	res := &Lineage{}
+	for _, node := range l.Nodes {
+		if node.Descendants >= minDescendants {
+			res.Nodes = append(res.Nodes, node)
+		}
+	}
+	return res
+}
+
+// WriteDOT writes the graph in the graphviz DOT format.
+func (l *Lineage) WriteDOT(w io.Writer) error {
+	present := make(map[string]bool)
+	for _, node := range l.Nodes {
+		present[node.Sig] = true
+	}
+	fmt.Fprintf(w, "digraph lineage {\n")
+	fmt.Fprintf(w, "\tnode [shape=box];\n")
+	for _, node := range l.Nodes {
+		label := fmt.Sprintf("%.8v\n%v\n%v", node.Sig, node.Call, node.Provenance)
+		if !node.InCorpus {
+			label = fmt.Sprintf("%.8v\n(removed)", node.Sig)
+		}
+		label += fmt.Sprintf("\ndescendants: %v", node.Descendants)
+		style := ""
+		if !node.InCorpus {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(w, "\t%q [label=%q%v];\n", node.Sig, label, style)
+	}
+	for _, node := range l.Nodes {
+		if node.Parent != "" && present[node.Parent] {
+			fmt.Fprintf(w, "\t%q -> %q;\n", node.Parent, node.Sig)
+		}
+	}
+	_, err := fmt.Fprintf(w, "}\n")
+	return err
+}
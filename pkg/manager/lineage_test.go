@@ -0,0 +1,89 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineage(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("test$int(0x0, 0x0, 0x0, 0x0, 0x0)\n"), prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := func(sig, job, parent string) *corpus.Item {
+		return &corpus.Item{
+			Sig:        sig,
+			Prog:       p,
+			Provenance: corpus.Provenance{Job: job, Parent: parent},
+		}
+	}
+	// The graph (removed is no longer in the corpus):
+	// gen -> a -> b -> c
+	//          \-> d
+	// removed -> e
+	// hub
+	lineage := BuildLineage([]*corpus.Item{
+		item("gen", corpus.ProvenanceGenerate, ""),
+		item("a", corpus.ProvenanceMutate, "gen"),
+		item("b", corpus.ProvenanceMutate, "a"),
+		item("c", corpus.ProvenanceHints, "b"),
+		item("d", corpus.ProvenanceSmash, "a"),
+		item("e", corpus.ProvenanceMutate, "removed"),
+		item("hub", corpus.ProvenanceHub, ""),
+	})
+	type result struct {
+		sig         string
+		inCorpus    bool
+		children    int
+		descendants int
+	}
+	var got []result
+	for _, node := range lineage.Nodes {
+		got = append(got, result{node.Sig, node.InCorpus, node.Children, node.Descendants})
+	}
+	assert.Equal(t, []result{
+		{"gen", true, 1, 4},
+		{"a", true, 2, 3},
+		{"b", true, 1, 1},
+		{"removed", false, 1, 1},
+		{"c", true, 0, 0},
+		{"d", true, 0, 0},
+		{"e", true, 0, 0},
+		{"hub", true, 0, 0},
+	}, got)
+
+	filtered := lineage.Filter(2)
+	assert.Len(t, filtered.Nodes, 2)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, filtered.WriteDOT(buf))
+	assert.Contains(t, buf.String(), `"gen" -> "a";`)
+	assert.NotContains(t, buf.String(), `"a" -> "b";`)
+}
+
+func TestLineageCycle(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("test$int(0x0, 0x0, 0x0, 0x0, 0x0)\n"), prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lineage := BuildLineage([]*corpus.Item{
+		{Sig: "a", Prog: p, Provenance: corpus.Provenance{Job: corpus.ProvenanceMutate, Parent: "b"}},
+		{Sig: "b", Prog: p, Provenance: corpus.Provenance{Job: corpus.ProvenanceMutate, Parent: "a"}},
+	})
+	assert.Len(t, lineage.Nodes, 2)
+}
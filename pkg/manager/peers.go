@@ -0,0 +1,390 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/prog"
+)
+
+// Peers implements direct exchange of corpus programs and reproducers between a small group
+// of managers without syz-hub. Every manager listens on PeersConfig.Addr and pushes
+// its new corpus programs and reproducers to all managers from PeersConfig.Peers.
+// After (re)connecting to a peer the whole corpus is sent, later only the delta.
+type Peers struct {
+	cfg  PeersConfig
+	mgr  PeersManagerView
+	serv *flatrpc.Serv
+
+	// Serializes Sync calls.
+	syncMu sync.Mutex
+	// Protects the fields below, it's not held while talking to peers.
+	mu     sync.Mutex
+	peers  map[string]*peerState
+	closed bool
+
+	statSendProg     *stat.Val
+	statRecvProg     *stat.Val
+	statRecvProgDrop *stat.Val
+	statSendRepro    *stat.Val
+	statRecvRepro    *stat.Val
+}
+
+type PeersConfig struct {
+	Name string
+	// Domain has the same meaning as the domain sent to syz-hub ("OS/HubDomain").
+	Domain string
+	// Addr to listen for connections from peers, may be empty if the manager only sends updates.
+	Addr string
+	// Peers are addresses of other managers.
+	Peers []string
+	// Key is shared by all managers in the group, connections with a different key are rejected.
+	Key          string
+	Target       *prog.Target
+	EnabledCalls map[*prog.Syscall]bool
+	// Send the corpus to peers (otherwise only repros are sent).
+	// The corpus is not sent with fake coverage since it's lower quality.
+	SendCorpus bool
+	// Override all received repros to be memory leaks (used on leak-checking instances).
+	Leak bool
+}
+
+// PeersManagerView restricts interface between Peers and the manager.
+type PeersManagerView interface {
+	// PeersCorpus returns the current corpus to share with peers.
+	PeersCorpus() []*corpus.Item
+	// PeersRepros returns reproducers found since the last call.
+	PeersRepros() [][]byte
+	AddPeersCandidates(candidates []fuzzer.Candidate)
+	AddPeersRepro(crash *Crash)
+}
+
+type peerState struct {
+	addr   string
+	conn   *flatrpc.Conn
+	sent   map[string]bool // signatures of programs already sent over conn
+	repros [][]byte        // repros that are not sent yet
+}
+
+func NewPeers(cfg PeersConfig, mgr PeersManagerView) (*Peers, error) {
+	p := &Peers{
+		cfg:   cfg,
+		mgr:   mgr,
+		peers: make(map[string]*peerState),
+
+		statSendProg:     stat.New("peer send prog", "", stat.Graph("peer progs")),
+		statRecvProg:     stat.New("peer recv prog", "", stat.Graph("peer progs")),
+		statRecvProgDrop: stat.New("peer recv prog drop", "", stat.NoGraph),
+		statSendRepro:    stat.New("peer send repro", "", stat.Graph("peer repros")),
+		statRecvRepro:    stat.New("peer recv repro", "", stat.Graph("peer repros")),
+	}
+	for _, addr := range cfg.Peers {
+		p.peers[addr] = &peerState{addr: addr}
+	}
+	if cfg.Addr != "" {
+		serv, err := flatrpc.Listen(cfg.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen for peers on %v: %w", cfg.Addr, err)
+		}
+		p.serv = serv
+	}
+	return p, nil
+}
+
+// Addr returns the actual listening address (useful if PeersConfig.Addr has 0 port).
+func (p *Peers) Addr() string {
+	if p.serv == nil {
+		return ""
+	}
+	return p.serv.Addr.String()
+}
+
+// Loop serves connections from peers and periodically sends updates to them until ctx is cancelled.
+func (p *Peers) Loop(ctx context.Context, period time.Duration) error {
+	if p.serv != nil {
+		log.Logf(0, "serving peer connections on %v", p.Addr())
+		go func() {
+			if err := p.serv.Serve(ctx, p.handleConn); err != nil {
+				log.Errorf("peers server failed: %v", err)
+			}
+		}()
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		p.Sync()
+		select {
+		case <-ctx.Done():
+			p.close()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync sends new corpus programs and repros to all peers.
+func (p *Peers) Sync() {
+	p.syncMu.Lock()
+	defer p.syncMu.Unlock()
+	var items []*corpus.Item
+	if p.cfg.SendCorpus {
+		items = p.mgr.PeersCorpus()
+	}
+	repros := p.mgr.PeersRepros()
+	// Connecting and sending may take long (and block on stalled peers),
+	// so we don't hold the mutex while talking to peers.
+	p.mu.Lock()
+	var disconnected []string
+	for addr, peer := range p.peers {
+		peer.repros = append(peer.repros, repros...)
+		if peer.conn == nil {
+			disconnected = append(disconnected, addr)
+		}
+	}
+	p.mu.Unlock()
+	conns := make(map[string]*flatrpc.Conn)
+	for _, addr := range disconnected {
+		conn, err := p.connect(addr)
+		if err != nil {
+			log.Logf(0, "failed to connect to peer %v: %v", addr, err)
+			continue
+		}
+		conns[addr] = conn
+	}
+	p.mu.Lock()
+	for addr, conn := range conns {
+		if p.closed {
+			conn.Close()
+			continue
+		}
+		peer := p.peers[addr]
+		peer.conn = conn
+		peer.sent = make(map[string]bool)
+	}
+	var connected []*peerState
+	for _, peer := range p.peers {
+		if peer.conn != nil {
+			connected = append(connected, peer)
+		}
+	}
+	p.mu.Unlock()
+	for _, peer := range connected {
+		p.syncPeer(peer, items)
+	}
+}
+
+func (p *Peers) connect(addr string) (*flatrpc.Conn, error) {
+	netConn, err := net.DialTimeout("tcp", addr, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	conn := flatrpc.NewConn(netConn)
+	if err := flatrpc.Send(conn, &flatrpc.PeerHello{
+		Name:   p.cfg.Name,
+		Domain: p.cfg.Domain,
+		Key:    p.cfg.Key,
+	}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	log.Logf(0, "connected to peer %v", addr)
+	return conn, nil
+}
+
+func (p *Peers) syncPeer(peer *peerState, items []*corpus.Item) {
+	p.mu.Lock()
+	conn := peer.conn
+	var sigs []string
+	var progs []string
+	for _, item := range items {
+		if !peer.sent[item.Sig] {
+			sigs = append(sigs, item.Sig)
+			progs = append(progs, string(item.Prog.Serialize()))
+		}
+	}
+	var repros []string
+	for _, repro := range peer.repros {
+		repros = append(repros, string(repro))
+	}
+	p.mu.Unlock()
+	if conn == nil {
+		return
+	}
+	// Send the corpus in batches to not hit the RPC message size limit.
+	for len(progs)+len(repros) != 0 {
+		msg := &flatrpc.PeerSync{
+			Progs:  progs[:min(len(progs), peerSyncBatch)],
+			Repros: repros,
+		}
+		err := flatrpc.Send(conn, msg)
+		p.mu.Lock()
+		if err != nil {
+			log.Logf(0, "failed to sync with peer %v: %v", peer.addr, err)
+			conn.Close()
+			if peer.conn == conn {
+				peer.conn = nil
+			}
+			p.mu.Unlock()
+			return
+		}
+		for _, sig := range sigs[:len(msg.Progs)] {
+			peer.sent[sig] = true
+		}
+		peer.repros = peer.repros[len(msg.Repros):]
+		p.mu.Unlock()
+		sigs, progs, repros = sigs[len(msg.Progs):], progs[len(msg.Progs):], nil
+		p.statSendProg.Add(len(msg.Progs))
+		p.statSendRepro.Add(len(msg.Repros))
+		log.Logf(0, "peer %v sync: sent progs %v, repros %v", peer.addr, len(msg.Progs), len(msg.Repros))
+	}
+}
+
+const peerSyncBatch = 1000
+
+func (p *Peers) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, peer := range p.peers {
+		if peer.conn != nil {
+			peer.conn.Close()
+			peer.conn = nil
+		}
+	}
+}
+
+func (p *Peers) handleConn(ctx context.Context, conn *flatrpc.Conn) error {
+	hello, err := flatrpc.Recv[*flatrpc.PeerHelloRaw](conn)
+	if err != nil {
+		log.Logf(0, "failed to receive peer hello: %v", err)
+		return nil
+	}
+	name, domain := hello.Name, hello.Domain
+	if hello.Key != p.cfg.Key {
+		log.Logf(0, "rejecting peer %v: wrong key", name)
+		return nil
+	}
+	log.Logf(0, "peer %v (domain %q) connected", name, domain)
+	for {
+		msg, err := flatrpc.Recv[*flatrpc.PeerSyncRaw](conn)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Logf(0, "peer %v disconnected: %v", name, err)
+			}
+			return nil
+		}
+		progs := p.processProgs(domain, msg.Progs)
+		repros := p.processRepros(msg.Repros)
+		log.Logf(0, "peer %v sync: recv progs %v/%v, repros %v/%v",
+			name, progs, len(msg.Progs), repros, len(msg.Repros))
+	}
+}
+
+func (p *Peers) processProgs(domain string, progs []string) int {
+	var candidates []fuzzer.Candidate
+	for _, data := range progs {
+		prog, err := p.parseProgram([]byte(data))
+		if err != nil {
+			log.Logf(1, "rejecting program from peer: %v\n%s", err, data)
+			p.statRecvProgDrop.Add(1)
+			continue
+		}
+		var flags fuzzer.ProgFlags
+		minimized, smashed := MatchDomains(p.cfg.Domain, domain)
+		if minimized && len(prog.Calls) < ReminimizeThreshold {
+			flags |= fuzzer.ProgMinimized
+		}
+		if smashed {
+			flags |= fuzzer.ProgSmashed
+		}
+		candidates = append(candidates, fuzzer.Candidate{
+			Prog:       prog,
+			Flags:      flags,
+			Provenance: corpus.Provenance{Job: corpus.ProvenancePeer},
+		})
+	}
+	if len(candidates) != 0 {
+		p.mgr.AddPeersCandidates(candidates)
+	}
+	p.statRecvProg.Add(len(candidates))
+	return len(candidates)
+}
+
+func (p *Peers) processRepros(repros []string) int {
+	accepted := 0
+	for _, repro := range repros {
+		if _, err := p.parseProgram([]byte(repro)); err != nil {
+			log.Logf(0, "rejecting repro from peer: %v\n%s", err, repro)
+			continue
+		}
+		// On leak instances we override the repro type to leak, otherwise repro
+		// won't enable leak detection and we won't reproduce leaks from other instances.
+		typ := crash.UnknownType
+		if p.cfg.Leak {
+			typ = crash.MemoryLeak
+		}
+		p.mgr.AddPeersRepro(&Crash{
+			FromHub: true,
+			Report: &report.Report{
+				Type:   typ,
+				Output: []byte(repro),
+			},
+		})
+		accepted++
+	}
+	p.statRecvRepro.Add(accepted)
+	return accepted
+}
+
+func (p *Peers) parseProgram(data []byte) (*prog.Prog, error) {
+	prog, err := ParseSeed(p.cfg.Target, data)
+	if err != nil {
+		return nil, err
+	}
+	if !prog.OnlyContains(p.cfg.EnabledCalls) {
+		return nil, fmt.Errorf("contains disabled calls")
+	}
+	return prog, nil
+}
+
+// MatchDomains says if a program received from a manager in the input domain
+// was already minimized and smashed in the self domain.
+func MatchDomains(self, input string) (bool, bool) {
+	if self == "" || input == "" {
+		return true, true
+	}
+	min0, smash0 := splitDomains(self)
+	min1, smash1 := splitDomains(input)
+	min := min0 != min1
+	smash := min || smash0 != smash1
+	return min, smash
+}
+
+func splitDomains(domain string) (string, string) {
+	delim0 := strings.IndexByte(domain, '/')
+	if delim0 == -1 {
+		return domain, ""
+	}
+	if delim0 == len(domain)-1 {
+		return domain[:delim0], ""
+	}
+	delim1 := strings.IndexByte(domain[delim0+1:], '/')
+	if delim1 == -1 {
+		return domain, ""
+	}
+	return domain[:delim0+delim1+1], domain[delim0+delim1+2:]
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/fuzzer"
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeers(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := make(map[*prog.Syscall]bool)
+	for _, call := range target.Syscalls {
+		enabled[call] = true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newPeers := func(name, key string, peers []string, view *testPeersView) *Peers {
+		p, err := NewPeers(PeersConfig{
+			Name:         name,
+			Domain:       targets.TestOS + "/" + name,
+			Addr:         "127.0.0.1:0",
+			Peers:        peers,
+			Key:          key,
+			Target:       target,
+			EnabledCalls: enabled,
+			SendCorpus:   true,
+			Leak:         true,
+		}, view)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go p.Loop(ctx, time.Hour)
+		return p
+	}
+	viewA, viewB, viewC := new(testPeersView), new(testPeersView), new(testPeersView)
+	peersB := newPeers("b", "key", nil, viewB)
+	peersA := newPeers("a", "key", []string{peersB.Addr()}, viewA)
+	// C does not know the key, so B must ignore it.
+	peersC := newPeers("c", "wrong", []string{peersB.Addr()}, viewC)
+
+	item := func(sig, text string) *corpus.Item {
+		p, err := target.Deserialize([]byte(text), prog.NonStrict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &corpus.Item{Sig: sig, Prog: p}
+	}
+	viewA.add([]*corpus.Item{
+		item("1", "test$int(0x1, 0x0, 0x0, 0x0, 0x0)\n"),
+		item("2", "test$int(0x2, 0x0, 0x0, 0x0, 0x0)\n"),
+	}, []byte("test$int(0x3, 0x0, 0x0, 0x0, 0x0)\n"))
+	viewC.add([]*corpus.Item{item("5", "test$int(0x5, 0x0, 0x0, 0x0, 0x0)\n")},
+		[]byte("test$int(0x6, 0x0, 0x0, 0x0, 0x0)\n"))
+	peersC.Sync()
+	peersA.Sync()
+	viewB.wait(t, 2, 1)
+
+	// Only new programs are sent on the next sync.
+	viewA.add([]*corpus.Item{item("4", "test$int(0x4, 0x0, 0x0, 0x0, 0x0)\n")}, nil)
+	peersA.Sync()
+	viewB.wait(t, 3, 1)
+
+	viewB.mu.Lock()
+	defer viewB.mu.Unlock()
+	for _, cand := range viewB.candidates {
+		assert.Equal(t, corpus.ProvenancePeer, cand.Provenance.Job)
+		assert.Equal(t, fuzzer.ProgMinimized|fuzzer.ProgSmashed, cand.Flags)
+	}
+	assert.Equal(t, "test$int(0x4, 0x0, 0x0, 0x0, 0x0)\n", string(viewB.candidates[2].Prog.Serialize()))
+	assert.True(t, viewB.repros[0].FromHub)
+	assert.Equal(t, crash.MemoryLeak, viewB.repros[0].Report.Type)
+}
+
+type testPeersView struct {
+	mu         sync.Mutex
+	corpus     []*corpus.Item
+	newRepros  [][]byte
+	candidates []fuzzer.Candidate
+	repros     []*Crash
+}
+
+func (v *testPeersView) add(items []*corpus.Item, repro []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.corpus = append(v.corpus, items...)
+	if repro != nil {
+		v.newRepros = append(v.newRepros, repro)
+	}
+}
+
+func (v *testPeersView) wait(t *testing.T, candidates, repros int) {
+	for start := time.Now(); time.Since(start) < time.Minute; time.Sleep(10 * time.Millisecond) {
+		v.mu.Lock()
+		done := len(v.candidates) >= candidates && len(v.repros) >= repros
+		v.mu.Unlock()
+		if done {
+			break
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	assert.Len(t, v.candidates, candidates)
+	assert.Len(t, v.repros, repros)
+}
+
+func (v *testPeersView) PeersCorpus() []*corpus.Item {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]*corpus.Item{}, v.corpus...)
+}
+
+func (v *testPeersView) PeersRepros() [][]byte {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	repros := v.newRepros
+	v.newRepros = nil
+	return repros
+}
+
+func (v *testPeersView) AddPeersCandidates(candidates []fuzzer.Candidate) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.candidates = append(v.candidates, candidates...)
+}
+
+func (v *testPeersView) AddPeersRepro(crash *Crash) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.repros = append(v.repros, crash)
+}
+
+func TestMatchDomains(t *testing.T) {
+	type Test struct {
+		self      string
+		input     string
+		minimized bool
+		smashed   bool
+	}
+	tests := []Test{
+		{"", "", true, true},
+		{"linux", "", true, true},
+		{"linux/", "", true, true},
+		{"linux/upstream/kasan", "", true, true},
+		{"", "linux", true, true},
+		{"", "linux/", true, true},
+		{"linux", "linux/", false, false},
+		{"linux/", "linux/", false, false},
+		{"linux", "linuz", true, true},
+		{"linux/upstream/kasan", "linuz", true, true},
+		{"linux/upstream", "linux/upstream", false, false},
+		{"linux/upstream", "linux/upstreax", true, true},
+		{"linux/upstream/", "linux/upstream", false, false},
+		{"linux/upstream", "linux/upstreax/", true, true},
+		{"linux/upstream", "linux/upstream/kasan", false, true},
+		{"linux/upstream/kasan", "linux/upstream", false, true},
+		{"linux/upstream/kasan", "linux/upstream/xasan", false, true},
+		{"linux/upstream/kasan", "linux/upstream/kasan", false, false},
+		{"linux/upstreax/kasan", "linux/upstream/kasan", true, true},
+		{"linux/upstreax/kasan", "linux/upstream/xasan", true, true},
+		{"linux/upstream/kasan", "linuz/upstream/xasan", true, true},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			minimized, smashed := MatchDomains(test.self, test.input)
+			if minimized != test.minimized || smashed != test.smashed {
+				t.Fatalf("(%q, %q) = %v/%v, want %v/%v",
+					test.self, test.input, minimized, smashed, test.minimized, test.smashed)
+			}
+		})
+	}
+}
@@ -29,6 +29,9 @@ type Crash struct {
 	FromHub       bool // this crash was created based on a repro from syz-hub
 	FromDashboard bool // .. or from dashboard
 	Manual        bool
+	// Recorded executor session of the crashed VM (see rpcserver.ReadSession).
+	// SaveCrash moves the file into the crash dir.
+	SessionFile string
 	*report.Report
 }
 
@@ -6,6 +6,7 @@ package manager
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -16,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/syzkaller/pkg/corpus"
 	"github.com/google/syzkaller/pkg/db"
 	"github.com/google/syzkaller/pkg/fuzzer"
 	"github.com/google/syzkaller/pkg/hash"
@@ -26,9 +28,12 @@ import (
 )
 
 type Seeds struct {
-	CorpusDB   *db.DB
-	Fresh      bool
-	Candidates []fuzzer.Candidate
+	CorpusDB     *db.DB
+	ProvenanceDB *db.DB
+	Fresh        bool
+	Candidates   []fuzzer.Candidate
+	SeedStats    map[string]corpus.SeedStats
+	Provenance   map[string]corpus.Provenance
 }
 
 func LoadSeeds(cfg *mgrconfig.Config, immutable bool) (Seeds, error) {
@@ -42,6 +47,12 @@ func LoadSeeds(cfg *mgrconfig.Config, immutable bool) (Seeds, error) {
 		log.Errorf("read %v inputs from corpus and got error: %v", len(info.CorpusDB.Records), err)
 	}
 	info.Fresh = len(info.CorpusDB.Records) == 0
+	info.ProvenanceDB, info.Provenance, err = loadJSONDB[corpus.Provenance](cfg.Workdir,
+		provenanceDBFile, immutable)
+	if err != nil {
+		return Seeds{}, err
+	}
+	info.SeedStats = loadSeedStats(info.CorpusDB)
 	corpusFlags := versionToFlags(info.CorpusDB.Version)
 	outputs := make(chan *input, 32)
 	chErr := make(chan error, 1)
@@ -70,6 +81,7 @@ func LoadSeeds(cfg *mgrconfig.Config, immutable bool) (Seeds, error) {
 			continue
 		}
 		flags := corpusFlags
+		provenance := corpus.ProvenanceCorpus
 		if inp.IsSeed {
 			if _, ok := info.CorpusDB.Records[hash.String(inp.Prog.Serialize())]; ok {
 				continue
@@ -77,10 +89,12 @@ func LoadSeeds(cfg *mgrconfig.Config, immutable bool) (Seeds, error) {
 			// Seeds are not considered "from corpus" (won't be rerun multiple times)
 			// b/c they are tried on every start anyway.
 			flags = fuzzer.ProgMinimized
+			provenance = corpus.ProvenanceSeed
 		}
 		candidates = append(candidates, fuzzer.Candidate{
-			Prog:  inp.Prog,
-			Flags: flags,
+			Prog:       inp.Prog,
+			Flags:      flags,
+			Provenance: corpus.Provenance{Job: provenance},
 		})
 	}
 	if err := <-chErr; err != nil {
@@ -108,6 +122,100 @@ func LoadSeeds(cfg *mgrconfig.Config, immutable bool) (Seeds, error) {
 	return info, nil
 }
 
+// Provenance of corpus programs is stored in a separate database next to corpus.db,
+// since corpus.db records are keyed by program hashes and contain only the program text.
+// The records are keyed by program signatures as well and contain JSON-encoded values.
+const provenanceDBFile = "provenance.db"
+
+func loadJSONDB[T any](workdir, file string, immutable bool) (*db.DB, map[string]T, error) {
+	jsonDB, err := db.Open(filepath.Join(workdir, file), !immutable)
+	if err != nil {
+		if jsonDB == nil {
+			return nil, nil, fmt.Errorf("failed to open %v: %w", file, err)
+		}
+		log.Errorf("failed to read %v: %v", file, err)
+	}
+	res := make(map[string]T)
+	for key, rec := range jsonDB.Records {
+		var val T
+		if err := json.Unmarshal(rec.Val, &val); err != nil {
+			log.Errorf("broken %v record %v: %v", file, key, err)
+			continue
+		}
+		res[key] = val
+	}
+	return jsonDB, res, nil
+}
+
+func saveJSON(jsonDB *db.DB, key string, val any) {
+	data, err := json.Marshal(val)
+	if err != nil {
+		panic(err)
+	}
+	jsonDB.Save(key, data, 0)
+}
+
+// SaveProvenance adds the provenance of the corpus program with the signature sig to the database.
+// The caller is responsible for flushing the database.
+func SaveProvenance(provenanceDB *db.DB, sig string, provenance corpus.Provenance) {
+	if provenance.Job == "" {
+		return
+	}
+	saveJSON(provenanceDB, sig, provenance)
+}
+
+// Seed stats of corpus programs are stored in corpus.db as JSON-encoded meta of the program records,
+// so they are deleted along with the programs and are not affected by DiscardData.
+func loadSeedStats(corpusDB *db.DB) map[string]corpus.SeedStats {
+	res := make(map[string]corpus.SeedStats)
+	for key, val := range corpusDB.Meta {
+		var stats corpus.SeedStats
+		if err := json.Unmarshal(val, &stats); err != nil {
+			log.Errorf("broken seed stats of corpus record %v: %v", key, err)
+			continue
+		}
+		res[key] = stats
+	}
+	return res
+}
+
+// SaveSeedStats updates seed stats of the corpus items in the corpus database.
+// Only items whose stats have changed substantially since they were saved are rewritten,
+// so that the database does not grow on every call. Returns the number of updated items.
+// The caller is responsible for flushing the database.
+func SaveSeedStats(corpusDB *db.DB, items []*corpus.Item) int {
+	saved := 0
+	for _, item := range items {
+		stats := item.SeedStats()
+		if stats == (corpus.SeedStats{}) {
+			continue
+		}
+		if _, ok := corpusDB.Records[item.Sig]; !ok {
+			// The program is not saved yet (or is being deleted).
+			continue
+		}
+		if val, ok := corpusDB.Meta[item.Sig]; ok {
+			var old corpus.SeedStats
+			if json.Unmarshal(val, &old) == nil && !seedStatsChanged(old, stats) {
+				continue
+			}
+		}
+		data, err := json.Marshal(stats)
+		if err != nil {
+			panic(err)
+		}
+		corpusDB.SaveMeta(item.Sig, data)
+		saved++
+	}
+	return saved
+}
+
+// seedStatsChanged says if the persisted seed stats are stale enough to be rewritten.
+func seedStatsChanged(old, cur corpus.SeedStats) bool {
+	return old.NewSignal != cur.NewSignal ||
+		cur.Mutations >= old.Mutations+100 && cur.Mutations >= old.Mutations+old.Mutations/4
+}
+
 type input struct {
 	IsSeed bool
 	Key    string
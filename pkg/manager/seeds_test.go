@@ -4,7 +4,19 @@
 package manager
 
 import (
+	"context"
+	"math/rand"
+	"path/filepath"
 	"testing"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRequires(t *testing.T) {
@@ -27,3 +39,45 @@ func TestRequires(t *testing.T) {
 		}
 	}
 }
+
+func TestSeedStats(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	require.NoError(t, err)
+	rnd := rand.New(testutil.RandSource(t))
+	crp := corpus.NewCorpus(context.Background())
+	crp.Save(corpus.NewInput{
+		Prog:   target.Generate(rnd, 5, target.DefaultChoiceTable()),
+		Signal: signal.FromRaw([]uint64{1, 2, 3}, 0),
+	})
+	item := crp.Items()[0]
+
+	dbFile := filepath.Join(t.TempDir(), "corpus.db")
+	corpusDB, err := db.Open(dbFile, false)
+	require.NoError(t, err)
+	item.RecordMutation(true)
+	// Items that are not in the database are not saved.
+	assert.Equal(t, 0, SaveSeedStats(corpusDB, crp.Items()))
+	corpusDB.Save(item.Sig, item.Prog.Serialize(), 0)
+	assert.Equal(t, 1, SaveSeedStats(corpusDB, crp.Items()))
+	assert.Equal(t, 0, SaveSeedStats(corpusDB, crp.Items()))
+	// A few more mutations are not worth rewriting the record.
+	for i := 0; i < 10; i++ {
+		item.RecordMutation(false)
+	}
+	assert.Equal(t, 0, SaveSeedStats(corpusDB, crp.Items()))
+	item.RecordMutation(true)
+	assert.Equal(t, 1, SaveSeedStats(corpusDB, crp.Items()))
+	require.NoError(t, corpusDB.Flush())
+
+	corpusDB, err = db.Open(dbFile, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]corpus.SeedStats{
+		item.Sig: {Mutations: 12, NewSignal: 2},
+	}, loadSeedStats(corpusDB))
+	// Stats are deleted along with the program.
+	corpusDB.Delete(item.Sig)
+	require.NoError(t, corpusDB.Flush())
+	corpusDB, err = db.Open(dbFile, false)
+	require.NoError(t, err)
+	assert.Empty(t, loadSeedStats(corpusDB))
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm"
+)
+
+// SnapshotConfig describes execution of programs in the snapshot mode (see mgrconfig.Config.Snapshot).
+type SnapshotConfig struct {
+	Cfg      *mgrconfig.Config
+	Reporter *report.Reporter
+	// Features enabled in the executor.
+	Features flatrpc.Feature
+	// Programs are taken from the source (the VM index is used as the executor ID).
+	Source *queue.Distributor
+	// Crashes found during execution of programs are sent to the channel.
+	Crashes   chan<- *Crash
+	StatExecs *stat.Val
+}
+
+// RunSnapshotLoop starts the executor in the VM, snapshots the VM and then executes programs
+// from the source restoring the VM from the snapshot before each program.
+// It returns when the context is cancelled or on the first VM error.
+func RunSnapshotLoop(ctx context.Context, inst *vm.Instance, cfg *SnapshotConfig) error {
+	executor, err := inst.Copy(cfg.Cfg.ExecutorBin)
+	if err != nil {
+		return err
+	}
+	// All network connections (including ssh) will break once we start restoring snapshots.
+	// So we start a background process and log to /dev/kmsg.
+	cmd := fmt.Sprintf("nohup %v exec snapshot 1>/dev/null 2>/dev/kmsg </dev/null &", executor)
+	ctxTimeout, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+	if _, _, err := inst.Run(ctxTimeout, cfg.Reporter, cmd); err != nil {
+		return err
+	}
+
+	builder := flatbuffers.NewBuilder(0)
+	var envFlags flatrpc.ExecEnv
+	for first := true; ctx.Err() == nil; first = false {
+		cfg.StatExecs.Add(1)
+		req := cfg.Source.Next(inst.Index())
+		if first {
+			envFlags = req.ExecOpts.EnvFlags
+			if err := snapshotSetup(cfg, inst, builder, envFlags); err != nil {
+				req.Done(&queue.Result{Status: queue.Crashed})
+				return err
+			}
+		}
+		if envFlags != req.ExecOpts.EnvFlags {
+			panic(fmt.Sprintf("request env flags has changed: 0x%x -> 0x%x",
+				envFlags, req.ExecOpts.EnvFlags))
+		}
+
+		res, output, err := snapshotRun(inst, builder, req)
+		if err != nil {
+			req.Done(&queue.Result{Status: queue.Crashed})
+			return err
+		}
+
+		if cfg.Reporter.ContainsCrash(output) {
+			res.Status = queue.Crashed
+			rep := cfg.Reporter.Parse(output)
+			buf := new(bytes.Buffer)
+			fmt.Fprintf(buf, "program:\n%s\n", req.Prog.Serialize())
+			buf.Write(rep.Output)
+			rep.Output = buf.Bytes()
+			cfg.Crashes <- &Crash{Report: rep}
+		}
+
+		req.Done(res)
+	}
+	return nil
+}
+
+func snapshotSetup(cfg *SnapshotConfig, inst *vm.Instance, builder *flatbuffers.Builder,
+	env flatrpc.ExecEnv) error {
+	msg := flatrpc.SnapshotHandshakeT{
+		CoverEdges:       cfg.Cfg.Experimental.CoverEdges && cfg.Cfg.Type != targets.GVisor,
+		Kernel64Bit:      cfg.Cfg.SysTarget.PtrSize == 8,
+		Slowdown:         int32(cfg.Cfg.Timeouts.Slowdown),
+		SyscallTimeoutMs: int32(cfg.Cfg.Timeouts.Syscall / time.Millisecond),
+		ProgramTimeoutMs: int32(cfg.Cfg.Timeouts.Program / time.Millisecond),
+		Features:         cfg.Features,
+		EnvFlags:         env,
+		SandboxArg:       cfg.Cfg.SandboxArg,
+	}
+	builder.Reset()
+	builder.Finish(msg.Pack(builder))
+	return inst.SetupSnapshot(builder.FinishedBytes())
+}
+
+func snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder, req *queue.Request) (
+	*queue.Result, []byte, error) {
+	progData, err := req.Prog.SerializeForExec()
+	if err != nil {
+		queue.StatExecBufferTooSmall.Add(1)
+		return &queue.Result{
+			Status: queue.ExecFailure,
+			Err:    fmt.Errorf("program serialization failed: %w", err),
+		}, nil, nil
+	}
+	msg := flatrpc.SnapshotRequestT{
+		ExecFlags: req.ExecOpts.ExecFlags,
+		NumCalls:  int32(len(req.Prog.Calls)),
+		ProgData:  progData,
+	}
+	for _, call := range req.ReturnAllSignal {
+		if call < 0 {
+			msg.AllExtraSignal = true
+		} else {
+			msg.AllCallSignal |= 1 << call
+		}
+	}
+	builder.Reset()
+	builder.Finish(msg.Pack(builder))
+
+	start := time.Now()
+	resData, output, err := inst.RunSnapshot(builder.FinishedBytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	elapsed := time.Since(start)
+
+	res := parseExecResult(resData)
+	if res.Info != nil {
+		res.Info.Elapsed = uint64(elapsed)
+		for len(res.Info.Calls) < len(req.Prog.Calls) {
+			res.Info.Calls = append(res.Info.Calls, &flatrpc.CallInfo{
+				Error: 999,
+			})
+		}
+		res.Info.Calls = res.Info.Calls[:len(req.Prog.Calls)]
+		if len(res.Info.ExtraRaw) != 0 {
+			res.Info.Extra = res.Info.ExtraRaw[0]
+			for _, info := range res.Info.ExtraRaw[1:] {
+				res.Info.Extra.Cover = append(res.Info.Extra.Cover, info.Cover...)
+				res.Info.Extra.Signal = append(res.Info.Extra.Signal, info.Signal...)
+			}
+			res.Info.ExtraRaw = nil
+		}
+	}
+
+	ret := &queue.Result{
+		Status: queue.Success,
+		Info:   res.Info,
+	}
+	if res.Error != "" {
+		ret.Status = queue.ExecFailure
+		ret.Err = errors.New(res.Error)
+	}
+	if req.ReturnOutput {
+		ret.Output = output
+	}
+	return ret, output, nil
+}
+
+func parseExecResult(data []byte) *flatrpc.ExecResult {
+	if len(data) < flatbuffers.SizeUint32 {
+		return &flatrpc.ExecResult{
+			Error: "the buffer is too small",
+		}
+	}
+	raw, err := flatrpc.Parse[*flatrpc.ExecutorMessageRaw](data[flatbuffers.SizeUint32:])
+	if err != nil {
+		// Don't consider result parsing error as an infrastructure error,
+		// it's just the test program corrupted memory.
+		return &flatrpc.ExecResult{
+			Error: err.Error(),
+		}
+	}
+	res, ok := raw.Msg.Value.(*flatrpc.ExecResult)
+	if !ok {
+		return &flatrpc.ExecResult{
+			Error: "result is not ExecResult",
+		}
+	}
+	return res
+}
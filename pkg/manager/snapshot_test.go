@@ -0,0 +1,158 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm"
+	"github.com/google/syzkaller/vm/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotLoop(t *testing.T) {
+	var commands []string
+	var handshake *flatrpc.SnapshotHandshakeT
+	execs := 0
+	fake.Register("snapshot-test", fake.Config{
+		Run: func(inst *fake.Instance, command string) ([]byte, error) {
+			commands = append(commands, command)
+			return nil, nil
+		},
+		Setup: func(inst *fake.Instance, state fake.State, input []byte) error {
+			handshake = flatrpc.GetRootAsSnapshotHandshake(input, 0).UnPack()
+			state["files"] = 1
+			return nil
+		},
+		Exec: func(inst *fake.Instance, state fake.State, input []byte) ([]byte, []byte) {
+			execs++
+			req := flatrpc.GetRootAsSnapshotRequest(input, 0).UnPack()
+			// Each program creates a file, but it must not be visible to the next programs.
+			state["files"]++
+			info := &flatrpc.ProgInfo{}
+			for i := 0; i < int(req.NumCalls); i++ {
+				info.Calls = append(info.Calls, &flatrpc.CallInfo{
+					Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished,
+					Error: int32(state["files"]),
+				})
+			}
+			var output []byte
+			if execs == 2 {
+				output = []byte("BUG: bad thing happened\n")
+			}
+			return snapshotResult(info), output
+		},
+	})
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:     targets.Linux,
+			TargetArch:   targets.AMD64,
+			TargetVMArch: targets.AMD64,
+			Timeouts: targets.Timeouts{
+				Scale:    1,
+				Slowdown: 1,
+				Syscall:  50 * time.Millisecond,
+				Program:  5 * time.Second,
+				NoOutput: 5 * time.Second,
+			},
+			SysTarget:   targets.Get(targets.Linux, targets.AMD64),
+			ExecutorBin: "/tmp/syz-executor",
+		},
+		Workdir: t.TempDir(),
+		Type:    "snapshot-test",
+	}
+	pool, err := vm.Create(cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	inst, err := pool.Create(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inst.Close()
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("test()\ntest$res0()\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const numProgs = 3
+	var reqs []*queue.Request
+	source := queue.Callback(func() *queue.Request {
+		req := &queue.Request{
+			Prog: p.Clone(),
+			ExecOpts: flatrpc.ExecOpts{
+				EnvFlags: flatrpc.ExecEnvSandboxNone,
+			},
+		}
+		reqs = append(reqs, req)
+		if len(reqs) == numProgs {
+			cancel()
+		}
+		return req
+	})
+	crashes := make(chan *Crash, numProgs)
+	err = RunSnapshotLoop(ctx, inst, &SnapshotConfig{
+		Cfg:       cfg,
+		Reporter:  reporter,
+		Source:    queue.Distribute(source),
+		Crashes:   crashes,
+		StatExecs: stat.New("snapshot test execs", "", stat.NoGraph),
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"nohup /syz-executor exec snapshot 1>/dev/null 2>/dev/kmsg </dev/null &"}, commands)
+	assert.Equal(t, flatrpc.ExecEnvSandboxNone, handshake.EnvFlags)
+	assert.Equal(t, int32(cfg.Timeouts.Program/time.Millisecond), handshake.ProgramTimeoutMs)
+	assert.Equal(t, numProgs, execs)
+	for i, req := range reqs {
+		res := req.Wait(context.Background())
+		wantStatus := queue.Success
+		if i == 1 {
+			wantStatus = queue.Crashed
+		}
+		assert.Equal(t, wantStatus, res.Status, "program #%v", i)
+		// All programs observe the same snapshotted state.
+		for _, call := range res.Info.Calls {
+			assert.Equal(t, int32(2), call.Error)
+		}
+	}
+	assert.Len(t, crashes, 1)
+	crash := <-crashes
+	assert.Equal(t, "BUG: bad thing happened", crash.Title)
+	assert.Contains(t, string(crash.Output), fmt.Sprintf("program:\n%s", p.Serialize()))
+}
+
+func snapshotResult(info *flatrpc.ProgInfo) []byte {
+	msg := &flatrpc.ExecutorMessage{
+		Msg: &flatrpc.ExecutorMessages{
+			Type:  flatrpc.ExecutorMessagesRawExecResult,
+			Value: &flatrpc.ExecResult{Info: info},
+		},
+	}
+	builder := flatbuffers.NewBuilder(0)
+	builder.FinishSizePrefixed(msg.Pack(builder))
+	return builder.FinishedBytes()
+}
@@ -92,6 +92,16 @@ type Config struct {
 	//  - "4.19/kasan"
 	HubDomain string `json:"hub_domain,omitempty"`
 
+	// Direct exchange of corpus programs and reproducers with a small group of managers
+	// without running syz-hub (optional, can't be used together with hub_client).
+	// PeersAddr is the address to listen for connections from other managers (e.g. ":56789"),
+	// Peers are addresses of other managers in the group (their peers_addr).
+	// PeersKey is a secret shared by all managers in the group, connections with a wrong key are rejected.
+	// hub_domain is respected for programs received from peers.
+	PeersAddr string   `json:"peers_addr,omitempty"`
+	Peers     []string `json:"peers,omitempty"`
+	PeersKey  string   `json:"peers_key,omitempty"`
+
 	// List of email addresses to receive notifications when bugs are encountered for the first time (optional).
 	// Mailx is the only supported mailer. Please set it up prior to using this function.
 	EmailAddrs []string `json:"email_addrs,omitempty"`
@@ -139,7 +149,7 @@ type Config struct {
 
 	// Enables snapshotting mode. In this mode VM is snapshotted and restarted from the snapshot
 	// before executing each test program. This provides better reproducibility and avoids global
-	// accumulated state. Currently only qemu VMs and Linux support this mode.
+	// accumulated state. The VM type must implement vmimpl.Snapshotter (currently qemu and gvisor on Linux).
 	Snapshot bool `json:"snapshot"`
 
 	// Use KCOV coverage (default: true).
@@ -255,6 +265,37 @@ type Experimental struct {
 	// with an empty Filter, but non-empty weight.
 	// E.g. "focus_areas": [ {"filter": {"files": ["^net"]}, "weight": 10.0}, {"weight": 1.0} ].
 	FocusAreas []FocusArea `json:"focus_areas,omitempty"`
+
+	// SchedulingPolicy controls how the fuzzer chooses between smash jobs, mutation and generation,
+	// and which mutation operators it favours. Supported values:
+	// "fixed" (default) uses a hard-coded schedule,
+	// "bandit" learns the choice online based on the new signal found by every execution.
+	SchedulingPolicy string `json:"scheduling_policy"`
+
+	// Dictionary is a file with interesting values extracted from the kernel binary
+	// with tools/syz-dict. The values are used to mutate data and integer arguments.
+	Dictionary string `json:"dictionary,omitempty"`
+
+	// HintsCache enables a cache of comparison operands collected by hints jobs.
+	// The cached values are used to mutate integer arguments of other programs
+	// of the same syscall. The cache is persisted in workdir/hints-cache.json.
+	HintsCache bool `json:"hints_cache,omitempty"`
+
+	// DirectedTargets are targets for the directed fuzzing mode (syz-manager -mode=directed).
+	// A target is either a kernel function name (e.g. "tcp_v4_rcv"),
+	// or a source line (e.g. "net/ipv4/tcp_input.c:1234").
+	// The fuzzer prefers programs that are closer to the targets in the static call graph.
+	DirectedTargets []string `json:"directed_targets,omitempty"`
+
+	// CallModel enables an n-gram model of call sequences trained on the corpus.
+	// The model is used to choose the next call based on the preceding calls
+	// when generating programs and inserting calls. See tools/syz-callmodel.
+	CallModel bool `json:"call_model,omitempty"`
+
+	// RecordSessions enables recording of all requests sent to VMs and their results
+	// into workdir/sessions. Recorded sessions can be replayed with tools/syz-replay.
+	// Note: this produces lots of data, use only for debugging of hard-to-reproduce bugs.
+	RecordSessions bool `json:"record_sessions,omitempty"`
 }
 
 type FocusArea struct {
@@ -105,6 +105,7 @@ func defaultValues() *Config {
 			RemoteCover:      true,
 			CoverEdges:       true,
 			DescriptionsMode: manualDescriptions,
+			SchedulingPolicy: "fixed",
 		},
 	}
 }
@@ -193,6 +194,17 @@ func Complete(cfg *Config) error {
 	if cfg.FuzzingVMs < 0 {
 		return fmt.Errorf("fuzzing_vms cannot be less than 0")
 	}
+	switch cfg.Experimental.SchedulingPolicy {
+	case "", "bandit", "fixed":
+	default:
+		return fmt.Errorf("config param scheduling_policy must be one of bandit/fixed")
+	}
+	if cfg.Experimental.Dictionary != "" {
+		if !osutil.IsExist(cfg.Experimental.Dictionary) {
+			return fmt.Errorf("bad config param dictionary: can't find %v", cfg.Experimental.Dictionary)
+		}
+		cfg.Experimental.Dictionary = osutil.Abs(cfg.Experimental.Dictionary)
+	}
 
 	var err error
 	cfg.Syscalls, err = ParseEnabledSyscalls(cfg.Target, cfg.EnabledSyscalls, cfg.DisabledSyscalls,
@@ -221,6 +233,14 @@ func (cfg *Config) completeServices() error {
 			return err
 		}
 	}
+	if len(cfg.Peers) != 0 || cfg.PeersAddr != "" {
+		if cfg.HubClient != "" {
+			return fmt.Errorf("peers and hub_client can't be used together")
+		}
+		if err := checkNonEmpty(cfg.Name, "name", cfg.PeersKey, "peers_key"); err != nil {
+			return err
+		}
+	}
 	if cfg.HubDomain != "" &&
 		!regexp.MustCompile(`^[a-zA-Z0-9-_.]{2,50}(/[a-zA-Z0-9-_.]{2,50})?$`).MatchString(cfg.HubDomain) {
 		return fmt.Errorf("bad value for hub_domain")
@@ -0,0 +1,75 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+// Details is a structured representation of a crash report.
+// It's filled in by Parse and is updated by Symbolize (currently only for Linux).
+// All fields are optional, they are present only if the report contains the corresponding info.
+type Details struct {
+	// Stacks in the order they appear in the report.
+	Stacks []*Stack `json:"stacks,omitempty"`
+	// The faulting instruction (e.g. RIP/pc register) if it points into the kernel.
+	IP *Frame `json:"ip,omitempty"`
+	// The bad memory access (for KASAN reports, page faults, etc).
+	Access *MemoryAccess `json:"access,omitempty"`
+	// Register dump in the order it appears in the report.
+	Registers []Register `json:"registers,omitempty"`
+	// The task and CPU context the crash happened in.
+	Context *TaskContext `json:"context,omitempty"`
+}
+
+type StackKind string
+
+const (
+	// The stack of the task where the crash happened.
+	StackCrash StackKind = "crash"
+	// Allocation/free stacks of the accessed heap object.
+	StackAlloc StackKind = "alloc"
+	StackFree  StackKind = "free"
+	// Auxiliary stacks that may be related to the object (e.g. call_rcu/queue_work calls).
+	StackAux StackKind = "aux"
+)
+
+type Stack struct {
+	Kind StackKind `json:"kind"`
+	// The task that allocated/freed the object (for alloc/free stacks).
+	PID    int      `json:"pid,omitempty"`
+	Frames []*Frame `json:"frames"`
+}
+
+// Frame is a single stack frame. Questionable frames (marked with "?" in Linux reports) are omitted.
+type Frame struct {
+	Function string `json:"function"`
+	// Offset of the PC from the function start and the function size (not present for inline frames).
+	Offset uint64 `json:"offset,omitempty"`
+	Size   uint64 `json:"size,omitempty"`
+	Module string `json:"module,omitempty"`
+	// File/Line are present only in symbolized reports.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type MemoryAccess struct {
+	// Bug type as reported by the tool (e.g. "slab-use-after-free", "null-ptr-deref").
+	Type string `json:"type,omitempty"`
+	// Either "read" or "write".
+	Op      string `json:"op,omitempty"`
+	Address string `json:"address,omitempty"`
+	Size    int    `json:"size,omitempty"`
+}
+
+type Register struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type TaskContext struct {
+	CPU      int    `json:"cpu"`
+	PID      int    `json:"pid"`
+	Comm     string `json:"comm"`
+	Tainted  bool   `json:"tainted,omitempty"`
+	Kernel   string `json:"kernel,omitempty"`
+	Hardware string `json:"hardware,omitempty"`
+}
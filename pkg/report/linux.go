@@ -214,6 +214,7 @@ func (ctx *linux) Parse(output []byte) *Report {
 				continue
 			}
 		}
+		rep.Details = parseLinuxDetails(rep.Report[rep.reportPrefixLen:])
 		return rep
 	}
 }
@@ -395,6 +396,7 @@ func (ctx *linux) Symbolize(rep *Report) error {
 		return err
 	}
 	rep.Report = ctx.decompileOpcodes(rep.Report, rep)
+	rep.Details = parseLinuxDetails(rep.Report[rep.reportPrefixLen:])
 
 	// Skip getting maintainers for Android fuzzing since the kernel source
 	// directory structure is different.
@@ -0,0 +1,242 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parseLinuxDetails extracts structured info from a Linux crash report.
+// The report is expected to be already stripped of timestamps/context prefixes.
+func parseLinuxDetails(report []byte) *Details {
+	details := &Details{}
+	var stack *Stack
+	// The register dump state: 0 - not started, 1 - in progress, 2 - finished.
+	regsState := 0
+	for _, line := range strings.Split(string(report), "\n") {
+		if linuxDetailsEndRe.MatchString(line) {
+			break
+		}
+		if regsState != 2 {
+			if regs, ip, ok := parseLinuxRegisters(line); ok {
+				regsState = 1
+				details.Registers = append(details.Registers, regs...)
+				if ip != nil && details.IP == nil {
+					details.IP = ip
+				}
+				continue
+			} else if regsState == 1 && !linuxCodeRe.MatchString(line) {
+				regsState = 2
+			}
+		} else if linuxRegistersStartRe.MatchString(line) || linuxCodeRe.MatchString(line) {
+			continue
+		}
+		if stack != nil {
+			if frame, ok := parseLinuxFrame(line); ok {
+				if frame != nil {
+					stack.Frames = append(stack.Frames, frame)
+				}
+				continue
+			}
+			stack = nil
+		}
+		if kind, pid, ok := parseLinuxStackHeader(line); ok {
+			if kind == StackCrash && details.stack(StackCrash) != nil {
+				// Another task stack (e.g. lockdep chains or NMI backtraces), ignore it.
+				continue
+			}
+			stack = &Stack{Kind: kind, PID: pid}
+			details.Stacks = append(details.Stacks, stack)
+			continue
+		}
+		parseLinuxAccess(details, line)
+		parseLinuxContext(details, line)
+	}
+	// Drop stacks without any non-questionable frames.
+	stacks := details.Stacks[:0]
+	for _, stack := range details.Stacks {
+		if len(stack.Frames) != 0 {
+			stacks = append(stacks, stack)
+		}
+	}
+	details.Stacks = stacks
+	if len(details.Stacks) == 0 && details.IP == nil && details.Access == nil &&
+		len(details.Registers) == 0 && details.Context == nil {
+		return nil
+	}
+	return details
+}
+
+func (details *Details) stack(kind StackKind) *Stack {
+	for _, stack := range details.Stacks {
+		if stack.Kind == kind {
+			return stack
+		}
+	}
+	return nil
+}
+
+func parseLinuxStackHeader(line string) (StackKind, int, bool) {
+	if linuxCallTrace.MatchString(line) {
+		return StackCrash, 0, true
+	}
+	if match := linuxObjectStackRe.FindStringSubmatch(line); match != nil {
+		kind := StackAlloc
+		if match[1] == "Freed" {
+			kind = StackFree
+		}
+		pid, _ := strconv.Atoi(match[2])
+		return kind, pid, true
+	}
+	if linuxAuxStackRe.MatchString(line) {
+		return StackAux, 0, true
+	}
+	return "", 0, false
+}
+
+// parseLinuxFrame parses a stack trace line. It returns ok=true for all lines that may appear
+// inside of a stack trace, but frame is nil for lines that are not frames or are questionable frames.
+func parseLinuxFrame(line string) (*Frame, bool) {
+	trimmed := strings.TrimSpace(line)
+	if linuxStackMarkerRe.MatchString(trimmed) {
+		return nil, true
+	}
+	if strings.HasPrefix(trimmed, "? ") {
+		return nil, true
+	}
+	if match := linuxInlineFrameRe.FindStringSubmatch(line); match != nil {
+		frame := &Frame{
+			Function: match[1],
+			File:     match[2],
+			Inline:   true,
+			Module:   match[4],
+		}
+		frame.Line, _ = strconv.Atoi(match[3])
+		return frame, true
+	}
+	raw := []byte(line)
+	parsed, ok := parseLinuxBacktraceLine(raw)
+	if !ok || len(bytes.TrimSpace(raw[:parsed.indices[0]])) != 0 {
+		return nil, false
+	}
+	frame := &Frame{
+		Function: parsed.Name,
+		Offset:   parsed.Offset,
+		Size:     parsed.Size,
+		Module:   parsed.ModName,
+	}
+	if match := linuxFrameFileRe.FindStringSubmatch(line[parsed.indices[7]:]); match != nil {
+		frame.File = match[1]
+		frame.Line, _ = strconv.Atoi(match[2])
+		if match[3] != "" {
+			frame.Module = match[3]
+		}
+	}
+	return frame, true
+}
+
+// parseLinuxRegisters parses a line of a register dump.
+// If the line contains the instruction pointer that points to a kernel function, it's returned as ip.
+func parseLinuxRegisters(line string) ([]Register, *Frame, bool) {
+	line = strings.TrimSpace(line)
+	if match := linuxIPRegisterRe.FindStringSubmatch(line); match != nil {
+		reg := Register{Name: match[1], Value: match[2]}
+		var ip *Frame
+		if parsed, ok := parseLinuxBacktraceLine([]byte(line)); ok {
+			ip = &Frame{
+				Function: parsed.Name,
+				Offset:   parsed.Offset,
+				Size:     parsed.Size,
+				Module:   parsed.ModName,
+			}
+			if match := linuxFrameFileRe.FindStringSubmatch(line[parsed.indices[7]:]); match != nil {
+				ip.File = match[1]
+				ip.Line, _ = strconv.Atoi(match[2])
+			}
+		}
+		return []Register{reg}, ip, true
+	}
+	if !linuxRegistersLineRe.MatchString(line) {
+		return nil, nil, false
+	}
+	var regs []Register
+	for _, match := range linuxRegisterRe.FindAllStringSubmatch(line, -1) {
+		regs = append(regs, Register{Name: match[1], Value: match[2]})
+	}
+	return regs, nil, true
+}
+
+func parseLinuxAccess(details *Details, line string) {
+	access := details.Access
+	if access == nil {
+		access = &MemoryAccess{}
+	}
+	if match := linuxAccessTypeRe.FindStringSubmatch(line); match != nil && access.Type == "" {
+		access.Type = match[1]
+		if match[2] != "" {
+			access.Address = "0x" + match[2]
+		}
+	} else if match := linuxAccessRe.FindStringSubmatch(line); match != nil && access.Op == "" {
+		access.Op = strings.ToLower(match[1])
+		access.Size, _ = strconv.Atoi(match[2])
+		if match[3] != "" {
+			access.Address = "0x" + match[3]
+		}
+	} else if match := linuxNullRangeRe.FindStringSubmatch(line); match != nil && access.Type == "" {
+		access.Type = match[1]
+		access.Address = "0x" + strings.TrimLeft(match[2], "0")
+		if access.Address == "0x" {
+			access.Address = "0x0"
+		}
+		start, _ := strconv.ParseUint(match[2], 16, 64)
+		end, _ := strconv.ParseUint(match[3], 16, 64)
+		access.Size = int(end - start + 1)
+	} else if match := linuxFaultAddressRe.FindStringSubmatch(line); match != nil && access.Address == "" {
+		access.Address = "0x" + match[1]
+	} else if match := linuxFaultOpRe.FindStringSubmatch(line); match != nil && access.Op == "" {
+		access.Op = match[1]
+	} else {
+		return
+	}
+	details.Access = access
+}
+
+func parseLinuxContext(details *Details, line string) {
+	if match := linuxTaskContextRe.FindStringSubmatch(line); match != nil && details.Context == nil {
+		details.Context = &TaskContext{
+			Comm:    match[3],
+			Tainted: match[4] != "Not tainted",
+			Kernel:  match[5],
+		}
+		details.Context.CPU, _ = strconv.Atoi(match[1])
+		details.Context.PID, _ = strconv.Atoi(match[2])
+	} else if match := linuxHardwareRe.FindStringSubmatch(line); match != nil &&
+		details.Context != nil && details.Context.Hardware == "" {
+		details.Context.Hardware = match[1]
+	}
+}
+
+// nolint: lll
+var (
+	linuxDetailsEndRe     = regexp.MustCompile(`^Kernel panic - not syncing|^---\[ end trace`)
+	linuxObjectStackRe    = regexp.MustCompile(`^(Allocated|Freed) by task ([0-9]+)(?: on cpu [0-9]+ at [0-9.]+s)?:$`)
+	linuxAuxStackRe       = regexp.MustCompile(`^(?:Second to last p|Last p)otentially related work creation:$`)
+	linuxStackMarkerRe    = regexp.MustCompile(`^</?(?:TASK|IRQ|NMI|SOFTIRQ)>$|^<EOI>$`)
+	linuxInlineFrameRe    = regexp.MustCompile(`^\s*(?:\[<[0-9a-fx]+>\]\s+)?([a-zA-Z0-9_.]+) (\S+):([0-9]+) \[inline\](?: \[([a-zA-Z0-9_.]+)\])?$`)
+	linuxFrameFileRe      = regexp.MustCompile(`^ (\S+\.[a-zA-Z]+):([0-9]+)(?: \[([a-zA-Z0-9_.]+)\])?`)
+	linuxIPRegisterRe     = regexp.MustCompile(`^(RIP|EIP|NIP|pc) ?: ?(.+)$`)
+	linuxRegistersStartRe = regexp.MustCompile(`^\s*(?:RIP|EIP|NIP|RSP|RAX|pc|lr|sp|x[0-9]+) ?:`)
+	linuxRegistersLineRe  = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9_]{0,7} ?: *[0-9a-fx():]+ *)+$`)
+	linuxRegisterRe       = regexp.MustCompile(`([A-Za-z][A-Za-z0-9_]{0,7}) ?: *([0-9a-fx():]+)`)
+	linuxAccessTypeRe     = regexp.MustCompile(`^BUG: (?:KASAN|KFENCE|KMSAN): ([a-z0-9-]+)(?: (?:read|write))? in .*?(?: at addr ([0-9a-f]+))?$`)
+	linuxAccessRe         = regexp.MustCompile(`^(Read|Write) of size ([0-9]+)(?: at addr ([0-9a-f]+))? by task`)
+	linuxNullRangeRe      = regexp.MustCompile(`^KASAN: ([a-z-]+) in range \[0x([0-9a-f]+)-0x([0-9a-f]+)\]`)
+	linuxFaultAddressRe   = regexp.MustCompile(`^BUG: (?:unable to handle (?:kernel )?(?:paging request|NULL pointer dereference) at|unable to handle page fault for address:|kernel NULL pointer dereference, address:) ([0-9a-f]+)`)
+	linuxFaultOpRe        = regexp.MustCompile(`^#PF: \S+ (read|write) access`)
+	linuxTaskContextRe    = regexp.MustCompile(`^CPU: ([0-9]+) (?:UID: [0-9]+ )?PID: ([0-9]+) Comm: (.+?) (Not tainted|Tainted:.*?) ([0-9]+\.[0-9]+\S*)`)
+	linuxHardwareRe       = regexp.MustCompile(`^Hardware name: (.+)$`)
+)
@@ -44,6 +44,8 @@ type ParseTest struct {
 	HasReport  bool
 	Report     []byte
 	Executor   string
+	// Formatted Report.Details (see formatDetails), checked only if present in the test.
+	Details []string
 	// Only used in report parsing:
 	corruptedReason string
 }
@@ -64,10 +66,13 @@ func (test *ParseTest) Equal(other *ParseTest) bool {
 	if test.HasReport && !bytes.Equal(test.Report, other.Report) {
 		return false
 	}
+	if len(test.Details) != 0 && !reflect.DeepEqual(test.Details, other.Details) {
+		return false
+	}
 	return test.Executor == other.Executor
 }
 
-func (test *ParseTest) Headers(includeFrame bool) []byte {
+func (test *ParseTest) Headers(includeFrame, includeDetails bool) []byte {
 	buf := new(bytes.Buffer)
 	fmt.Fprintf(buf, "TITLE: %v\n", test.Title)
 	for _, t := range test.AltTitles {
@@ -88,9 +93,96 @@ func (test *ParseTest) Headers(includeFrame bool) []byte {
 	if test.Executor != "" {
 		fmt.Fprintf(buf, "EXECUTOR: %s\n", test.Executor)
 	}
+	if includeDetails {
+		for _, ln := range test.Details {
+			fmt.Fprintf(buf, "%v\n", ln)
+		}
+	}
 	return buf.Bytes()
 }
 
+// formatDetails formats details as test header lines:
+//
+//	STACK: alloc pid=5000
+//		kmalloc+0x10/0x20 [module] mm/slub.c:123
+//		inlined_func mm/slub.c:100 inline
+//	IP: func+0x10/0x20
+//	ACCESS: type=slab-use-after-free op=read address=0xffff888077ec2bf8 size=8
+//	REGISTERS: RAX=0000000000000002 RBX=0000000000000001
+//	CONTEXT: cpu=0 pid=6659 comm=syz-executor.0 kernel=6.4.0 hardware="QEMU Standard PC"
+func formatDetails(details *Details) []string {
+	if details == nil {
+		return nil
+	}
+	var lines []string
+	for _, stack := range details.Stacks {
+		ln := fmt.Sprintf("STACK: %v", stack.Kind)
+		if stack.PID != 0 {
+			ln += fmt.Sprintf(" pid=%v", stack.PID)
+		}
+		lines = append(lines, ln)
+		for _, frame := range stack.Frames {
+			lines = append(lines, "\t"+formatFrame(frame))
+		}
+	}
+	if details.IP != nil {
+		lines = append(lines, "IP: "+formatFrame(details.IP))
+	}
+	if access := details.Access; access != nil {
+		lines = append(lines, "ACCESS:"+formatFields(
+			"type", access.Type, "op", access.Op, "address", access.Address, "size", access.Size))
+	}
+	if len(details.Registers) != 0 {
+		var fields []any
+		for _, reg := range details.Registers {
+			fields = append(fields, reg.Name, reg.Value)
+		}
+		lines = append(lines, "REGISTERS:"+formatFields(fields...))
+	}
+	if ctx := details.Context; ctx != nil {
+		tainted := "N"
+		if ctx.Tainted {
+			tainted = "Y"
+		}
+		lines = append(lines, "CONTEXT:"+formatFields("cpu", ctx.CPU, "pid", ctx.PID, "comm", ctx.Comm,
+			"tainted", tainted, "kernel", ctx.Kernel, "hardware", ctx.Hardware))
+	}
+	return lines
+}
+
+func formatFrame(frame *Frame) string {
+	ln := frame.Function
+	if frame.Size != 0 {
+		ln += fmt.Sprintf("+0x%x/0x%x", frame.Offset, frame.Size)
+	}
+	if frame.Module != "" {
+		ln += fmt.Sprintf(" [%v]", frame.Module)
+	}
+	if frame.File != "" {
+		ln += fmt.Sprintf(" %v:%v", frame.File, frame.Line)
+	}
+	if frame.Inline {
+		ln += " inline"
+	}
+	return ln
+}
+
+// formatFields formats name/value pairs as " name=value", empty values are omitted.
+func formatFields(fields ...any) string {
+	ln := ""
+	for i := 0; i < len(fields); i += 2 {
+		val := fmt.Sprint(fields[i+1])
+		if val == "" {
+			continue
+		}
+		if strings.ContainsAny(val, " \"") {
+			val = fmt.Sprintf("%q", val)
+		}
+		ln += fmt.Sprintf(" %v=%v", fields[i], val)
+	}
+	return ln
+}
+
 func testParseFile(t *testing.T, reporter *Reporter, fn string) {
 	test := parseReport(t, reporter, fn)
 	testParseImpl(t, reporter, test)
@@ -161,6 +253,10 @@ func parseHeaderLine(t *testing.T, test *ParseTest, ln string) {
 	)
 	switch {
 	case strings.HasPrefix(ln, "#"):
+	case strings.HasPrefix(ln, "STACK: "), strings.HasPrefix(ln, "\t"), strings.HasPrefix(ln, "IP: "),
+		strings.HasPrefix(ln, "ACCESS: "), strings.HasPrefix(ln, "REGISTERS: "),
+		strings.HasPrefix(ln, "CONTEXT: "):
+		test.Details = append(test.Details, ln)
 	case strings.HasPrefix(ln, titlePrefix):
 		test.Title = ln[len(titlePrefix):]
 	case strings.HasPrefix(ln, altTitlePrefix):
@@ -211,6 +307,7 @@ func testFromReport(rep *Report) *ParseTest {
 		Type:            TitleToCrashType(rep.Title),
 		Frame:           rep.Frame,
 		Report:          rep.Report,
+		Details:         formatDetails(rep.Details),
 	}
 	if rep.Executor != nil {
 		ret.Executor = fmt.Sprintf("proc=%d, id=%d", rep.Executor.ProcID, rep.Executor.ExecID)
@@ -238,7 +335,7 @@ func testParseImpl(t *testing.T, reporter *Reporter, test *ParseTest) {
 			updateReportTest(t, test, parsed)
 		}
 		t.Fatalf("want:\n%s\ngot:\n%sCorrupted reason: %q",
-			test.Headers(true), parsed.Headers(true), parsed.corruptedReason)
+			test.Headers(true, true), parsed.Headers(true, len(test.Details) != 0), parsed.corruptedReason)
 	}
 	if parsed.Title != "" && len(rep.Report) == 0 {
 		t.Fatalf("found crash message but report is empty")
@@ -293,7 +390,7 @@ func checkReport(t *testing.T, reporter *Reporter, rep *Report, test *ParseTest)
 
 func updateReportTest(t *testing.T, test, parsed *ParseTest) {
 	buf := new(bytes.Buffer)
-	buf.Write(parsed.Headers(test.Frame != ""))
+	buf.Write(parsed.Headers(test.Frame != "", len(test.Details) != 0))
 	fmt.Fprintf(buf, "\n%s", test.Log)
 	if test.HasReport {
 		fmt.Fprintf(buf, "REPORT:\n%s", parsed.Report)
@@ -396,7 +493,7 @@ func testSymbolizeFile(t *testing.T, reporter *Reporter, fn string) {
 		}
 		assert.Equal(t, string(test.Report), string(rep.Report), "extracted wrong report")
 		t.Fatalf("want:\n%s\ngot:\n%sCorrupted reason: %q",
-			test.Headers(true), parsed.Headers(true), parsed.corruptedReason)
+			test.Headers(true, true), parsed.Headers(true, len(test.Details) != 0), parsed.corruptedReason)
 	}
 }
 
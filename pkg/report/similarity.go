@@ -0,0 +1,109 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Similarity returns similarity of the crash stacks of two reports in the [0, 1] range,
+// 1 means that the stacks are the same. Frames closer to the top of the stack have higher weight,
+// so reports that differ only in the deep callers (e.g. the same bug triggered via different syscalls)
+// are still similar, while reports with different top frames are not.
+// If any of the reports doesn't have a parsed stack (see Report.Details), only titles are compared.
+func Similarity(a, b *Report) float64 {
+	sigA, sigB := StackSignature(a), StackSignature(b)
+	if len(sigA) == 0 || len(sigB) == 0 {
+		if a.Title == b.Title {
+			return 1
+		}
+		return 0
+	}
+	return signatureSimilarity(sigA, sigB)
+}
+
+// StackSignature returns canonical function names of the top frames of the crash stack
+// that are used for similarity comparison. Frames of the reporting machinery (dump_stack, kasan, etc)
+// are skipped, syscall prefixes and compiler-generated suffixes (.isra.0, .cold, etc) are stripped.
+func StackSignature(rep *Report) []string {
+	if rep.Details == nil {
+		return nil
+	}
+	var frames []*Frame
+	if rep.Details.IP != nil {
+		frames = append(frames, rep.Details.IP)
+	}
+	if stack := rep.Details.stack(StackCrash); stack != nil {
+		frames = append(frames, stack.Frames...)
+	}
+	var sig []string
+	for _, frame := range frames {
+		name := frame.Function
+		if pos := strings.IndexByte(name, '.'); pos > 0 {
+			name = name[:pos]
+		}
+		name = linuxStackParams.stripFrames([]string{name})[0]
+		if signatureSkipRe.MatchString(name) || len(sig) != 0 && sig[len(sig)-1] == name {
+			continue
+		}
+		sig = append(sig, name)
+		if len(sig) == maxSignatureFrames {
+			break
+		}
+	}
+	return sig
+}
+
+// Frames beyond that have negligible weight.
+const maxSignatureFrames = 20
+
+// Note: we don't use stackParams.skipPatterns b/c they also skip lots of generic functions
+// (locking, rb-trees, etc) that are not interesting for titles, but are useful for stack comparison.
+// Currently details are extracted only for Linux, so these are Linux functions.
+var signatureSkipRe = regexp.MustCompile(`^(?:dump_stack|dump_stack_lvl|show_stack|print_report|` +
+	`print_address_description|kasan_.*|__kasan_.*|__asan_.*|kmsan_.*|__msan_.*|kfence_.*|__ubsan_.*|ubsan_.*|` +
+	`panic|__warn|warn_slowpath_.*|report_bug|handle_bug|fixup_bug|do_error_trap|exc_invalid_op|asm_exc_.*|` +
+	`check_panic_on_warn|should_fail.*|fail_dump)$`)
+
+// signatureSimilarity computes the frame-weighted edit distance between the signatures
+// and normalizes it to the [0, 1] similarity range.
+func signatureSimilarity(a, b []string) float64 {
+	// dist[i][j] is the distance between a[i:] and b[j:].
+	dist := make([][]float64, len(a)+1)
+	for i := range dist {
+		dist[i] = make([]float64, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		dist[i][len(b)] = dist[i+1][len(b)] + frameWeight(i)
+	}
+	for j := len(b) - 1; j >= 0; j-- {
+		dist[len(a)][j] = dist[len(a)][j+1] + frameWeight(j)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			subst := math.Max(frameWeight(i), frameWeight(j))
+			if a[i] == b[j] {
+				subst = 0
+			}
+			dist[i][j] = math.Min(dist[i+1][j+1]+subst,
+				math.Min(dist[i+1][j]+frameWeight(i), dist[i][j+1]+frameWeight(j)))
+		}
+	}
+	total := math.Max(signatureWeight(len(a)), signatureWeight(len(b)))
+	return math.Max(0, 1-dist[0][0]/total)
+}
+
+func frameWeight(pos int) float64 {
+	return math.Pow(0.9, float64(pos))
+}
+
+func signatureWeight(frames int) float64 {
+	total := 0.0
+	for i := 0; i < frames; i++ {
+		total += frameWeight(i)
+	}
+	return total
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{"foo bar baz", "foo bar baz", 1, 1},
+		{"foo bar baz", "qux quux corge", 0, 0},
+		// Differences in deep frames matter less than differences in top frames.
+		{"f1 f2 f3 f4 f5 f6 f7 f8 f9 f10", "f1 f2 f3 f4 f5 f6 f7 f8 f9 g10", 0.9, 0.99},
+		{"f1 f2 f3 f4 f5 f6 f7 f8 f9 f10", "g1 f2 f3 f4 f5 f6 f7 f8 f9 f10", 0.8, 0.9},
+		// An additional top frame (e.g. an inlined helper) is a small difference.
+		{"f1 f2 f3 f4 f5 f6 f7 f8 f9 f10", "g0 f1 f2 f3 f4 f5 f6 f7 f8 f9 f10", 0.8, 0.95},
+		{"f1 f2 f3", "f1 f2 f3 f4 f5 f6", 0.4, 0.7},
+	}
+	for _, test := range tests {
+		a, b := strings.Fields(test.a), strings.Fields(test.b)
+		sim := signatureSimilarity(a, b)
+		assert.Equal(t, sim, signatureSimilarity(b, a), "%q vs %q", test.a, test.b)
+		if sim < test.min || sim > test.max {
+			t.Errorf("%q vs %q: similarity %v, want [%v, %v]", test.a, test.b, sim, test.min, test.max)
+		}
+	}
+}
+
+func TestStackSignature(t *testing.T) {
+	reporter := linuxReporter(t)
+	rep := parseReportFile(t, reporter, "testdata/linux/report/510")
+	assert.Equal(t, []string{
+		"rb_erase",
+		"__kernfs_remove",
+		"kernfs_remove_by_name_ns",
+		"remove_files",
+		"sysfs_remove_group",
+		"sysfs_remove_groups",
+		"device_remove_attrs",
+		"device_del",
+		"rollback_registered_many",
+		"unregister_netdevice_many",
+		"default_device_exit_batch",
+		"ops_exit_list",
+		"cleanup_net",
+		"process_one_work",
+		"worker_thread",
+		"kthread",
+		"ret_from_fork",
+	}, StackSignature(rep))
+}
+
+func TestSimilarity(t *testing.T) {
+	reporter := linuxReporter(t)
+	kasan := parseReportFile(t, reporter, "testdata/linux/report/704")
+	gpf := parseReportFile(t, reporter, "testdata/linux/report/510")
+	assert.Equal(t, 1.0, Similarity(kasan, kasan))
+	assert.Less(t, Similarity(kasan, gpf), 0.1)
+	// The same bug reached via a different caller.
+	other := *gpf
+	other.Details = &Details{
+		IP:     gpf.Details.IP,
+		Stacks: []*Stack{{Kind: StackCrash, Frames: gpf.Details.Stacks[0].Frames[:10]}},
+	}
+	other.Details.Stacks[0].Frames = append(other.Details.Stacks[0].Frames, &Frame{Function: "foo"})
+	assert.Greater(t, Similarity(gpf, &other), 0.8)
+	// Reports without stacks are compared by titles.
+	noStack := &Report{Title: gpf.Title}
+	assert.Equal(t, 1.0, Similarity(gpf, noStack))
+	noStack.Title = "foo"
+	assert.Equal(t, 0.0, Similarity(gpf, noStack))
+}
+
+func linuxReporter(t *testing.T) *Reporter {
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.AMD64,
+			SysTarget:  targets.Get(targets.Linux, targets.AMD64),
+		},
+	}
+	reporter, err := NewReporter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reporter
+}
+
+func parseReportFile(t *testing.T, reporter *Reporter, file string) *Report {
+	rep := reporter.Parse(parseReport(t, reporter, file).Log)
+	if rep == nil {
+		t.Fatalf("no report in %v", file)
+	}
+	return rep
+}
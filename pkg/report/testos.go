@@ -0,0 +1,63 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// testOS parses output of the executor for the test OS (sys/test) targets.
+// The targets don't have a kernel, instead pseudo-syscalls simulate crashes
+// with a "{{CRASH: title}}" message (see fake_crash in executor/common_test.h).
+type testOS struct {
+	*config
+}
+
+func ctorTest(cfg *config) (reporterImpl, []string, error) {
+	ctx := &testOS{
+		config: cfg,
+	}
+	return ctx, nil, nil
+}
+
+func (ctx *testOS) ContainsCrash(output []byte) bool {
+	return containsCrash(output, testOSOopses, ctx.ignores)
+}
+
+func (ctx *testOS) Parse(output []byte) *Report {
+	rep := simpleLineParser(output, testOSOopses, nil, ctx.ignores)
+	if rep == nil {
+		return nil
+	}
+	// The crash message is printed on the line following the header.
+	if bytes.HasPrefix(output[rep.StartPos:], testOSCrashHeader) && rep.EndPos < len(output) {
+		if next := bytes.IndexByte(output[rep.EndPos+1:], '\n'); next != -1 {
+			rep.EndPos += next + 1
+		} else {
+			rep.EndPos = len(output)
+		}
+	}
+	return rep
+}
+
+func (ctx *testOS) Symbolize(rep *Report) error {
+	return nil
+}
+
+var testOSCrashHeader = []byte("SYZFAIL: crash")
+
+var testOSOopses = append([]*oops{
+	{
+		testOSCrashHeader,
+		[]oopsFormat{
+			{
+				title:        compile("SYZFAIL: crash\\n{{CRASH: (.*?)}}"),
+				fmt:          "%[1]v",
+				noStackTrace: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+}, commonOopses...)
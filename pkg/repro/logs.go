@@ -0,0 +1,136 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"sort"
+
+	"github.com/google/syzkaller/prog"
+)
+
+// If the crash happened several times, its other logs help to find the guilty programs:
+// programs that precede the crash in several logs are more likely to be guilty than others,
+// and programs that were never executed in the other logs are less likely to be required.
+
+const (
+	// The number of the last programs before the crash in each log that are considered suspects.
+	suspectWindow = 20
+	// The max number of programs we test separately.
+	maxSingleCandidates = 20
+)
+
+type suspect struct {
+	entry *prog.LogEntry
+	score float64
+	// The number of logs where the program precedes the crash.
+	logs int
+}
+
+// rankSuspects returns programs executed shortly before the crash in any of the logs
+// ordered by their likelihood to be guilty. Each appearance in a log adds to the program score,
+// appearances closer to the crash add more.
+func rankSuspects(logs [][]*prog.LogEntry) []*suspect {
+	suspects := make(map[string]*suspect)
+	var ret []*suspect
+	for _, entries := range logs {
+		seen := make(map[string]bool)
+		for pos := 0; pos < suspectWindow && pos < len(entries); pos++ {
+			ent := entries[len(entries)-1-pos]
+			key := string(ent.P.Serialize())
+			s := suspects[key]
+			if s == nil {
+				s = &suspect{entry: ent}
+				suspects[key] = s
+				ret = append(ret, s)
+			}
+			s.score += 1 / float64(pos+1)
+			if !seen[key] {
+				seen[key] = true
+				s.logs++
+			}
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].score > ret[j].score
+	})
+	return ret
+}
+
+// singleCandidates returns programs to test separately: the last programs of every proc in all logs
+// and other suspects, ordered by their score. The programs in first are always tested first.
+func singleCandidates(first []*prog.LogEntry, logs [][]*prog.LogEntry) []*prog.LogEntry {
+	suspects := rankSuspects(logs)
+	score := make(map[string]float64)
+	for _, s := range suspects {
+		score[string(s.entry.P.Serialize())] = s.score
+	}
+	var candidates []*prog.LogEntry
+	for _, entries := range logs {
+		candidates = append(candidates, lastEntries(entries)...)
+	}
+	for _, s := range suspects {
+		if s.logs > 1 {
+			candidates = append(candidates, s.entry)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return score[string(candidates[i].P.Serialize())] > score[string(candidates[j].P.Serialize())]
+	})
+	ret := dedupEntries(append(append([]*prog.LogEntry{}, first...), candidates...))
+	if len(ret) > maxSingleCandidates {
+		ret = ret[:maxSingleCandidates]
+	}
+	return ret
+}
+
+// commonEntries returns the entries that were executed (before the crash) in at least one of the other logs.
+func commonEntries(entries []*prog.LogEntry, others [][]*prog.LogEntry) []*prog.LogEntry {
+	executed := make(map[string]bool)
+	for _, other := range others {
+		for _, ent := range other {
+			executed[string(ent.P.Serialize())] = true
+		}
+	}
+	var ret []*prog.LogEntry
+	for _, ent := range entries {
+		if executed[string(ent.P.Serialize())] {
+			ret = append(ret, ent)
+		}
+	}
+	return ret
+}
+
+func dedupEntries(entries []*prog.LogEntry) []*prog.LogEntry {
+	var ret []*prog.LogEntry
+	dedup := make(map[string]bool)
+	for _, ent := range entries {
+		key := string(ent.P.Serialize())
+		if !dedup[key] {
+			dedup[key] = true
+			ret = append(ret, ent)
+		}
+	}
+	return ret
+}
+
+// entriesBeforeCrash cuts programs that were executed after the crash.
+func entriesBeforeCrash(entries []*prog.LogEntry, crashStart int) []*prog.LogEntry {
+	for i, ent := range entries {
+		if ent.Start > crashStart {
+			return entries[:i]
+		}
+	}
+	return entries
+}
+
+// stateEntries returns programs that changed persistent machine state.
+func stateEntries(entries []*prog.LogEntry) []*prog.LogEntry {
+	var ret []*prog.LogEntry
+	for _, ent := range entries {
+		if ent.State {
+			ret = append(ret, ent)
+		}
+	}
+	return ret
+}
@@ -39,13 +39,14 @@ type Result struct {
 }
 
 type Stats struct {
-	Log              []byte
-	TotalTime        time.Duration
-	ExtractProgTime  time.Duration
-	MinimizeProgTime time.Duration
-	SimplifyProgTime time.Duration
-	ExtractCTime     time.Duration
-	SimplifyCTime    time.Duration
+	Log                []byte
+	TotalTime          time.Duration
+	ExtractProgTime    time.Duration
+	MinimizeProgTime   time.Duration
+	SearchScheduleTime time.Duration
+	SimplifyProgTime   time.Duration
+	ExtractCTime       time.Duration
+	SimplifyCTime      time.Duration
 }
 
 type reproContext struct {
@@ -58,6 +59,7 @@ type reproContext struct {
 	crashStart     int
 	crashExecutor  *report.ExecutorInfo
 	entries        []*prog.LogEntry
+	otherEntries   [][]*prog.LogEntry
 	testTimeouts   []time.Duration
 	startOpts      csource.Options
 	stats          *Stats
@@ -65,6 +67,14 @@ type reproContext struct {
 	timeouts       targets.Timeouts
 	observedTitles map[string]bool
 	fast           bool
+	// Programs that changed persistent machine state (see prog.LogEntry.State).
+	state []*prog.LogEntry
+	// Execute the state programs before the tested programs.
+	replayState bool
+	// The last measured reliability and the reproducer it was measured for.
+	reliability     float64
+	reliabilityProg []byte
+	reliabilityOpts csource.Options
 }
 
 // execInterface describes the interfaces needed by pkg/repro.
@@ -85,8 +95,11 @@ type Environment struct {
 	logf func(string, ...interface{})
 }
 
-func Run(ctx context.Context, log []byte, env Environment) (*Result, *Stats, error) {
-	return runInner(ctx, log, env, &poolWrapper{
+// Run tries to find a reproducer for a crash.
+// All logs must be console outputs of the same crash, the first one is the main one.
+// The other logs (if any) are used to find the programs that are more likely to trigger the crash.
+func Run(ctx context.Context, logs [][]byte, env Environment) (*Result, *Stats, error) {
+	return runInner(ctx, logs, env, &poolWrapper{
 		cfg:      env.Config,
 		reporter: env.Reporter,
 		pool:     env.Pool,
@@ -95,8 +108,9 @@ func Run(ctx context.Context, log []byte, env Environment) (*Result, *Stats, err
 
 var ErrEmptyCrashLog = errors.New("no programs")
 
-func runInner(ctx context.Context, crashLog []byte, env Environment, exec execInterface) (*Result, *Stats, error) {
+func runInner(ctx context.Context, crashLogs [][]byte, env Environment, exec execInterface) (*Result, *Stats, error) {
 	cfg := env.Config
+	crashLog := crashLogs[0]
 	entries := cfg.Target.ParseLog(crashLog, prog.NonStrict)
 	if len(entries) == 0 {
 		return nil, nil, fmt.Errorf("log (%d bytes) parse failed: %w", len(crashLog), ErrEmptyCrashLog)
@@ -132,6 +146,21 @@ func runInner(ctx context.Context, crashLog []byte, env Environment, exec execIn
 	if env.Fast {
 		testTimeouts = []time.Duration{30 * time.Second, 5 * time.Minute}
 	}
+	var otherEntries [][]*prog.LogEntry
+	for _, log := range crashLogs[1:] {
+		otherStart := len(log)
+		if rep := env.Reporter.Parse(log); rep != nil {
+			if rep.Title != crashTitle {
+				continue
+			}
+			otherStart = rep.StartPos
+		} else if crashStart != len(crashLog) {
+			continue
+		}
+		if other := entriesBeforeCrash(cfg.Target.ParseLog(log, prog.NonStrict), otherStart); len(other) != 0 {
+			otherEntries = append(otherEntries, other)
+		}
+	}
 	reproCtx := &reproContext{
 		ctx:           ctx,
 		exec:          exec,
@@ -142,6 +171,7 @@ func runInner(ctx context.Context, crashLog []byte, env Environment, exec execIn
 		crashExecutor: crashExecutor,
 
 		entries:        entries,
+		otherEntries:   otherEntries,
 		testTimeouts:   testTimeouts,
 		startOpts:      createStartOptions(cfg, env.Features, crashType),
 		stats:          new(Stats),
@@ -218,12 +248,8 @@ func createStartOptions(cfg *mgrconfig.Config, features flatrpc.Feature,
 
 func (ctx *reproContext) repro() (*Result, error) {
 	// Cut programs that were executed after crash.
-	for i, ent := range ctx.entries {
-		if ent.Start > ctx.crashStart {
-			ctx.entries = ctx.entries[:i]
-			break
-		}
-	}
+	ctx.entries = entriesBeforeCrash(ctx.entries, ctx.crashStart)
+	ctx.state = stateEntries(ctx.entries)
 
 	reproStart := time.Now()
 	defer func() {
@@ -243,8 +269,14 @@ func (ctx *reproContext) repro() (*Result, error) {
 		return nil, err
 	}
 
-	// Try extracting C repro without simplifying options first.
 	if !ctx.fast {
+		// Find the call schedule that makes the reproducer more reliable.
+		res, err = ctx.searchSchedule(res)
+		if err != nil {
+			return nil, err
+		}
+
+		// Try extracting C repro without simplifying options first.
 		res, err = ctx.extractC(res)
 		if err != nil {
 			return nil, err
@@ -267,17 +299,24 @@ func (ctx *reproContext) repro() (*Result, error) {
 		}
 	}
 	// Validate the resulting reproducer - a random rare kernel crash might have diverted the process.
-	res.Reliability, err = calculateReliability(func() (bool, error) {
-		ret, err := ctx.testProg(res.Prog, res.Duration, res.Opts, false)
+	// If the reproducer did not change since the schedule search, its measurement is already
+	// such validation (it counts only the crashes we have seen before).
+	if reliability, ok := ctx.measuredReliability(res); ok {
+		ctx.reproLogf(2, "reusing measured reliability %.2f", reliability)
+		res.Reliability = reliability
+	} else {
+		res.Reliability, err = calculateReliability(func() (bool, error) {
+			ret, err := ctx.testProg(res.Prog, res.Duration, res.Opts, false)
+			if err != nil {
+				return false, err
+			}
+			ctx.reproLogf(2, "validation run: crashed=%v", ret.Crashed)
+			return ret.Crashed, nil
+		})
 		if err != nil {
-			return false, err
+			ctx.reproLogf(2, "could not calculate reliability, err=%v", err)
+			return nil, err
 		}
-		ctx.reproLogf(2, "validation run: crashed=%v", ret.Crashed)
-		return ret.Crashed, nil
-	})
-	if err != nil {
-		ctx.reproLogf(2, "could not calculate reliability, err=%v", err)
-		return nil, err
 	}
 
 	const minReliability = 0.15
@@ -289,6 +328,20 @@ func (ctx *reproContext) repro() (*Result, error) {
 	return res, nil
 }
 
+func (ctx *reproContext) setReliability(res *Result, reliability float64) {
+	ctx.reliability = reliability
+	ctx.reliabilityProg = res.Prog.Serialize()
+	ctx.reliabilityOpts = res.Opts
+}
+
+func (ctx *reproContext) measuredReliability(res *Result) (float64, bool) {
+	if ctx.reliabilityProg == nil || res.Opts != ctx.reliabilityOpts ||
+		!bytes.Equal(res.Prog.Serialize(), ctx.reliabilityProg) {
+		return 0, false
+	}
+	return ctx.reliability, true
+}
+
 func calculateReliability(cb func() (bool, error)) (float64, error) {
 	const (
 		maxRuns  = 10
@@ -329,10 +382,18 @@ func (ctx *reproContext) extractProg(entries []*prog.LogEntry) (*Result, error)
 		}
 	}
 
-	if len(toTest) == 0 {
+	if len(ctx.otherEntries) != 0 {
+		ctx.reproLogf(3, "testing suspect programs from %v logs", len(ctx.otherEntries)+1)
+		toTest = singleCandidates(toTest, append([][]*prog.LogEntry{entries}, ctx.otherEntries...))
+	} else if len(toTest) == 0 {
 		ctx.reproLogf(3, "testing a last program of every proc")
 		toTest = lastEntries(entries)
 	}
+	// Programs that were executed in the other logs as well (bisected first b/c it's a smaller set).
+	common := commonEntries(entries, ctx.otherEntries)
+	if len(common) == len(entries) {
+		common = nil
+	}
 
 	for i, timeout := range ctx.testTimeouts {
 		// Execute each program separately to detect simple crashes caused by a single program.
@@ -346,6 +407,17 @@ func (ctx *reproContext) extractProg(entries []*prog.LogEntry) (*Result, error)
 			return res, nil
 		}
 
+		if len(ctx.state) != 0 {
+			res, err = ctx.extractProgSingleWithState(toTest, timeout)
+			if err != nil {
+				return nil, err
+			}
+			if res != nil {
+				ctx.reproLogf(3, "found reproducer with %d syscalls", len(res.Prog.Calls))
+				return res, nil
+			}
+		}
+
 		// Don't try bisecting if there's only one entry.
 		if len(entries) == 1 {
 			continue
@@ -356,6 +428,18 @@ func (ctx *reproContext) extractProg(entries []*prog.LogEntry) (*Result, error)
 			continue
 		}
 
+		if len(common) > 1 {
+			ctx.reproLogf(3, "bisecting %v programs common for all logs", len(common))
+			res, err = ctx.extractProgBisect(common, timeout)
+			if err != nil {
+				return nil, err
+			}
+			if res != nil {
+				ctx.reproLogf(3, "found reproducer with %d syscalls", len(res.Prog.Calls))
+				return res, nil
+			}
+		}
+
 		// Execute all programs and bisect the log to find multiple guilty programs.
 		res, err = ctx.extractProgBisect(entries, timeout)
 		if err != nil {
@@ -413,6 +497,30 @@ func (ctx *reproContext) extractProgSingle(entries []*prog.LogEntry, duration ti
 	return nil, nil
 }
 
+// Crashes may depend on persistent machine state (mounted filesystems, sysctls, injected faults)
+// created by programs executed long before the crash. Execute the programs separately again,
+// but now after the programs that created the state.
+func (ctx *reproContext) extractProgSingleWithState(entries []*prog.LogEntry, duration time.Duration) (
+	*Result, error) {
+	ctx.reproLogf(3, "single: replaying machine state created by %d programs", len(ctx.state))
+	ctx.replayState = true
+	res, err := ctx.extractProgSingle(entries, duration)
+	ctx.replayState = false
+	if err != nil || res == nil {
+		return nil, err
+	}
+	// Make the reproducer standalone: prepend the state programs to the guilty program.
+	// Unneeded state calls are dropped during concatenation and minimization.
+	var withState []*prog.LogEntry
+	for _, ent := range ctx.state {
+		if ent.P != res.Prog {
+			withState = append(withState, &prog.LogEntry{P: ent.P.Clone()})
+		}
+	}
+	withState = append(withState, &prog.LogEntry{P: res.Prog.Clone()})
+	return ctx.concatenateProgs(withState, res.Duration)
+}
+
 func (ctx *reproContext) extractProgBisect(entries []*prog.LogEntry, baseDuration time.Duration) (*Result, error) {
 	ctx.reproLogf(3, "bisect: bisecting %d programs with base timeout %s", len(entries), baseDuration)
 
@@ -732,6 +840,9 @@ func (ctx *reproContext) testProgs(entries []*prog.LogEntry, duration time.Durat
 	if len(entries) == 0 {
 		return ret, fmt.Errorf("no programs to execute")
 	}
+	if ctx.replayState {
+		entries = ctx.withState(entries)
+	}
 	pstr := encodeEntries(entries)
 	program := entries[0].P.String()
 	if len(entries) > 1 {
@@ -755,6 +866,21 @@ func (ctx *reproContext) testProgs(entries []*prog.LogEntry, duration time.Durat
 	}, strict)
 }
 
+// withState prepends the programs that created machine state to the entries.
+func (ctx *reproContext) withState(entries []*prog.LogEntry) []*prog.LogEntry {
+	tested := make(map[*prog.Prog]bool)
+	for _, ent := range entries {
+		tested[ent.P] = true
+	}
+	var ret []*prog.LogEntry
+	for _, ent := range ctx.state {
+		if !tested[ent.P] {
+			ret = append(ret, ent)
+		}
+	}
+	return append(ret, entries...)
+}
+
 func (ctx *reproContext) testCProg(p *prog.Prog, duration time.Duration, opts csource.Options,
 	strict bool) (ret verdict, err error) {
 	return ctx.getVerdict(func() (*instance.RunResult, error) {
@@ -1030,9 +1156,9 @@ func (stats *Stats) FullLog() []byte {
 	if stats == nil {
 		return nil
 	}
-	return []byte(fmt.Sprintf("Extracting prog: %v\nMinimizing prog: %v\n"+
+	return []byte(fmt.Sprintf("Extracting prog: %v\nMinimizing prog: %v\nSearching call schedule: %v\n"+
 		"Simplifying prog options: %v\nExtracting C: %v\nSimplifying C: %v\n\n\n%s",
-		stats.ExtractProgTime, stats.MinimizeProgTime,
+		stats.ExtractProgTime, stats.MinimizeProgTime, stats.SearchScheduleTime,
 		stats.SimplifyProgTime, stats.ExtractCTime, stats.SimplifyCTime, stats.Log))
 }
 
@@ -4,6 +4,7 @@
 package repro
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/rand"
@@ -149,7 +150,7 @@ func runTestRepro(t *testing.T, log string, exec execInterface) (*Result, *Stats
 		Reporter: reporter,
 		logf:     t.Logf,
 	}
-	return runInner(context.Background(), []byte(log), env, exec)
+	return runInner(context.Background(), [][]byte{[]byte(log)}, env, exec)
 }
 
 const testReproLog = `
@@ -348,3 +349,153 @@ func BenchmarkCalculateReliability(b *testing.B) {
 		})
 	}
 }
+
+func TestMultipleLogs(t *testing.T) {
+	mainLog := `
+2015/12/21 12:18:05 executing program 1:
+alarm(0xb)
+2015/12/21 12:18:10 executing program 2:
+getpid()
+2015/12/21 12:18:15 executing program 1:
+getuid()
+`
+	otherLog := `
+2015/12/21 12:19:05 executing program 2:
+getpid()
+2015/12/21 12:19:10 executing program 0:
+alarm(0xb)
+`
+	mgrConfig := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:     targets.Linux,
+			TargetVMArch: targets.AMD64,
+		},
+		Sandbox: "namespace",
+	}
+	var err error
+	mgrConfig.Target, err = prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	reporter, err := report.NewReporter(mgrConfig)
+	require.NoError(t, err)
+	env := Environment{
+		Config:   mgrConfig,
+		Features: flatrpc.AllFeatures,
+		Reporter: reporter,
+		logf:     t.Logf,
+	}
+	var executed []string
+	exec := &testExecInterface{
+		run: func(log []byte) (*instance.RunResult, error) {
+			executed = append(executed, string(log))
+			if bytes.Contains(log, []byte("alarm(0xb)")) {
+				return fakeCrashResult("crashed"), nil
+			}
+			return fakeCrashResult(""), nil
+		},
+	}
+	result, _, err := runInner(context.Background(),
+		[][]byte{[]byte(mainLog), []byte(otherLog), []byte(otherLog)}, env, exec)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "alarm(0xb)\n", string(result.Prog.Serialize()))
+	// The program that precedes the crash in all logs must be tested first.
+	assert.Equal(t, "executing program 0:\nalarm(0xb)\n", executed[0])
+}
+
+func TestRankSuspects(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	parse := func(log string) []*prog.LogEntry {
+		return target.ParseLog([]byte(log), prog.NonStrict)
+	}
+	logs := [][]*prog.LogEntry{
+		parse("executing program 0:\ngetpid()\nexecuting program 1:\ngetuid()\n"),
+		parse("executing program 0:\ngetuid()\nexecuting program 1:\ngetgid()\n"),
+		parse("executing program 0:\ngetuid()\nexecuting program 1:\ngetpid()\n"),
+	}
+	var ranked []string
+	for _, s := range rankSuspects(logs) {
+		ranked = append(ranked, fmt.Sprintf("%s:%v", bytes.TrimSpace(s.entry.P.Serialize()), s.logs))
+	}
+	assert.Equal(t, []string{"getuid():3", "getpid():2", "getgid():1"}, ranked)
+
+	common := commonEntries(logs[0], logs[1:])
+	require.Len(t, common, 2)
+	common = commonEntries(logs[1], logs[2:])
+	require.Len(t, common, 1)
+	assert.Equal(t, "getuid()\n", string(common[0].P.Serialize()))
+}
+
+func TestSearchSchedule(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	p, err := target.Deserialize([]byte("pause()\nalarm(0xa)\n"), prog.Strict)
+	require.NoError(t, err)
+	counter := 0
+	exec := &testExecInterface{
+		run: func(log []byte) (*instance.RunResult, error) {
+			// The race needs the async pause() and the delayed alarm(),
+			// with async pause() alone it's triggered only every other time.
+			if !bytes.Contains(log, []byte("pause() (async)")) {
+				return fakeCrashResult(""), nil
+			}
+			counter++
+			if bytes.Contains(log, []byte("alarm(0xa) (delay: 100)")) || counter%2 == 0 {
+				return fakeCrashResult("crashed"), nil
+			}
+			return fakeCrashResult(""), nil
+		},
+	}
+	ctx := &reproContext{
+		ctx:            context.Background(),
+		exec:           exec,
+		logf:           t.Logf,
+		stats:          new(Stats),
+		observedTitles: map[string]bool{"crashed": true},
+	}
+	res := &Result{
+		Prog:     p,
+		Duration: time.Minute,
+		Opts:     csource.Options{Threaded: true},
+	}
+	res, err = ctx.searchSchedule(res)
+	require.NoError(t, err)
+	assert.Equal(t, "pause() (async)\nalarm(0xa) (delay: 100)\n", string(res.Prog.Serialize()))
+
+	// Async calls need the threaded mode.
+	res.Prog = p
+	res.Opts.Threaded = false
+	res, err = ctx.searchSchedule(res)
+	require.NoError(t, err)
+	assert.Equal(t, "pause()\nalarm(0xa)\n", string(res.Prog.Serialize()))
+}
+
+func TestStateReplay(t *testing.T) {
+	// The crash needs the state created by getgid() executed long before the crash.
+	const log = `
+1m0s ago: executing state program 0 (id=1, state=sysctl):
+getgid()
+10s ago: executing program 1 (id=2):
+getpid()
+5s ago: executing program 1 (id=3):
+alarm(0xb)
+`
+	crashCondition := regexp.MustCompile(`(?s)getgid\(\).*alarm\(0xb\)`)
+	var executed []string
+	result, _, err := runTestRepro(t, log, &testExecInterface{
+		run: func(log []byte) (*instance.RunResult, error) {
+			executed = append(executed, string(log))
+			if crashCondition.Match(log) {
+				return fakeCrashResult("crashed"), nil
+			}
+			return fakeCrashResult(""), nil
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	// The state program is part of the reproducer, so the C reproducer works standalone.
+	assert.Equal(t, "getgid()\nalarm(0xb)\n", string(result.Prog.Serialize()))
+	assert.True(t, result.CRepro)
+	// The state is replayed before the separately tested programs (before bisection).
+	assert.Contains(t, executed, "executing program 0:\ngetgid()\nexecuting program 0:\nalarm(0xb)\n")
+}
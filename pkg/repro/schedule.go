@@ -0,0 +1,159 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"time"
+
+	"github.com/google/syzkaller/prog"
+)
+
+// Races are frequently triggered only with a particular schedule of the racing calls:
+// the calls need to run concurrently (async), one of them needs to start a bit later (delay),
+// or they need to run on different/the same CPUs. We greedily search for the schedule
+// that makes the reproducer most reliable. The schedule is expressed with call props,
+// so it's preserved in both syz and C reproducers.
+
+const (
+	// The max number of schedules we test (each one is tested up to 10 times).
+	maxScheduleCandidates = 16
+	// Reproducers that crash at least every other time are reliable enough.
+	// Reliability estimates are noisy, so searching for a better schedule for them
+	// risks adding props that are not really needed.
+	searchScheduleReliability = 0.5
+	// Reproducers with this reliability can't be improved.
+	goodReliability = 1.0
+	// A schedule is accepted only if it improves reliability by at least this much,
+	// otherwise we would add props that improve reliability only due to noise.
+	minScheduleGain = 0.2
+	// The search runs the reproducer many times and each run can take minutes,
+	// so we stop testing new schedules after this time.
+	maxScheduleSearchTime = 30 * time.Minute
+)
+
+// Delays (in microseconds) that we try for the racing calls.
+var scheduleDelays = []int{10, 100, 1000}
+
+type scheduleStage func(p *prog.Prog) []*prog.Prog
+
+var scheduleStages = []scheduleStage{
+	asyncSchedules,
+	delaySchedules,
+	cpuSchedules,
+}
+
+// Search for a call schedule that makes the reproducer more reliable.
+func (ctx *reproContext) searchSchedule(res *Result) (*Result, error) {
+	if !res.Opts.Threaded || len(res.Prog.Calls) < 2 {
+		return res, nil
+	}
+	ctx.reproLogf(2, "searching for call schedule")
+	start := time.Now()
+	defer func() {
+		ctx.stats.SearchScheduleTime = time.Since(start)
+	}()
+
+	best, err := ctx.scheduleReliability(res, res.Prog)
+	if err != nil {
+		return nil, err
+	}
+	ctx.reproLogf(3, "schedule: baseline reliability %.2f", best)
+	defer func() {
+		// The final validation reuses this measurement if the reproducer does not change.
+		ctx.setReliability(res, best)
+	}()
+	if best >= searchScheduleReliability {
+		return res, nil
+	}
+	tested := 0
+	budgetLeft := func() bool {
+		return best < goodReliability && tested < maxScheduleCandidates &&
+			time.Since(start) < maxScheduleSearchTime
+	}
+	for _, stage := range scheduleStages {
+		bestProg := res.Prog
+		for _, p := range stage(res.Prog) {
+			if !budgetLeft() {
+				break
+			}
+			tested++
+			reliability, err := ctx.scheduleReliability(res, p)
+			if err != nil {
+				return nil, err
+			}
+			ctx.reproLogf(3, "schedule: reliability %.2f\n%s", reliability, p.Serialize())
+			if reliability >= best+minScheduleGain {
+				best, bestProg = reliability, p
+			}
+		}
+		res.Prog = bestProg
+	}
+	ctx.reproLogf(2, "schedule: final reliability %.2f", best)
+	return res, nil
+}
+
+func (ctx *reproContext) scheduleReliability(res *Result, p *prog.Prog) (float64, error) {
+	return calculateReliability(func() (bool, error) {
+		ret, err := ctx.testProg(p, res.Duration, res.Opts, true)
+		if err != nil {
+			return false, err
+		}
+		return ret.Crashed, nil
+	})
+}
+
+// asyncSchedules makes single calls async, so that they race with the following calls.
+func asyncSchedules(p0 *prog.Prog) []*prog.Prog {
+	var ret []*prog.Prog
+	for i, call := range p0.Calls {
+		if call.Props.Async || !prog.CanBeAsync(p0, i) {
+			continue
+		}
+		p := p0.Clone()
+		p.Calls[i].Props.Async = true
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// delaySchedules delays either of the racing calls (an async call and the following call).
+func delaySchedules(p0 *prog.Prog) []*prog.Prog {
+	var ret []*prog.Prog
+	for _, i := range racingCalls(p0) {
+		for _, delay := range scheduleDelays {
+			for _, idx := range []int{i + 1, i} {
+				p := p0.Clone()
+				p.Calls[idx].Props.Delay = delay
+				ret = append(ret, p)
+			}
+		}
+	}
+	return ret
+}
+
+// cpuSchedules pins the racing calls to different CPUs (to run them truly in parallel)
+// or to the same CPU (so that they interleave only on preemption).
+func cpuSchedules(p0 *prog.Prog) []*prog.Prog {
+	var ret []*prog.Prog
+	for _, i := range racingCalls(p0) {
+		for _, cpus := range [][2]int{{1, 2}, {1, 1}} {
+			p := p0.Clone()
+			p.Calls[i].Props.CPU = cpus[0]
+			p.Calls[i+1].Props.CPU = cpus[1]
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// racingCalls returns indices of async calls, each one races with the next call.
+func racingCalls(p *prog.Prog) []int {
+	var ret []int
+	for i := 0; i+1 < len(p.Calls); i++ {
+		if p.Calls[i].Props.Async {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
@@ -18,7 +18,8 @@ import (
 type LastExecuting struct {
 	count     int
 	procs     []ExecRecord
-	hanged    []ExecRecord // hanged programs, kept forever
+	hanged    []ExecRecord                    // hanged programs, kept forever
+	state     map[prog.StateKind][]ExecRecord // the last programs that changed each kind of state
 	positions []int
 }
 
@@ -27,12 +28,20 @@ type ExecRecord struct {
 	Proc int
 	Prog []byte
 	Time time.Duration
+	// Persistent machine state changed by the program (see prog.Prog.StateChanges).
+	State prog.StateKind
 }
 
+// The max number of programs that changed each kind of machine state we keep.
+// These are kept regardless of the number of programs executed after them,
+// and form a checkpoint of the machine state in the crash log.
+const maxStateRecords = 10
+
 func MakeLastExecuting(procs, count int) *LastExecuting {
 	return &LastExecuting{
 		count:     count,
 		procs:     make([]ExecRecord, procs*count),
+		state:     make(map[prog.StateKind][]ExecRecord),
 		positions: make([]int, procs),
 	}
 }
@@ -64,16 +73,60 @@ func (last *LastExecuting) Hanged(id, proc int, progData []byte, now time.Durati
 	})
 }
 
+// Note execution of a program that changed persistent machine state.
+// The crash may depend on the state created by the program long after its execution.
+func (last *LastExecuting) NoteState(id, proc int, progData []byte, state prog.StateKind, now time.Duration) {
+	rec := ExecRecord{
+		ID:    id,
+		Proc:  proc,
+		Prog:  progData,
+		Time:  now,
+		State: state,
+	}
+	// Programs are kept per kind of state, so that e.g. lots of programs that mount filesystems
+	// don't evict the programs that changed sysctls.
+	for kind := prog.StateKind(1); kind <= state; kind <<= 1 {
+		if state&kind == 0 {
+			continue
+		}
+		records := last.state[kind]
+		if len(records) == maxStateRecords {
+			records = append(records[:0], records[1:]...)
+		}
+		last.state[kind] = append(records, rec)
+	}
+}
+
 // Returns a sorted set of last executing programs.
 // The records are sorted by time in ascending order.
 // ExecRecord.Time is the difference in start executing time between this
 // program and the program that started executing last.
 func (last *LastExecuting) Collect() []ExecRecord {
+	state := make(map[int]ExecRecord)
+	for _, records := range last.state {
+		for _, rec := range records {
+			state[rec.ID] = rec
+		}
+	}
+	for i := range last.procs {
+		if rec := &last.procs[i]; rec.Time != 0 && state[rec.ID].State != 0 {
+			// The program is still among the last programs, mark it instead of duplicating.
+			rec.State = state[rec.ID].State
+			delete(state, rec.ID)
+		}
+	}
 	procs := append(last.procs, last.hanged...)
+	for _, rec := range state {
+		procs = append(procs, rec)
+	}
 	last.procs = nil // The type must not be used after this.
 	last.hanged = nil
+	last.state = nil
 	sort.Slice(procs, func(i, j int) bool {
-		return procs[i].Time < procs[j].Time
+		if procs[i].Time != procs[j].Time {
+			return procs[i].Time < procs[j].Time
+		}
+		return procs[i].ID < procs[j].ID
 	})
 	max := procs[len(procs)-1].Time
 	for i := len(procs) - 1; i >= 0; i-- {
@@ -90,6 +143,12 @@ func PrependExecuting(rep *report.Report, lastExec []ExecRecord) {
 	buf := new(bytes.Buffer)
 	fmt.Fprintf(buf, "last executing test programs:\n\n")
 	for _, exec := range lastExec {
+		if exec.State != 0 {
+			// pkg/repro replays such programs before the candidate programs.
+			fmt.Fprintf(buf, "%v ago: executing state program %v (id=%v, state=%v):\n%s\n",
+				exec.Time, exec.Proc, exec.ID, exec.State, exec.Prog)
+			continue
+		}
 		fmt.Fprintf(buf, "%v ago: executing program %v (id=%v):\n%s\n", exec.Time, exec.Proc, exec.ID, exec.Prog)
 	}
 	fmt.Fprintf(buf, "kernel console output (not intermixed with test programs):\n\n")
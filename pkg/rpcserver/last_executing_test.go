@@ -5,7 +5,9 @@ package rpcserver
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/syzkaller/prog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -76,3 +78,34 @@ func TestLastExecutingHanged(t *testing.T) {
 		{ID: 9, Proc: 0, Prog: []byte("prog9"), Time: 0},
 	})
 }
+
+func TestLastExecutingState(t *testing.T) {
+	last := MakeLastExecuting(1, 2)
+	last.Note(1, 0, []byte("prog1"), 10)
+	last.NoteState(1, 0, []byte("prog1"), prog.StateSysctl, 10)
+	last.Note(2, 0, []byte("prog2"), 20)
+	last.Note(3, 0, []byte("prog3"), 30)
+	last.NoteState(3, 0, []byte("prog3"), prog.StateSysctl, 30)
+	last.Note(4, 0, []byte("prog4"), 40)
+	assert.Equal(t, last.Collect(), []ExecRecord{
+		{ID: 1, Proc: 0, Prog: []byte("prog1"), Time: 30, State: prog.StateSysctl},
+		{ID: 3, Proc: 0, Prog: []byte("prog3"), Time: 10, State: prog.StateSysctl},
+		{ID: 4, Proc: 0, Prog: []byte("prog4"), Time: 0},
+	})
+}
+
+func TestLastExecutingStateKinds(t *testing.T) {
+	last := MakeLastExecuting(1, 1)
+	last.Note(1, 0, []byte("prog1"), 1)
+	last.NoteState(1, 0, []byte("prog1"), prog.StateSysctl, 1)
+	for i := 2; i < maxStateRecords+3; i++ {
+		last.Note(i, 0, []byte("mount"), time.Duration(i))
+		last.NoteState(i, 0, []byte("mount"), prog.StateMount|prog.StateFaultInjection, time.Duration(i))
+	}
+	records := last.Collect()
+	// Lots of programs that mount filesystems don't evict the program that changed sysctls.
+	assert.Len(t, records, maxStateRecords+1)
+	assert.Equal(t, ExecRecord{ID: 1, Proc: 0, Prog: []byte("prog1"), Time: maxStateRecords + 1,
+		State: prog.StateSysctl}, records[0])
+	assert.Equal(t, 3, records[1].ID)
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"math/rand"
 	"net/url"
+	"os"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -52,6 +54,8 @@ type Config struct {
 	Slowdown      int
 	pcBase        uint64
 	localModules  []*vminfo.KernelModule
+	// If set, complete executor sessions are recorded into this dir (see session.go).
+	SessionDir string
 
 	// RPCServer closes the channel once the machine check has begun. Used for fault injection during testing.
 	machineCheckStarted chan struct{}
@@ -78,7 +82,9 @@ type Server interface {
 	TriagedCorpus()
 	Serve(context.Context) error
 	CreateInstance(id int, injectExec chan<- bool, updInfo dispatcher.UpdateInfo) chan error
-	ShutdownInstance(id int, crashed bool, extraExecs ...report.ExecutorInfo) ([]ExecRecord, []byte)
+	// ShutdownInstance returns the last executed programs, machine info and the session file
+	// of the crashed VM (see sessionRecorder.Shutdown), the caller is responsible for removing it.
+	ShutdownInstance(id int, crashed bool, extraExecs ...report.ExecutorInfo) ([]ExecRecord, []byte, string)
 	StopFuzzing(id int)
 	DistributeSignalDelta(plus signal.Signal)
 }
@@ -159,6 +165,10 @@ func New(cfg *RemoteConfig) (Server, error) {
 	if !cfg.Experimental.RemoteCover {
 		features &= ^flatrpc.FeatureExtraCoverage
 	}
+	sessionDir := ""
+	if cfg.Experimental.RecordSessions {
+		sessionDir = filepath.Join(cfg.Workdir, "sessions")
+	}
 	return newImpl(&Config{
 		Config: vminfo.Config{
 			Target:     cfg.Target,
@@ -179,6 +189,7 @@ func New(cfg *RemoteConfig) (Server, error) {
 		// gVisor/Starnix are not Linux, so filtering against Linux ranges won't work.
 		FilterSignal:      cfg.Type != targets.GVisor && cfg.Type != targets.Starnix,
 		PrintMachineCheck: true,
+		SessionDir:        sessionDir,
 		Procs:             cfg.Procs,
 		Slowdown:          cfg.Timeouts.Slowdown,
 		pcBase:            pcBase,
@@ -305,7 +316,9 @@ func (serv *server) handleConn(ctx context.Context, conn *flatrpc.Conn) error {
 		serv.CreateInstance(id, nil, nil)
 		defer func() {
 			serv.StopFuzzing(id)
-			serv.ShutdownInstance(id, true)
+			if _, _, session := serv.ShutdownInstance(id, true); session != "" {
+				os.Remove(session)
+			}
 		}()
 	} else if err := checkRevisions(connectReq, serv.cfg.Target); err != nil {
 		return err
@@ -542,13 +555,16 @@ func (serv *server) CreateInstance(id int, injectExec chan<- bool, updInfo dispa
 		debug:         serv.cfg.Debug,
 		debugTimeouts: serv.cfg.DebugTimeouts,
 		sysTarget:     serv.sysTarget,
+		timeouts:      serv.timeouts,
 		injectExec:    injectExec,
 		infoc:         make(chan chan []byte),
 		requests:      make(map[int64]*queue.Request),
 		executing:     make(map[int64]bool),
 		hanged:        make(map[int64]bool),
+		stateChanges:  make(map[int64]prog.StateKind),
 		// Executor may report proc IDs that are larger than serv.cfg.Procs.
 		lastExec: MakeLastExecuting(prog.MaxPids, 6),
+		session:  newSessionRecorder(serv.cfg.SessionDir, id),
 		stats:    serv.runnerStats,
 		procs:    serv.cfg.Procs,
 		updInfo:  updInfo,
@@ -577,12 +593,14 @@ func (serv *server) StopFuzzing(id int) {
 	runner.Stop()
 }
 
-func (serv *server) ShutdownInstance(id int, crashed bool, extraExecs ...report.ExecutorInfo) ([]ExecRecord, []byte) {
+func (serv *server) ShutdownInstance(id int, crashed bool,
+	extraExecs ...report.ExecutorInfo) ([]ExecRecord, []byte, string) {
 	serv.mu.Lock()
 	runner := serv.runners[id]
 	delete(serv.runners, id)
 	serv.mu.Unlock()
-	return runner.Shutdown(crashed, extraExecs...), runner.MachineInfo()
+	records, session := runner.Shutdown(crashed, extraExecs...)
+	return records, runner.MachineInfo(), session
 }
 
 func (serv *server) DistributeSignalDelta(plus signal.Signal) {
@@ -18,6 +18,7 @@ import (
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
@@ -34,6 +35,7 @@ type Runner struct {
 	debug         bool
 	debugTimeouts bool
 	sysTarget     *targets.Target
+	timeouts      targets.Timeouts
 	stats         *runnerStats
 	finished      chan bool
 	injectExec    chan<- bool
@@ -43,7 +45,9 @@ type Runner struct {
 	requests      map[int64]*queue.Request
 	executing     map[int64]bool
 	hanged        map[int64]bool
+	stateChanges  map[int64]prog.StateKind
 	lastExec      *LastExecuting
+	session       *sessionRecorder
 	updInfo       dispatcher.UpdateInfo
 	resultCh      chan error
 
@@ -329,24 +333,51 @@ func (runner *Runner) sendRequest(req *queue.Request) error {
 			avoid |= uint64(1 << id.Proc)
 		}
 	}
+	if req.HangProne && runner.procs > 1 {
+		// Hang-prone programs are executed only on the last proc,
+		// so that they don't block execution of other programs on all procs.
+		avoid |= 1<<(runner.procs-1) - 1
+	}
+	execReq := &flatrpc.ExecRequest{
+		Id:        id,
+		Type:      req.Type,
+		Avoid:     avoid,
+		Data:      data,
+		Flags:     flags,
+		ExecOpts:  &opts,
+		AllSignal: allSignal,
+		TimeoutMs: runner.timeoutMs(req),
+	}
 	msg := &flatrpc.HostMessage{
 		Msg: &flatrpc.HostMessages{
-			Type: flatrpc.HostMessagesRawExecRequest,
-			Value: &flatrpc.ExecRequest{
-				Id:        id,
-				Type:      req.Type,
-				Avoid:     avoid,
-				Data:      data,
-				Flags:     flags,
-				ExecOpts:  &opts,
-				AllSignal: allSignal,
-			},
+			Type:  flatrpc.HostMessagesRawExecRequest,
+			Value: execReq,
 		},
 	}
 	runner.requests[id] = req
+	// Computed once per request, the request may be retried and executed several times.
+	if req.Type == flatrpc.RequestTypeProgram {
+		if state := req.Prog.StateChanges(); state != 0 {
+			runner.stateChanges[id] = state
+		}
+	}
+	runner.session.Request(req, execReq)
 	return flatrpc.Send(runner.conn, msg)
 }
 
+// timeoutMs returns program timeout for the request (0 means the default program timeout).
+func (runner *Runner) timeoutMs(req *queue.Request) int32 {
+	if req.Timeout == 0 || req.Type != flatrpc.RequestTypeProgram {
+		return 0
+	}
+	// Too short timeouts would turn transient slowdowns of the VM into false hangs.
+	timeout := max(req.Timeout, runner.timeouts.Program/5, 4*runner.timeouts.Syscall)
+	if timeout >= runner.timeouts.Program {
+		return 0
+	}
+	return int32(timeout / time.Millisecond)
+}
+
 func (runner *Runner) handleExecutingMessage(msg *flatrpc.ExecutingMessage) error {
 	req := runner.requests[msg.Id]
 	if req == nil {
@@ -381,7 +412,12 @@ func (runner *Runner) handleExecutingMessage(msg *flatrpc.ExecutingMessage) erro
 	default:
 		panic(fmt.Sprintf("unhandled request type %v", req.Type))
 	}
-	runner.lastExec.Note(int(msg.Id), proc, data, osutil.MonotonicNano())
+	now := osutil.MonotonicNano()
+	runner.lastExec.Note(int(msg.Id), proc, data, now)
+	if state := runner.stateChanges[msg.Id]; state != 0 {
+		runner.lastExec.NoteState(int(msg.Id), proc, data, state, now)
+	}
+	runner.session.Executing(msg)
 	select {
 	case runner.injectExec <- true:
 	default:
@@ -405,6 +441,7 @@ func (runner *Runner) handleExecResult(msg *flatrpc.ExecResult) error {
 	}
 	delete(runner.requests, msg.Id)
 	delete(runner.executing, msg.Id)
+	delete(runner.stateChanges, msg.Id)
 	if req.Type == flatrpc.RequestTypeProgram && msg.Info != nil {
 		for len(msg.Info.Calls) < len(req.Prog.Calls) {
 			msg.Info.Calls = append(msg.Info.Calls, &flatrpc.CallInfo{
@@ -449,6 +486,7 @@ func (runner *Runner) handleExecResult(msg *flatrpc.ExecResult) error {
 		}
 		runner.hanged[msg.Id] = true
 	}
+	runner.session.Result(msg, status)
 	req.Done(&queue.Result{
 		Executor: queue.ExecutorID{
 			VM:   runner.id,
@@ -515,12 +553,21 @@ func (runner *Runner) convertCallInfo(call *flatrpc.CallInfo) {
 }
 
 func (runner *Runner) SendSignalUpdate(plus []uint64) error {
+	// Dense parts of the signal are sent as bitmaps to reduce the traffic.
+	rest, offsets, bitmap := signal.EncodeBitmap(runner.canonicalizer.Decanonicalize(plus))
+	update := &flatrpc.SignalUpdate{
+		NewMax: rest,
+	}
+	if len(offsets) != 0 {
+		update.NewMaxBitmap = &flatrpc.SignalBitmap{
+			Offsets: offsets,
+			Bitmap:  bitmap,
+		}
+	}
 	msg := &flatrpc.HostMessage{
 		Msg: &flatrpc.HostMessages{
-			Type: flatrpc.HostMessagesRawSignalUpdate,
-			Value: &flatrpc.SignalUpdate{
-				NewMax: runner.canonicalizer.Decanonicalize(plus),
-			},
+			Type:  flatrpc.HostMessagesRawSignalUpdate,
+			Value: update,
 		},
 	}
 	return flatrpc.Send(runner.conn, msg)
@@ -546,7 +593,9 @@ func (runner *Runner) Stop() {
 	}
 }
 
-func (runner *Runner) Shutdown(crashed bool, extraExecs ...report.ExecutorInfo) []ExecRecord {
+// Shutdown returns the last executed programs and, if the VM has crashed and session recording
+// is enabled, the session file (see sessionRecorder.Shutdown).
+func (runner *Runner) Shutdown(crashed bool, extraExecs ...report.ExecutorInfo) ([]ExecRecord, string) {
 	runner.mu.Lock()
 	runner.stopped = true
 	finished := runner.finished
@@ -575,7 +624,8 @@ func (runner *Runner) Shutdown(crashed bool, extraExecs ...report.ExecutorInfo)
 		}
 		req.Done(&queue.Result{Status: status})
 	}
-	return records
+	session := runner.session.Shutdown(crashed)
+	return records, session
 }
 
 func (runner *Runner) MachineInfo() []byte {
@@ -0,0 +1,335 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Session recording allows to capture the complete sequence of requests sent to a VM
+// and the results received from it, so that the session can be replayed later
+// (see tools/syz-replay). This is useful for bugs that happen only after hours of fuzzing
+// and that pkg/repro fails to reproduce from the last executed programs.
+//
+// Sessions are written as JSON lines into SessionDir/vmN.jsonl. The file is rotated
+// on every VM restart and when it grows larger than sessionFileSize, at most sessionFiles
+// files are kept per VM (vmN.jsonl is the current one, vmN.jsonl.1 is the previous one, etc).
+// If the VM crashes, the current file is moved out of the rotation to a unique name,
+// so that the manager can save it along with the crash.
+
+type SessionEventKind string
+
+const (
+	SessionStart     SessionEventKind = "start"
+	SessionRequest   SessionEventKind = "request"
+	SessionExecuting SessionEventKind = "executing"
+	SessionResult    SessionEventKind = "result"
+	SessionShutdown  SessionEventKind = "shutdown"
+)
+
+// SessionEvent is a single record in a session file.
+// Only fields relevant for the Kind are set.
+type SessionEvent struct {
+	Kind SessionEventKind `json:"kind"`
+	// Time since the session start.
+	Time time.Duration `json:"time"`
+	ID   int64         `json:"id,omitempty"`
+
+	// SessionStart fields.
+	VM    int       `json:"vm,omitempty"`
+	Start time.Time `json:"start,omitempty"`
+	// Index of the file within the session (non-0 if the session was rotated due to size).
+	Part int `json:"part,omitempty"`
+
+	// SessionRequest fields.
+	Type        flatrpc.RequestType `json:"type,omitempty"`
+	Prog        string              `json:"prog,omitempty"`
+	BinaryFile  string              `json:"binary_file,omitempty"`
+	GlobPattern string              `json:"glob,omitempty"`
+	Flags       flatrpc.RequestFlag `json:"flags,omitempty"`
+	ExecOpts    *flatrpc.ExecOpts   `json:"exec_opts,omitempty"`
+	AllSignal   []int32             `json:"all_signal,omitempty"`
+	Avoid       uint64              `json:"avoid,omitempty"`
+
+	// SessionExecuting and SessionResult fields.
+	Proc int `json:"proc,omitempty"`
+	Try  int `json:"try,omitempty"`
+
+	// SessionResult fields.
+	Status  queue.Status   `json:"status,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Hanged  bool           `json:"hanged,omitempty"`
+	Elapsed time.Duration  `json:"elapsed,omitempty"`
+	Calls   []*SessionCall `json:"calls,omitempty"`
+
+	// SessionShutdown fields.
+	Crashed bool `json:"crashed,omitempty"`
+}
+
+type SessionCall struct {
+	Flags  flatrpc.CallFlag `json:"flags,omitempty"`
+	Error  int32            `json:"error,omitempty"`
+	Signal int              `json:"signal,omitempty"`
+	Cover  int              `json:"cover,omitempty"`
+}
+
+const (
+	sessionFileSize = 256 << 20
+	sessionFiles    = 8
+)
+
+// sessionRecorder writes session events for a single VM.
+// All methods are nil-receiver safe, nil recorder means that recording is disabled.
+// Recording is best-effort: on any errors it logs them and stops recording.
+type sessionRecorder struct {
+	file  string
+	vm    int
+	start time.Time
+	part  int
+	size  int
+	f     *os.File
+	w     *bufio.Writer
+}
+
+func newSessionRecorder(dir string, vm int) *sessionRecorder {
+	if dir == "" {
+		return nil
+	}
+	if err := osutil.MkdirAll(dir); err != nil {
+		log.Logf(0, "failed to create session dir: %v", err)
+		return nil
+	}
+	rec := &sessionRecorder{
+		file:  filepath.Join(dir, fmt.Sprintf("vm%v.jsonl", vm)),
+		vm:    vm,
+		start: time.Now(),
+	}
+	if !rec.open() {
+		return nil
+	}
+	return rec
+}
+
+func (rec *sessionRecorder) open() bool {
+	for i := sessionFiles - 1; i > 0; i-- {
+		os.Rename(rec.fileName(i-1), rec.fileName(i))
+	}
+	f, err := os.Create(rec.file)
+	if err != nil {
+		log.Logf(0, "failed to create session file: %v", err)
+		return false
+	}
+	rec.f = f
+	rec.w = bufio.NewWriterSize(f, 64<<10)
+	rec.size = 0
+	rec.write(&SessionEvent{
+		Kind:  SessionStart,
+		VM:    rec.vm,
+		Start: rec.start,
+		Part:  rec.part,
+	})
+	return rec.f != nil
+}
+
+func (rec *sessionRecorder) fileName(i int) string {
+	if i == 0 {
+		return rec.file
+	}
+	return fmt.Sprintf("%v.%v", rec.file, i)
+}
+
+func (rec *sessionRecorder) write(ev *SessionEvent) {
+	if rec == nil || rec.f == nil {
+		return
+	}
+	ev.Time = time.Since(rec.start)
+	data, err := json.Marshal(ev)
+	if err != nil {
+		panic(err)
+	}
+	data = append(data, '\n')
+	if _, err := rec.w.Write(data); err != nil {
+		log.Logf(0, "failed to write session file: %v", err)
+		rec.Close()
+		return
+	}
+	rec.size += len(data)
+	if rec.size >= sessionFileSize {
+		rec.Close()
+		rec.part++
+		rec.open()
+	}
+}
+
+func (rec *sessionRecorder) Request(req *queue.Request, msg *flatrpc.ExecRequest) {
+	if rec == nil {
+		return
+	}
+	ev := &SessionEvent{
+		Kind:        SessionRequest,
+		ID:          msg.Id,
+		Type:        msg.Type,
+		BinaryFile:  req.BinaryFile,
+		GlobPattern: req.GlobPattern,
+		Flags:       msg.Flags,
+		ExecOpts:    msg.ExecOpts,
+		AllSignal:   msg.AllSignal,
+		Avoid:       msg.Avoid,
+	}
+	if req.Prog != nil {
+		ev.Prog = string(req.Prog.Serialize())
+	}
+	rec.write(ev)
+}
+
+func (rec *sessionRecorder) Executing(msg *flatrpc.ExecutingMessage) {
+	rec.write(&SessionEvent{
+		Kind: SessionExecuting,
+		ID:   msg.Id,
+		Proc: int(msg.ProcId),
+		Try:  int(msg.Try),
+	})
+}
+
+func (rec *sessionRecorder) Result(msg *flatrpc.ExecResult, status queue.Status) {
+	if rec == nil {
+		return
+	}
+	ev := &SessionEvent{
+		Kind:   SessionResult,
+		ID:     msg.Id,
+		Proc:   int(msg.Proc),
+		Status: status,
+		Error:  msg.Error,
+		Hanged: msg.Hanged,
+	}
+	if msg.Info != nil {
+		ev.Elapsed = time.Duration(msg.Info.Elapsed)
+		for _, call := range msg.Info.Calls {
+			ev.Calls = append(ev.Calls, &SessionCall{
+				Flags:  call.Flags,
+				Error:  call.Error,
+				Signal: len(call.Signal),
+				Cover:  len(call.Cover),
+			})
+		}
+	}
+	rec.write(ev)
+}
+
+// Shutdown finishes recording. If the VM has crashed, it returns the name of the file
+// with the crashed session, the caller is responsible for removing the file.
+func (rec *sessionRecorder) Shutdown(crashed bool) string {
+	rec.write(&SessionEvent{
+		Kind:    SessionShutdown,
+		Crashed: crashed,
+	})
+	if rec == nil || rec.f == nil {
+		return ""
+	}
+	rec.Close()
+	if !crashed {
+		return ""
+	}
+	file := filepath.Join(filepath.Dir(rec.file), fmt.Sprintf("vm%v-crash-%v.jsonl", rec.vm, time.Now().UnixNano()))
+	if err := os.Rename(rec.file, file); err != nil {
+		log.Logf(0, "failed to rename session file: %v", err)
+		return ""
+	}
+	return file
+}
+
+func (rec *sessionRecorder) Close() {
+	if rec == nil || rec.f == nil {
+		return
+	}
+	if err := rec.w.Flush(); err != nil {
+		log.Logf(0, "failed to write session file: %v", err)
+	}
+	rec.f.Close()
+	rec.f = nil
+}
+
+// ReadSession reads session events from the given files.
+// The files must be given in the chronological order (e.g. vm0.jsonl.1 vm0.jsonl).
+// A truncated last record (e.g. if the manager was killed) is ignored.
+func ReadSession(files ...string) ([]*SessionEvent, error) {
+	var events []*SessionEvent
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bufio.NewReader(f))
+		for {
+			ev := new(SessionEvent)
+			if err := dec.Decode(ev); err != nil {
+				if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+					break
+				}
+				f.Close()
+				return nil, fmt.Errorf("%v: record %v: %w", file, len(events), err)
+			}
+			events = append(events, ev)
+		}
+		f.Close()
+	}
+	return events, nil
+}
+
+// SessionExec describes a single request execution in a session.
+type SessionExec struct {
+	Request *SessionEvent
+	// Proc and Time of the (first) executing event.
+	Proc int
+	Time time.Duration
+	// Result is nil if the execution has not finished (e.g. the VM has crashed).
+	Result *SessionEvent
+}
+
+// SessionExecs returns requests in the order they started executing in the VM.
+// Requests that were sent but never started are not included.
+// Requests are identified by IDs that are unique only within a single VM session,
+// so the events must belong to a single session.
+func SessionExecs(events []*SessionEvent) []*SessionExec {
+	requests := make(map[int64]*SessionEvent)
+	started := make(map[int64]*SessionExec)
+	var execs []*SessionExec
+	for _, ev := range events {
+		switch ev.Kind {
+		case SessionRequest:
+			requests[ev.ID] = ev
+		case SessionExecuting:
+			if started[ev.ID] != nil || requests[ev.ID] == nil {
+				// Retries are done by the executor itself, or the request
+				// was recorded in a file that was already rotated out.
+				continue
+			}
+			exec := &SessionExec{
+				Request: requests[ev.ID],
+				Proc:    ev.Proc,
+				Time:    ev.Time,
+			}
+			started[ev.ID] = exec
+			execs = append(execs, exec)
+		case SessionResult:
+			if exec := started[ev.ID]; exec != nil {
+				exec.Result = ev
+			}
+		}
+	}
+	return execs
+}
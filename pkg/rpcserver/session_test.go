@@ -0,0 +1,97 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	// The first session is rotated out by the second one.
+	newSessionRecorder(dir, 1).Shutdown(false)
+	rec := newSessionRecorder(dir, 1)
+	opts := &flatrpc.ExecOpts{
+		EnvFlags:  flatrpc.ExecEnvSandboxNone,
+		ExecFlags: flatrpc.ExecFlagCollectSignal,
+	}
+	progs := []string{"test()\n", "test$res0()\n", "test()\ntest()\n"}
+	for i, text := range progs {
+		p, err := target.Deserialize([]byte(text), prog.Strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.Request(&queue.Request{Prog: p}, &flatrpc.ExecRequest{
+			Id:        int64(i + 1),
+			ExecOpts:  opts,
+			AllSignal: []int32{0},
+		})
+	}
+	rec.Executing(&flatrpc.ExecutingMessage{Id: 2, ProcId: 1})
+	rec.Executing(&flatrpc.ExecutingMessage{Id: 1, ProcId: 0})
+	rec.Executing(&flatrpc.ExecutingMessage{Id: 1, ProcId: 0, Try: 1})
+	rec.Result(&flatrpc.ExecResult{
+		Id:   1,
+		Info: &flatrpc.ProgInfo{Calls: []*flatrpc.CallInfo{{Error: 1, Signal: []uint64{1, 2}}}},
+	}, queue.Success)
+	rec.Executing(&flatrpc.ExecutingMessage{Id: 3, ProcId: 1})
+	rec.Result(&flatrpc.ExecResult{Id: 2, Proc: 1, Hanged: true}, queue.Hanged)
+	crashFile := rec.Shutdown(true)
+
+	// The crashed session is moved out of the rotation.
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ElementsMatch(t, []string{
+		crashFile,
+		filepath.Join(dir, "vm1.jsonl.1"),
+	}, files)
+	newSessionRecorder(dir, 1).Shutdown(false)
+	assert.FileExists(t, crashFile)
+
+	// Simulate a truncated file.
+	data, err := os.ReadFile(crashFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "truncated")
+	if err := os.WriteFile(file, data[:len(data)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ReadSession(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, SessionStart, events[0].Kind)
+	assert.Equal(t, 1, events[0].VM)
+	assert.Equal(t, SessionResult, events[len(events)-1].Kind)
+
+	execs := SessionExecs(events)
+	assert.Len(t, execs, 3)
+	assert.Equal(t, progs[1], execs[0].Request.Prog)
+	assert.Equal(t, 1, execs[0].Proc)
+	assert.Equal(t, queue.Hanged, execs[0].Result.Status)
+	assert.True(t, execs[0].Result.Hanged)
+	assert.Equal(t, progs[0], execs[1].Request.Prog)
+	assert.Equal(t, 0, execs[1].Proc)
+	assert.Equal(t, *opts, *execs[1].Request.ExecOpts)
+	assert.Equal(t, []int32{0}, execs[1].Request.AllSignal)
+	assert.Equal(t, []*SessionCall{{Error: 1, Signal: 2}}, execs[1].Result.Calls)
+	assert.Equal(t, progs[2], execs[2].Request.Prog)
+	assert.Nil(t, execs[2].Result)
+	assert.True(t, execs[0].Time <= execs[1].Time && execs[1].Time <= execs[2].Time)
+}
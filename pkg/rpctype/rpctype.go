@@ -16,6 +16,10 @@ type HubConnectArgs struct {
 	HTTP string
 	// See pkg/mgrconfig.Config.HubDomain.
 	Domain string
+	// Identifies the kernel build the manager tests (optional).
+	// Signal (CorpusSignal, Digest) is comparable only between managers with the same signal space,
+	// regardless of their domains. If empty, the hub does not filter programs by signal.
+	SignalSpace string
 	// Manager has started with an empty corpus and requests whole hub corpus.
 	Fresh bool
 	// Set of system call names supported by this manager.
@@ -23,6 +27,11 @@ type HubConnectArgs struct {
 	Calls []string
 	// Current manager corpus.
 	Corpus [][]byte
+	// Signal of the corresponding Corpus programs (optional), see signal.Signal.DigestHashes.
+	CorpusSignal [][]uint32
+	// Digest of the signal of the whole manager corpus (optional), see signal.Digest.
+	// The hub does not send programs whose signal is already covered by the digest.
+	Digest []byte
 }
 
 type HubSyncArgs struct {
@@ -33,10 +42,14 @@ type HubSyncArgs struct {
 	NeedRepros bool
 	// Programs added to corpus since last sync or connect.
 	Add [][]byte
+	// Signal of the corresponding Add programs (optional), see HubConnectArgs.CorpusSignal.
+	AddSignal [][]uint32
 	// Hashes of programs removed from corpus since last sync or connect.
 	Del []string
 	// Repros found since last sync.
 	Repros [][]byte
+	// Updated digest of the manager corpus signal (optional), see HubConnectArgs.Digest.
+	Digest []byte
 }
 
 type HubSyncRes struct {
@@ -0,0 +1,80 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// This file contains the reference implementation of the compact max signal representation
+// sent to executors (see SignalBitmapRaw in pkg/flatrpc/flatrpc.fbs).
+// The executor max signal filter (executor/cover_filter.h) discards low 3 bits of elements,
+// so dense parts of signal can be sent as bitmaps with 8-byte granularity w/o any loss of precision
+// as far as the executor is concerned.
+
+const (
+	// BitmapChunk is the range of elements covered by a single bitmap
+	// (must be equal to flatrpc.ConstSignalBitmapChunk).
+	BitmapChunk       = 64 << 10
+	bitmapGranularity = 8
+	// BitmapChunkBytes is the size of a single chunk bitmap.
+	BitmapChunkBytes = BitmapChunk / bitmapGranularity / 8
+	// A chunk is encoded as bitmap only if it's smaller than the list of its elements
+	// (the bitmap costs additional 8 bytes for the offset).
+	bitmapMinElems = (BitmapChunkBytes+8)/8 + 1
+)
+
+// EncodeBitmap splits raw signal into elements that are cheaper to send as is (rest),
+// and dense chunks encoded as bitmaps (start elements of the chunks and concatenated chunk bitmaps).
+func EncodeBitmap(raw []uint64) (rest, offsets []uint64, bitmap []byte) {
+	chunks := make(map[uint64]int)
+	for _, e := range raw {
+		chunks[e/BitmapChunk]++
+	}
+	for chunk, elems := range chunks {
+		if elems >= bitmapMinElems {
+			offsets = append(offsets, chunk*BitmapChunk)
+		}
+	}
+	if len(offsets) == 0 {
+		return raw, nil, nil
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	index := make(map[uint64]int, len(offsets))
+	for i, offset := range offsets {
+		index[offset/BitmapChunk] = i
+	}
+	bitmap = make([]byte, len(offsets)*BitmapChunkBytes)
+	for _, e := range raw {
+		i, ok := index[e/BitmapChunk]
+		if !ok {
+			rest = append(rest, e)
+			continue
+		}
+		bit := e % BitmapChunk / bitmapGranularity
+		bitmap[i*BitmapChunkBytes+int(bit/8)] |= 1 << (bit % 8)
+	}
+	return rest, offsets, bitmap
+}
+
+// DecodeBitmap returns elements present in the bitmaps (with low 3 bits cleared)
+// the same way the executor does it.
+func DecodeBitmap(offsets []uint64, bitmap []byte) ([]uint64, error) {
+	if len(bitmap) != len(offsets)*BitmapChunkBytes {
+		return nil, fmt.Errorf("bad signal bitmap size %v for %v chunks", len(bitmap), len(offsets))
+	}
+	var res []uint64
+	for i, offset := range offsets {
+		chunk := bitmap[i*BitmapChunkBytes : (i+1)*BitmapChunkBytes]
+		for b, v := range chunk {
+			for bit := 0; v != 0; bit, v = bit+1, v>>1 {
+				if v&1 != 0 {
+					res = append(res, offset+uint64(b*8+bit)*bitmapGranularity)
+				}
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitmapEquivalence(t *testing.T) {
+	rnd := rand.New(testutil.RandSource(t))
+	for i := 0; i < testutil.IterCount(); i++ {
+		s := randBitmapSignal(rnd)
+		raw := s.ToRaw()
+		rest, offsets, bitmap := EncodeBitmap(raw)
+		decoded, err := DecodeBitmap(offsets, bitmap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The executor filter discards low 3 bits of elements,
+		// so the original and the encoded signal must produce the same filter.
+		want := bitmapFilter(raw)
+		got := bitmapFilter(append(rest, decoded...))
+		assert.Equal(t, want, got)
+		// Elements sent as is must be present in the original signal.
+		for _, e := range rest {
+			assert.True(t, s.Contains(e))
+		}
+		assert.LessOrEqual(t, len(rest)*8+len(offsets)*8+len(bitmap), len(raw)*8)
+	}
+}
+
+func TestBitmapEncoding(t *testing.T) {
+	var raw []uint64
+	// Dense chunk.
+	for i := uint64(0); i < 1000; i++ {
+		raw = append(raw, 0xffffffff81000000+i*16)
+	}
+	// Sparse chunk.
+	raw = append(raw, 0xffffffff82000000, 0xffffffff82000100)
+	rest, offsets, bitmap := EncodeBitmap(raw)
+	assert.Equal(t, []uint64{0xffffffff82000000, 0xffffffff82000100}, rest)
+	assert.Equal(t, []uint64{0xffffffff81000000}, offsets)
+	assert.Len(t, bitmap, BitmapChunkBytes)
+	decoded, err := DecodeBitmap(offsets, bitmap)
+	assert.NoError(t, err)
+	assert.Equal(t, raw[:1000], decoded)
+
+	_, err = DecodeBitmap(offsets, bitmap[1:])
+	assert.Error(t, err)
+}
+
+func randBitmapSignal(rnd *rand.Rand) Signal {
+	var raw []uint64
+	base := uint64(0xffffffff81000000)
+	for chunk := 0; chunk < 10; chunk++ {
+		start := base + uint64(rnd.Intn(1<<10))*BitmapChunk
+		// Density of chunks varies from very sparse to very dense.
+		elems := rnd.Intn(1 << rnd.Intn(14))
+		for i := 0; i < elems; i++ {
+			raw = append(raw, start+uint64(rnd.Intn(BitmapChunk)))
+		}
+	}
+	return FromRaw(raw, 0)
+}
+
+func bitmapFilter(raw []uint64) map[uint64]bool {
+	res := make(map[uint64]bool)
+	for _, e := range raw {
+		res[e/bitmapGranularity] = true
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"math/bits"
+)
+
+// Digest is a compact probabilistic summary of a signal set (a bloom filter).
+// It allows to check if signal of a program is (most likely) already covered by the set
+// w/o transferring the whole set. False positives are possible (a tiny fraction of signal
+// may be falsely considered as covered), false negatives are not.
+//
+// Elements are checked by their 32-bit hashes (see DigestHashes) rather than
+// by the elements themselves to make signal of individual programs more compact as well.
+type Digest []byte
+
+const (
+	digestBitsPerElem = 10
+	digestHashes      = 4
+	digestMinSize     = 1 << 10
+)
+
+// Digest returns digest of the signal.
+// The size is ~10 bits per element, which gives ~1% false positive rate.
+func (s Signal) Digest() Digest {
+	size := digestMinSize
+	for size*8 < len(s)*digestBitsPerElem {
+		size *= 2
+	}
+	d := make(Digest, size)
+	for e := range s {
+		d.add(hashElem(e))
+	}
+	return d
+}
+
+// DigestHashes returns hashes of the signal elements that can be checked with Digest.ContainsAll.
+func (s Signal) DigestHashes() []uint32 {
+	res := make([]uint32, 0, len(s))
+	for e := range s {
+		res = append(res, hashElem(e))
+	}
+	return res
+}
+
+// ContainsAll returns true if all hashes are contained in the digest.
+// Empty (or malformed) digest does not contain anything.
+func (d Digest) ContainsAll(hashes []uint32) bool {
+	if !d.valid() {
+		return false
+	}
+	for _, h := range hashes {
+		if !d.contains(h) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d Digest) valid() bool {
+	return len(d) >= digestMinSize && len(d)&(len(d)-1) == 0
+}
+
+func (d Digest) add(h uint32) {
+	mask := uint32(len(d)*8 - 1)
+	h1, h2 := digestHashPair(h)
+	for i := uint32(0); i < digestHashes; i++ {
+		bit := (h1 + i*h2) & mask
+		d[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+func (d Digest) contains(h uint32) bool {
+	mask := uint32(len(d)*8 - 1)
+	h1, h2 := digestHashPair(h)
+	for i := uint32(0); i < digestHashes; i++ {
+		bit := (h1 + i*h2) & mask
+		if d[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// digestHashPair derives 2 independent hashes for double hashing of bloom filter bits.
+func digestHashPair(h uint32) (uint32, uint32) {
+	return h, bits.RotateLeft32(h*0x9e3779b1, 16) | 1
+}
+
+func hashElem(e elemType) uint32 {
+	// The finalizer of splitmix64.
+	x := uint64(e)
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return uint32(x)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"slices"
+)
+
+// With coverage edges, executor computes signal elements as pc ^ (hash(prev_pc & mask) & mask)
+// (see write_signal in executor/executor.cc). Only the low 12 bits of PCs are mixed in,
+// so the element keeps the high bits of the PC.
+const edgeMask = 1<<12 - 1
+
+// ElemEdge returns the coverage edge (the previous and the current PC) that produced
+// the signal element. The edge is searched among the PCs the program covered.
+// If edges are not used, the element is the PC itself and prev is 0.
+// hashed says if the executor hashes previous PCs (it does not do so for the test OS).
+// Since only 12 bits of the previous PC are used, the edge may be ambiguous,
+// in such case the first matching edge is returned.
+func ElemEdge(elem uint64, pcs []uint64, edges, hashed bool) (prev, pc uint64, ok bool) {
+	if !edges {
+		return 0, elem, slices.Contains(pcs, elem)
+	}
+	// The first PC in the trace has 0 as the previous PC.
+	prevs := map[uint64]uint64{edgeHash(0, hashed): 0}
+	for _, pc := range pcs {
+		h := edgeHash(pc, hashed)
+		if _, ok := prevs[h]; !ok {
+			prevs[h] = pc
+		}
+	}
+	for _, pc := range pcs {
+		if pc&^edgeMask != elem&^edgeMask {
+			continue
+		}
+		if prev, ok := prevs[(elem^pc)&edgeMask]; ok {
+			return prev, pc, true
+		}
+	}
+	return 0, 0, false
+}
+
+// edgeHash is the Go version of hash(prev_pc & mask) & mask from executor/executor.cc.
+func edgeHash(pc uint64, hashed bool) uint64 {
+	a := uint32(pc & edgeMask)
+	if hashed {
+		a = (a ^ 61) ^ (a >> 16)
+		a = a + (a << 3)
+		a = a ^ (a >> 4)
+		a = a * 0x27d4eb2d
+		a = a ^ (a >> 15)
+	}
+	return uint64(a) & edgeMask
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElemEdge(t *testing.T) {
+	pcs := []uint64{0xffffffff81001234, 0xffffffff81002345, 0xffffffff81003456}
+	for _, hashed := range []bool{false, true} {
+		// The elements as they are computed by the executor for the trace pcs[0], pcs[2].
+		first := pcs[0] ^ edgeHash(0, hashed)
+		second := pcs[2] ^ edgeHash(pcs[0], hashed)
+		prev, pc, ok := ElemEdge(first, pcs, true, hashed)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(0), prev)
+		assert.Equal(t, pcs[0], pc)
+		prev, pc, ok = ElemEdge(second, pcs, true, hashed)
+		assert.True(t, ok)
+		assert.Equal(t, pcs[0], prev)
+		assert.Equal(t, pcs[2], pc)
+		_, _, ok = ElemEdge(0xffffffff81004000, pcs, true, hashed)
+		assert.False(t, ok)
+	}
+	prev, pc, ok := ElemEdge(pcs[1], pcs, false, true)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), prev)
+	assert.Equal(t, pcs[1], pc)
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"sort"
+	"sync"
+)
+
+// HitCounts tracks how many corpus programs and how many executions hit each signal element.
+// It allows to favour inputs that exercise rarely reached code over inputs that
+// only re-hit common paths. Unlike Signal, HitCounts is safe for concurrent use.
+type HitCounts struct {
+	mu         sync.RWMutex
+	progs      map[elemType]uint32
+	execs      map[elemType]uint32
+	execsAdded int
+}
+
+type ElemHits struct {
+	Elem  uint64
+	Progs uint32 // number of corpus programs that hit the element
+	Execs uint32 // number of recently accounted executions that hit the element
+}
+
+// Execution counts are halved after this many accounted executions, so that they reflect
+// recent executions, can't overflow, and elements that are not hit anymore are eventually forgotten.
+const execsDecayPeriod = 10000
+
+func NewHitCounts() *HitCounts {
+	return &HitCounts{
+		progs: make(map[elemType]uint32),
+		execs: make(map[elemType]uint32),
+	}
+}
+
+// AddProg accounts signal of a new corpus program.
+func (hc *HitCounts) AddProg(s Signal) {
+	hc.UpdateProg(nil, s)
+}
+
+// UpdateProg accounts elements of the new program signal that were not present in the old signal.
+func (hc *HitCounts) UpdateProg(old, s Signal) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	for e := range s {
+		if _, ok := old[e]; ok {
+			continue
+		}
+		hc.progs[e]++
+	}
+}
+
+// ResetProgs forgets all accounted corpus programs (e.g. before corpus minimization),
+// execution counts are preserved.
+func (hc *HitCounts) ResetProgs() {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.progs = make(map[elemType]uint32)
+}
+
+// AddExec accounts signal of a single execution.
+// Note: executors normally return only signal that is new for them, so this
+// must be used only for executions that return all signal (e.g. triage runs).
+func (hc *HitCounts) AddExec(raw []uint64) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	for _, e := range raw {
+		hc.execs[elemType(e)]++
+	}
+	hc.execsAdded++
+	if hc.execsAdded < execsDecayPeriod {
+		return
+	}
+	hc.execsAdded = 0
+	for e, execs := range hc.execs {
+		if execs /= 2; execs == 0 {
+			delete(hc.execs, e)
+		} else {
+			hc.execs[e] = execs
+		}
+	}
+}
+
+// Rarity returns the rarity score of the signal: the sum of inverse numbers of corpus programs
+// that hit each element. Elements that are not hit by any program count as 1,
+// so the score of a signal that consists of unique elements is equal to its size.
+func (hc *HitCounts) Rarity(s Signal) float64 {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+	score := 0.0
+	for e := range s {
+		score += 1 / float64(max(1, hc.progs[e]))
+	}
+	return score
+}
+
+// Rarest returns up to n covered elements that are hit by the smallest number of corpus programs.
+func (hc *HitCounts) Rarest(n int) []ElemHits {
+	hc.mu.RLock()
+	res := make([]ElemHits, 0, len(hc.progs))
+	for e, progs := range hc.progs {
+		res = append(res, ElemHits{
+			Elem:  uint64(e),
+			Progs: progs,
+			Execs: hc.execs[e],
+		})
+	}
+	hc.mu.RUnlock()
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Progs != res[j].Progs {
+			return res[i].Progs < res[j].Progs
+		}
+		if res[i].Execs != res[j].Execs {
+			return res[i].Execs < res[j].Execs
+		}
+		return res[i].Elem < res[j].Elem
+	})
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
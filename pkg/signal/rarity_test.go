@@ -0,0 +1,45 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package signal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitCounts(t *testing.T) {
+	hc := NewHitCounts()
+	hc.AddProg(FromRaw([]uint64{1, 2, 3}, 1))
+	hc.AddProg(FromRaw([]uint64{1, 2}, 1))
+	hc.AddProg(FromRaw([]uint64{1}, 1))
+	// Elements that are already present in the old signal are not accounted twice.
+	hc.UpdateProg(FromRaw([]uint64{1}, 1), FromRaw([]uint64{1, 4}, 1))
+	hc.AddExec([]uint64{1, 2, 2, 4})
+
+	assert.InDelta(t, 1.0/3+1.0/2+1+1, hc.Rarity(FromRaw([]uint64{1, 2, 3, 5}, 1)), 1e-9)
+	assert.Equal(t, []ElemHits{
+		{Elem: 3, Progs: 1, Execs: 0},
+		{Elem: 4, Progs: 1, Execs: 1},
+		{Elem: 2, Progs: 2, Execs: 2},
+	}, hc.Rarest(3))
+
+	hc.ResetProgs()
+	assert.Equal(t, 2.0, hc.Rarity(FromRaw([]uint64{1, 2}, 1)))
+	assert.Empty(t, hc.Rarest(10))
+}
+
+func TestHitCountsExecsDecay(t *testing.T) {
+	hc := NewHitCounts()
+	hc.AddProg(FromRaw([]uint64{1, 2}, 1))
+	hc.AddExec([]uint64{2})
+	for i := 1; i < execsDecayPeriod; i++ {
+		hc.AddExec([]uint64{1})
+	}
+	assert.Equal(t, []ElemHits{
+		{Elem: 2, Progs: 1, Execs: 0},
+		{Elem: 1, Progs: 1, Execs: (execsDecayPeriod - 1) / 2},
+	}, hc.Rarest(2))
+	assert.Len(t, hc.execs, 1)
+}
@@ -20,6 +20,11 @@ func (s Signal) Empty() bool {
 	return len(s) == 0
 }
 
+func (s Signal) Contains(elem uint64) bool {
+	_, ok := s[elemType(elem)]
+	return ok
+}
+
 func (s Signal) Copy() Signal {
 	c := make(Signal, len(s))
 	for e, p := range s {
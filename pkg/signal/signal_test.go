@@ -16,3 +16,27 @@ func TestIntersectsWith(t *testing.T) {
 	// The other signal has a lower priority.
 	assert.False(t, base.IntersectsWith(FromRaw([]uint64{0, 1, 2}, 0)))
 }
+
+func TestDigest(t *testing.T) {
+	var raw []uint64
+	for i := uint64(0); i < 10000; i++ {
+		raw = append(raw, i*0x1000+0xffffffff81000000)
+	}
+	s := FromRaw(raw, 0)
+	digest := s.Digest()
+	assert.True(t, digest.ContainsAll(s.DigestHashes()))
+	assert.True(t, digest.ContainsAll(FromRaw(raw[100:200], 1).DigestHashes()))
+	assert.False(t, Digest(nil).ContainsAll(s.DigestHashes()))
+	assert.False(t, digest[:100].ContainsAll(s.DigestHashes()))
+	// Check the false positive rate.
+	falsePositives := 0
+	for i := uint64(0); i < 10000; i++ {
+		elem := FromRaw([]uint64{i*0x1000 + 0xffffffff91000000}, 0)
+		if digest.ContainsAll(elem.DigestHashes()) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 300)
+	// Programs with at least some new signal are not covered.
+	assert.False(t, digest.ContainsAll(FromRaw(append(raw[:10:10], 0x1, 0x2, 0x3), 0).DigestHashes()))
+}
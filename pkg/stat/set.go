@@ -41,6 +41,11 @@ func New(name, desc string, opts ...any) *Val {
 	return global.New(name, desc, opts...)
 }
 
+// Delete unregisters the metric, it's not collected nor shown on graphs anymore.
+func Delete(v *Val) {
+	global.Delete(v)
+}
+
 func Collect(level Level) []UI {
 	return global.Collect(level)
 }
@@ -239,6 +244,26 @@ func (s *set) New(name, desc string, opts ...any) *Val {
 	return v
 }
 
+func (s *set) Delete(v *Val) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vals[v.name] != v {
+		return
+	}
+	delete(s.vals, v.name)
+	graph := s.graphs[v.graph]
+	if graph == nil {
+		return
+	}
+	delete(graph.lines, v.name)
+	for _, v1 := range s.vals {
+		if v1.graph == v.graph {
+			return
+		}
+	}
+	delete(s.graphs, v.graph)
+}
+
 type Val struct {
 	name    string
 	desc    string
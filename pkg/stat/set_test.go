@@ -82,6 +82,33 @@ func TestSet(t *testing.T) {
 	set.RenderGraphs()
 }
 
+func TestSetDelete(t *testing.T) {
+	a := assert.New(t)
+	set := newSet(4, false)
+	v0 := set.New("v0", "desc0", Graph("graph"))
+	v1 := set.New("v1", "desc1", Graph("graph"))
+	set.tick()
+	a.Len(set.RenderGraphs(), 1)
+
+	set.Delete(v0)
+	ui := set.Collect(All)
+	a.Len(ui, 1)
+	a.Equal(ui[0].Name, "v1")
+	graphs := set.RenderGraphs()
+	a.Len(graphs, 1)
+	a.Len(graphs[0].Lines, 1)
+
+	set.Delete(v1)
+	a.Empty(set.Collect(All))
+	a.Empty(set.RenderGraphs())
+
+	// A new metric with the same name can be registered again.
+	v0 = set.New("v0", "desc0", Graph("graph"))
+	v0.Add(1)
+	set.tick()
+	a.Len(set.RenderGraphs(), 1)
+}
+
 func TestSetRateFormat(t *testing.T) {
 	a := assert.New(t)
 	set := newSet(4, false)
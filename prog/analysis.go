@@ -211,6 +211,7 @@ type RequiredFeatures struct {
 	Csums          bool
 	FaultInjection bool
 	Async          bool
+	CallSchedule   bool
 }
 
 func (p *Prog) RequiredFeatures() RequiredFeatures {
@@ -232,6 +233,9 @@ func (p *Prog) RequiredFeatures() RequiredFeatures {
 		if c.Props.Async {
 			features.Async = true
 		}
+		if c.Props.Delay > 0 || c.Props.CPU > 0 {
+			features.CallSchedule = true
+		}
 	}
 	return features
 }
@@ -59,6 +59,22 @@ func AssignRandomAsync(origProg *Prog, rand *rand.Rand) *Prog {
 	return prog
 }
 
+// CanBeAsync returns whether the call idx can be made async without breaking resource passing:
+// the call must not produce resources that are consumed by other calls (they may not be ready
+// by the time they are needed). Making the last call async is pointless, so it's never allowed.
+func CanBeAsync(p *Prog, idx int) bool {
+	if idx+1 >= len(p.Calls) {
+		return false
+	}
+	ok := true
+	ForeachArg(p.Calls[idx], func(arg Arg, ctx *ArgCtx) {
+		if res, isRes := arg.(*ResultArg); isRes && res.Dir() != DirIn && len(res.uses) != 0 {
+			ok = false
+		}
+	})
+	return ok
+}
+
 var rerunSteps = []int{32, 64}
 
 func AssignRandomRerun(prog *Prog, rand *rand.Rand) {
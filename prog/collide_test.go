@@ -85,6 +85,24 @@ r4 = dup(r3)
 	}
 }
 
+func TestCanBeAsync(t *testing.T) {
+	target, err := GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte(`r0 = openat(0xffffffffffffff9c, &AUTO='./file1\x00', 0x42, 0x1ff)
+r1 = dup(r0)
+write(r0, &AUTO="01010101", 0x4)
+close(r0)
+`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []bool{false, true, true, false}, []bool{
+		CanBeAsync(p, 0), CanBeAsync(p, 1), CanBeAsync(p, 2), CanBeAsync(p, 3),
+	})
+}
+
 func TestDoubleExecCollide(t *testing.T) {
 	tests := []struct {
 		os         string
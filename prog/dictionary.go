@@ -0,0 +1,53 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+// Dictionary contains values that are likely to be interesting for the fuzzed kernel,
+// e.g. string literals, magic constants and enum values extracted from the kernel binary.
+// Such values frequently gate deep code paths (filesystem superblock magics, netlink
+// attribute names, etc) and are very unlikely to be guessed randomly.
+// If passed in MutateOpts, the values are used during mutation of data and integer arguments.
+type Dictionary struct {
+	Strings [][]byte
+	Ints    []uint64
+}
+
+func (dict *Dictionary) empty() bool {
+	return dict == nil || len(dict.Strings) == 0 && len(dict.Ints) == 0
+}
+
+// dictToken returns a random dictionary value as a byte sequence,
+// integers are encoded with the minimal width that fits the value.
+func (r *randGen) dictToken() []byte {
+	dict := r.dict
+	if dict.empty() {
+		return nil
+	}
+	if idx := r.Intn(len(dict.Strings) + len(dict.Ints)); idx < len(dict.Strings) {
+		return dict.Strings[idx]
+	}
+	v := dict.Ints[r.Intn(len(dict.Ints))]
+	width := 1
+	for width < 8 && v>>(8*width) != 0 {
+		width *= 2
+	}
+	if r.oneOf(10) {
+		v = swapInt(v, width)
+	}
+	data := make([]byte, width)
+	storeInt(data, v, width)
+	return data
+}
+
+// dictInt returns a random dictionary integer that fits into bitSize bits.
+func (r *randGen) dictInt(bitSize uint64) (uint64, bool) {
+	if r.dict == nil || len(r.dict.Ints) == 0 {
+		return 0, false
+	}
+	v := r.dict.Ints[r.Intn(len(r.dict.Ints))]
+	if bitSize < 64 && v>>bitSize != 0 {
+		return 0, false
+	}
+	return v, true
+}
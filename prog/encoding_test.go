@@ -471,7 +471,7 @@ func TestSerializeCallProps(t *testing.T) {
 		},
 		{
 			"serialize0(0x0) (fail_nth: 5)\n",
-			[]CallProps{{5, false, 0}},
+			[]CallProps{{FailNth: 5}},
 		},
 		{
 			"serialize0(0x0) (fail_nth)\n",
@@ -483,11 +483,15 @@ func TestSerializeCallProps(t *testing.T) {
 		},
 		{
 			"serialize0(0x0) (async)\n",
-			[]CallProps{{0, true, 0}},
+			[]CallProps{{Async: true}},
 		},
 		{
 			"serialize0(0x0) (async, rerun: 10)\n",
-			[]CallProps{{0, true, 10}},
+			[]CallProps{{Async: true, Rerun: 10}},
+		},
+		{
+			"serialize0(0x0) (async, delay: 100, cpu: 2)\n",
+			[]CallProps{{Async: true, Delay: 100, CPU: 2}},
 		},
 	}
 
@@ -580,11 +580,11 @@ test() (fail_nth: 4)
 test() (async, rerun: 10)
 `,
 			[]any{
-				execInstrSetProps, 3, 0, 0,
+				execInstrSetProps, 3, 0, 0, 0, 0,
 				callID("test"), ExecNoCopyout, 0,
-				execInstrSetProps, 4, 0, 0,
+				execInstrSetProps, 4, 0, 0, 0, 0,
 				callID("test"), ExecNoCopyout, 0,
-				execInstrSetProps, 0, 1, 10,
+				execInstrSetProps, 0, 1, 10, 0, 0,
 				callID("test"), ExecNoCopyout, 0,
 				execInstrEOF,
 			},
@@ -593,17 +593,17 @@ test() (async, rerun: 10)
 					{
 						Meta:  target.SyscallMap["test"],
 						Index: ExecNoCopyout,
-						Props: CallProps{3, false, 0},
+						Props: CallProps{FailNth: 3},
 					},
 					{
 						Meta:  target.SyscallMap["test"],
 						Index: ExecNoCopyout,
-						Props: CallProps{4, false, 0},
+						Props: CallProps{FailNth: 4},
 					},
 					{
 						Meta:  target.SyscallMap["test"],
 						Index: ExecNoCopyout,
-						Props: CallProps{0, true, 10},
+						Props: CallProps{Async: true, Rerun: 10},
 					},
 				},
 			},
@@ -186,15 +186,8 @@ func checkConstArg(arg *ConstArg, field *Field, compMap CompMap, exec func() boo
 	original := arg.Val
 	// Note: because shrinkExpand returns a map, order of programs is non-deterministic.
 	// This can affect test coverage reports.
-replacerLoop:
 	for _, replacer := range shrinkExpand(original, compMap, arg.Type().TypeBitSize(), false) {
-		if field != nil && len(field.relatedFields) != 0 {
-			for related := range field.relatedFields {
-				if related.(uselessHinter).uselessHint(replacer) {
-					continue replacerLoop
-				}
-			}
-		} else if arg.Type().(uselessHinter).uselessHint(replacer) {
+		if uselessHint(arg, field, replacer) {
 			continue
 		}
 		arg.Val = replacer
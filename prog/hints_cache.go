@@ -0,0 +1,234 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// HintsCache aggregates comparison operands collected by hints jobs (see MutateWithHints)
+// per syscall and per syscall argument path (e.g. "a0.int4_0" for the int4_0 field
+// of the struct pointed to by the first argument).
+// Collecting comparisons is expensive (it requires several executions of the program),
+// while the operands are frequently useful for other programs that use the same syscall.
+// If passed in MutateOpts, the cached values are used during mutation of integer arguments.
+// The cache is bounded: only the last maxHintsCacheValues values are kept per argument path.
+// HintsCache is safe for concurrent use.
+type HintsCache struct {
+	mu      sync.RWMutex
+	calls   map[string]map[string]*hintsCacheEntry
+	values  int
+	version uint64
+}
+
+type hintsCacheEntry struct {
+	vals []uint64
+	pos  int // the next value to evict once vals is full
+}
+
+const maxHintsCacheValues = 32
+
+func NewHintsCache() *HintsCache {
+	return &HintsCache{
+		calls: make(map[string]map[string]*hintsCacheEntry),
+	}
+}
+
+// Add aggregates comparison operands that match integer arguments of p.Calls[callIndex].
+func (cache *HintsCache) Add(p *Prog, callIndex int, comps CompMap) {
+	c := p.Calls[callIndex]
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	foreachHintsCacheArg(c, func(arg *ConstArg, field *Field, path string) {
+		for _, v := range shrinkExpand(arg.Val, comps, arg.Type().TypeBitSize(), false) {
+			if uselessHint(arg, field, v) {
+				continue
+			}
+			cache.addLocked(c.Meta.Name, path, v)
+		}
+	})
+}
+
+func (cache *HintsCache) addLocked(call, path string, v uint64) {
+	paths := cache.calls[call]
+	if paths == nil {
+		paths = make(map[string]*hintsCacheEntry)
+		cache.calls[call] = paths
+	}
+	ent := paths[path]
+	if ent == nil {
+		ent = new(hintsCacheEntry)
+		paths[path] = ent
+	}
+	for _, v1 := range ent.vals {
+		if v1 == v {
+			return
+		}
+	}
+	cache.version++
+	if len(ent.vals) < maxHintsCacheValues {
+		ent.vals = append(ent.vals, v)
+		cache.values++
+		return
+	}
+	ent.vals[ent.pos] = v
+	ent.pos = (ent.pos + 1) % len(ent.vals)
+}
+
+// Len returns the total number of cached values.
+func (cache *HintsCache) Len() int {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	return cache.values
+}
+
+// Version returns a number that changes whenever new values are added to the cache.
+// It allows to avoid saving the cache if it did not change.
+func (cache *HintsCache) Version() uint64 {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	return cache.version
+}
+
+// Serialize returns the cache contents in JSON format: call name -> argument path -> values.
+func (cache *HintsCache) Serialize() []byte {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	res := make(map[string]map[string][]uint64, len(cache.calls))
+	for call, paths := range cache.calls {
+		res[call] = make(map[string][]uint64, len(paths))
+		for path, ent := range paths {
+			res[call][path] = ent.vals
+		}
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		panic(fmt.Sprintf("failed to serialize hints cache: %v", err))
+	}
+	return data
+}
+
+// Deserialize adds values serialized with Serialize to the cache.
+// Values for calls and argument paths that don't exist anymore are harmless:
+// they are never used for mutations.
+func (cache *HintsCache) Deserialize(data []byte) error {
+	var res map[string]map[string][]uint64
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("failed to deserialize hints cache: %w", err)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for call, paths := range res {
+		for path, vals := range paths {
+			for _, v := range vals {
+				cache.addLocked(call, path, v)
+			}
+		}
+	}
+	return nil
+}
+
+// mutate replaces a random integer argument of the call with a cached value for its path.
+func (cache *HintsCache) mutate(r *randGen, p *Prog, c *Call) bool {
+	type candidate struct {
+		arg   *ConstArg
+		field *Field
+		vals  []uint64
+	}
+	var candidates []candidate
+	cache.mu.RLock()
+	paths := cache.calls[c.Meta.Name]
+	if len(paths) != 0 {
+		foreachHintsCacheArg(c, func(arg *ConstArg, field *Field, path string) {
+			if ent := paths[path]; ent != nil {
+				candidates = append(candidates, candidate{arg, field, ent.vals})
+			}
+		})
+	}
+	var (
+		arg *ConstArg
+		val uint64
+	)
+	if len(candidates) != 0 {
+		cand := candidates[r.Intn(len(candidates))]
+		arg, val = cand.arg, cand.vals[r.Intn(len(cand.vals))]
+		if val == arg.Val || uselessHint(arg, cand.field, val) {
+			arg = nil
+		}
+	}
+	cache.mu.RUnlock()
+	if arg == nil {
+		return false
+	}
+	original := arg.Val
+	arg.Val = val
+	// Similarly to MutateWithHints, don't try to fix the resulting program.
+	if p.Target.sanitize(c, false) != nil || p.checkConditions() != nil {
+		arg.Val = original
+		return false
+	}
+	return true
+}
+
+func uselessHint(arg *ConstArg, field *Field, v uint64) bool {
+	if field != nil && len(field.relatedFields) != 0 {
+		for related := range field.relatedFields {
+			if related.(uselessHinter).uselessHint(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return arg.Type().(uselessHinter).uselessHint(v)
+}
+
+// foreachHintsCacheArg invokes the callback for all integer arguments of the call
+// that can be mutated with hints, path identifies the argument within the call.
+// All array elements share the same path.
+func foreachHintsCacheArg(c *Call, f func(arg *ConstArg, field *Field, path string)) {
+	var rec func(arg Arg, field *Field, path []string)
+	rec = func(arg Arg, field *Field, path []string) {
+		if arg.Dir() == DirOut {
+			return
+		}
+		switch a := arg.(type) {
+		case *ConstArg:
+			switch typ := a.Type().(type) {
+			case *ProcType, *CsumType:
+				return
+			case *ConstType:
+				if IsPad(typ) {
+					return
+				}
+			}
+			if a.Type().TypeBitSize() > 8 {
+				f(a, field, strings.Join(path, "."))
+			}
+		case *PointerArg:
+			if a.Res != nil {
+				rec(a.Res, nil, path)
+			}
+		case *UnionArg:
+			field := &a.Type().(*UnionType).Fields[a.Index]
+			rec(a.Option, field, append(path, field.Name))
+		case *GroupArg:
+			switch typ := a.Type().(type) {
+			case *StructType:
+				for i, inner := range a.Inner {
+					rec(inner, &typ.Fields[i], append(path, typ.Fields[i].Name))
+				}
+			case *ArrayType:
+				for _, inner := range a.Inner {
+					rec(inner, nil, append(path, "[]"))
+				}
+			}
+		}
+	}
+	for i, arg := range c.Args {
+		rec(arg, &c.Meta.Args[i], []string{c.Meta.Args[i].Name})
+	}
+}
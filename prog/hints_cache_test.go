@@ -0,0 +1,95 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHintsCache(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte(`test$hint_int(&AUTO={0x0, 0x1111, 0x1234, 0x12345678, 0x0})`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewHintsCache()
+	cache.Add(p, 0, CompMap{
+		0x1234:     compSet(0xabcd),
+		0x12345678: compSet(0xdead),
+	})
+	assert.Equal(t, map[string][]uint64{
+		"a0.int4_0": {0xabcd},
+		"a0.int8_0": {0xdead},
+	}, cacheValues(cache, "test$hint_int"))
+	version := cache.Version()
+
+	// Cached values survive serialization.
+	cache1 := NewHintsCache()
+	if err := cache1.Deserialize(cache.Serialize()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, cacheValues(cache, "test$hint_int"), cacheValues(cache1, "test$hint_int"))
+	assert.Equal(t, 2, cache1.Len())
+
+	// Useless hints (e.g. special ints that are generated anyway) are not cached.
+	cache.Add(p, 0, CompMap{0x1234: compSet(4096)})
+	assert.Equal(t, version, cache.Version())
+
+	// Values that are already present don't change the cache.
+	cache.Add(p, 0, CompMap{0x1234: compSet(0xabcd)})
+	assert.Equal(t, version, cache.Version())
+
+	// The number of values per argument path is bounded.
+	for i := 0; i < 2*maxHintsCacheValues; i++ {
+		cache.Add(p, 0, CompMap{0x1234: compSet(0x10000 + uint64(i))})
+	}
+	assert.Len(t, cacheValues(cache, "test$hint_int")["a0.int4_0"], maxHintsCacheValues)
+	assert.Equal(t, maxHintsCacheValues+1, cache.Len())
+}
+
+func TestMutateHintsCache(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte(`test$hint_int(&AUTO={0x0, 0x1111, 0x1234, 0x12345678, 0x0})`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewHintsCache()
+	cache.Add(p, 0, CompMap{0x1234: compSet(0xabcd)})
+	// A different program of the same syscall must be able to use the cached value.
+	p, err = target.Deserialize([]byte(`test$hint_int(&AUTO={0x0, 0x0, 0x5555, 0x0, 0x0})`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultMutateOpts
+	opts.HintsCache = cache
+	rs := testutil.RandSource(t)
+	ct := target.DefaultChoiceTable()
+	for i := 0; i < 10000; i++ {
+		p1 := p.Clone()
+		p1.MutateWithOpts(rs, len(p.Calls), ct, nil, nil, opts)
+		found := false
+		ForeachArg(p1.Calls[0], func(arg Arg, _ *ArgCtx) {
+			if a, ok := arg.(*ConstArg); ok && a.Val == 0xabcd {
+				found = true
+			}
+		})
+		if found {
+			return
+		}
+	}
+	t.Fatalf("cached hint is not used")
+}
+
+func cacheValues(cache *HintsCache, call string) map[string][]uint64 {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	res := make(map[string][]uint64)
+	for path, ent := range cache.calls[call] {
+		res[path] = append([]uint64{}, ent.vals...)
+	}
+	return res
+}
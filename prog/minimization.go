@@ -281,6 +281,16 @@ func minimizeCallProps(p0 *Prog, callIndex, callIndex0 int, pred minimizePred) *
 		}
 	}
 
+	// Try to drop the call schedule.
+	if props.Delay > 0 || props.CPU > 0 {
+		p := p0.Clone()
+		p.Calls[callIndex].Props.Delay = 0
+		p.Calls[callIndex].Props.CPU = 0
+		if pred(p, callIndex0, statMinRemoveProps, "props") {
+			p0 = p
+		}
+	}
+
 	return p0
 }
 
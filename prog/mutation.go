@@ -9,6 +9,7 @@ import (
 	"math"
 	"math/rand"
 	"sort"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/image"
 )
@@ -47,10 +48,18 @@ type MutateOpts struct {
 	InsertWeight       int
 	MutateArgWeight    int
 	RemoveCallWeight   int
+	// Crossover is experimental, so it's not enabled in DefaultMutateOpts
+	// and is used only if explicitly requested (e.g. by the bandit scheduling policy).
+	CrossoverWeight int
+	// Dictionary is an optional set of interesting values for data and integer mutations.
+	Dictionary *Dictionary
+	// HintsCache is an optional cache of comparison operands for integer mutations.
+	HintsCache *HintsCache
 }
 
 func (o MutateOpts) weight() int {
-	return o.SquashWeight + o.SpliceWeight + o.InsertWeight + o.MutateArgWeight + o.RemoveCallWeight
+	return o.SquashWeight + o.SpliceWeight + o.InsertWeight + o.MutateArgWeight + o.RemoveCallWeight +
+		o.CrossoverWeight
 }
 
 func (p *Prog) MutateWithOpts(rs rand.Source, ncalls int, ct *ChoiceTable, noMutate map[int]bool,
@@ -60,6 +69,7 @@ func (p *Prog) MutateWithOpts(rs rand.Source, ncalls int, ct *ChoiceTable, noMut
 	}
 	totalWeight := opts.weight()
 	r := newRand(p.Target, rs)
+	r.dict = opts.Dictionary
 	ncalls = max(ncalls, len(p.Calls))
 	ctx := &mutator{
 		p:        p,
@@ -94,6 +104,11 @@ func (p *Prog) MutateWithOpts(rs rand.Source, ncalls int, ct *ChoiceTable, noMut
 			ok = ctx.mutateArg()
 			continue
 		}
+		val -= opts.CrossoverWeight
+		if val < 0 {
+			ok = ctx.crossover()
+			continue
+		}
 		ok = ctx.removeCall()
 	}
 	p.sanitizeFix()
@@ -133,6 +148,152 @@ func (ctx *mutator) splice() bool {
 	return true
 }
 
+// crossover replaces a random compound argument (struct, union or array) of ctx.p
+// with an argument of the same type at the same path (see foreachCrossoverArg)
+// taken from a random program p0 out of the corpus.
+// Unlike splice, this allows to combine programs below the call level
+// (e.g. take a sockaddr or a set of netlink attributes from another program).
+// Resources referenced by the donated argument are re-linked to compatible
+// resources in ctx.p, or replaced with special values if there are none.
+func (ctx *mutator) crossover() bool {
+	p, r := ctx.p, ctx.r
+	if len(ctx.corpus) == 0 || len(p.Calls) == 0 {
+		return false
+	}
+	// We are going to take arguments from p0, so we need to work on a copy.
+	p0 := ctx.corpus[r.Intn(len(ctx.corpus))].Clone()
+	donors := make(map[crossoverKey][]Arg)
+	for _, c := range p0.Calls {
+		foreachCrossoverArg(c, func(arg Arg, _ *PointerArg, key crossoverKey) {
+			donors[key] = append(donors[key], arg)
+		})
+	}
+	type crossoverArg struct {
+		call *Call
+		arg  Arg
+		base *PointerArg
+		key  crossoverKey
+	}
+	var args []crossoverArg
+	for _, c := range p.Calls {
+		if ctx.noMutate[c.Meta.ID] {
+			continue
+		}
+		foreachCrossoverArg(c, func(arg Arg, base *PointerArg, key crossoverKey) {
+			if len(donors[key]) != 0 {
+				args = append(args, crossoverArg{c, arg, base, key})
+			}
+		})
+	}
+	if len(args) == 0 {
+		return false
+	}
+	dst := args[r.Intn(len(args))]
+	src := donors[dst.key][r.Intn(len(donors[dst.key]))]
+	// Note: we need to call analyze before we replace the argument,
+	// since it may not fit into the existing allocation anymore.
+	s := analyze(ctx.ct, ctx.corpus, p, dst.call)
+	ForeachSubArg(src, func(arg Arg, _ *ArgCtx) {
+		a, ok := arg.(*ResultArg)
+		if !ok {
+			return
+		}
+		// Uses of the donated resources refer to p0 and are lost.
+		a.uses = nil
+		if a.Res == nil {
+			return
+		}
+		var res *ResultArg
+		if existing := r.existingResource(s, a.Type().(*ResourceType), a.Dir()); existing != nil {
+			res = existing.(*ResultArg)
+		} else {
+			res = a.Type().DefaultArg(a.Dir()).(*ResultArg)
+		}
+		replaceResultArg(a, res)
+	})
+	var baseSize uint64
+	if dst.base != nil {
+		baseSize = dst.base.Res.Size()
+	}
+	// replaceArg does not unlink resources in the replaced pointees,
+	// so we unlink the whole old argument in advance.
+	removeArg(dst.arg)
+	ForeachSubArg(dst.arg, func(arg Arg, _ *ArgCtx) {
+		if a, ok := arg.(*ResultArg); ok {
+			a.Res = nil
+		}
+	})
+	replaceArg(dst.arg, src)
+	// Update base pointer if size has increased.
+	if base := dst.base; base != nil && baseSize < base.Res.Size() {
+		newArg := r.allocAddr(s, base.Type(), base.Dir(), base.Res.Size(), base.Res)
+		replaceArg(base, newArg)
+	}
+	// The donated argument may not satisfy conditions of the new parent.
+	calls, _ := r.patchConditionalFields(dst.call, s)
+	idx := len(p.Calls)
+	for i, c := range p.Calls {
+		if c == dst.call {
+			idx = i
+			break
+		}
+	}
+	p.insertBefore(dst.call, calls)
+	for len(p.Calls) > ctx.ncalls {
+		p.RemoveCall(idx)
+	}
+	p.Target.assignSizesCall(dst.call)
+	return true
+}
+
+// Compound arguments of the same type may have very different meaning depending on where
+// they are used (e.g. the same struct may be used for both the source and the destination),
+// so arguments are interchangeable only if they are reached via the same path.
+type crossoverKey struct {
+	typ  Type
+	dir  Dir
+	path string
+}
+
+// foreachCrossoverArg invokes the callback for all compound (struct, union and array) input arguments
+// of the call. The path in the key consists of the syscall argument name and names of all fields
+// the argument is reached through (similarly to foreachHintsCacheArg), so the same struct
+// in bind(addr) and connect(addr) matches, but the struct in different fields does not.
+// base is the pointer to the heap object containing the argument, if any.
+func foreachCrossoverArg(c *Call, f func(arg Arg, base *PointerArg, key crossoverKey)) {
+	var rec func(arg Arg, base *PointerArg, path []string)
+	rec = func(arg Arg, base *PointerArg, path []string) {
+		switch arg.Type().(type) {
+		case *StructType, *UnionType, *ArrayType:
+			if arg.Dir() != DirOut {
+				f(arg, base, crossoverKey{arg.Type(), arg.Dir(), strings.Join(path, ".")})
+			}
+		}
+		switch a := arg.(type) {
+		case *PointerArg:
+			if a.Res != nil {
+				rec(a.Res, a, path)
+			}
+		case *UnionArg:
+			rec(a.Option, base, append(path, a.Type().(*UnionType).Fields[a.Index].Name))
+		case *GroupArg:
+			switch typ := a.Type().(type) {
+			case *StructType:
+				for i, inner := range a.Inner {
+					rec(inner, base, append(path, typ.Fields[i].Name))
+				}
+			case *ArrayType:
+				for _, inner := range a.Inner {
+					rec(inner, base, append(path, "[]"))
+				}
+			}
+		}
+	}
+	for i, arg := range c.Args {
+		rec(arg, nil, []string{c.Meta.Args[i].Name})
+	}
+}
+
 // Picks a random complex pointer and squashes its arguments into an ANY.
 // Subsequently, if the ANY contains blobs, mutates a random blob.
 func (ctx *mutator) squashAny() bool {
@@ -227,6 +388,9 @@ func (ctx *mutator) mutateArg() bool {
 	if ctx.noMutate[c.Meta.ID] {
 		return false
 	}
+	if ctx.opts.HintsCache != nil && r.oneOf(4) && ctx.opts.HintsCache.mutate(r, p, c) {
+		return true
+	}
 	updateSizes := true
 	for stop, ok := false, false; !stop; stop = ok && r.oneOf(ctx.opts.MutateArgCount) {
 		ok = true
@@ -345,6 +509,12 @@ func (t *IntType) mutate(r *randGen, s *state, arg Arg, ctx ArgCtx) (calls []*Ca
 		return regenerate(r, s, arg)
 	}
 	a := arg.(*ConstArg)
+	if t.Kind == IntPlain && r.dict != nil && len(r.dict.Ints) != 0 && r.oneOf(4) {
+		if v, ok := r.dictInt(t.TypeBitSize()); ok {
+			a.Val = v
+			return
+		}
+	}
 	if t.Align == 0 {
 		a.Val = mutateInt(r, a, t)
 	} else {
@@ -865,6 +1035,27 @@ var mutateDataFuncs = [...]func(r *randGen, data []byte, minLen, maxLen uint64)
 		storeInt(data[i:], value, width)
 		return data, true
 	},
+	// Insert or overwrite a dictionary value.
+	func(r *randGen, data []byte, minLen, maxLen uint64) ([]byte, bool) {
+		token := r.dictToken()
+		if len(token) == 0 || uint64(len(token)) > maxLen {
+			return data, false
+		}
+		pos := 0
+		if len(data) != 0 {
+			pos = r.Intn(len(data) + 1)
+		}
+		if r.bin() && uint64(len(data)+len(token)) <= maxLen {
+			data = append(data[:pos], append(append([]byte{}, token...), data[pos:]...)...)
+		} else {
+			pos = min(pos, int(maxLen)-len(token))
+			for len(data) < pos+len(token) {
+				data = append(data, 0)
+			}
+			copy(data[pos:], token)
+		}
+		return data, true
+	},
 }
 
 func swap16(v uint16) uint16 {
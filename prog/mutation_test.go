@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/google/syzkaller/pkg/testutil"
@@ -256,6 +257,131 @@ func TestMutateCorpus(t *testing.T) {
 	}
 }
 
+func TestMutateCrossover(t *testing.T) {
+	testEachTargetRandom(t, func(t *testing.T, target *Target, rs rand.Source, iters int) {
+		ct := target.DefaultChoiceTable()
+		var corpus [][]byte
+		var corpusProgs []*Prog
+		for i := 0; i < 20; i++ {
+			p := target.Generate(rs, 10, ct)
+			corpus = append(corpus, p.Serialize())
+			corpusProgs = append(corpusProgs, p)
+		}
+		// Not all programs have compound arguments, so we can't use crossover alone.
+		opts := DefaultMutateOpts
+		opts.CrossoverWeight = 1000
+		for i := 0; i < iters; i++ {
+			p := target.Generate(rs, 10, ct)
+			p.MutateWithOpts(rs, 10, ct, nil, corpusProgs, opts)
+			if err := p.validate(); err != nil {
+				t.Fatalf("invalid program after crossover: %v\n%s", err, p.Serialize())
+			}
+		}
+		// Corpus programs must not be affected.
+		for i, p := range corpusProgs {
+			if err := p.validate(); err != nil {
+				t.Fatalf("corpus program is corrupted: %v", err)
+			}
+			if data := p.Serialize(); !bytes.Equal(corpus[i], data) {
+				t.Fatalf("corpus program changed after crossover\noriginal:\n%s\nnew:\n%s",
+					corpus[i], data)
+			}
+		}
+	})
+}
+
+func TestMutateCrossoverArray(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte("mutate3(&(0x7f0000000000)=[0x0], 0x1)\n"), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	donor, err := target.Deserialize([]byte("mutate3(&(0x7f0000000000)=[0x1, 0x0, 0x1], 0x3)\n"), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := testutil.RandSource(t)
+	ct := target.DefaultChoiceTable()
+	p.MutateWithOpts(rs, 1, ct, nil, []*Prog{donor}, MutateOpts{
+		ExpectedIterations: 1,
+		CrossoverWeight:    1,
+	})
+	// The array is taken from the donor and the length is updated.
+	// The pointer may be re-allocated since the array has grown.
+	if data := string(p.Serialize()); !strings.HasSuffix(data, "=[0x1, 0x0, 0x1], 0x3)\n") {
+		t.Fatalf("unexpected program after crossover:\n%s", data)
+	}
+}
+
+func TestMutateCrossoverPath(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	const orig = "test$recur2(&(0x7f0000000000)={0x0, 0x0, &(0x7f0000000100)={0x0, 0x0, 0x0, 0x0}, 0x0, 0x0, 0x0})\n"
+	// The donor has syz_recur_2_0 structs only in the a3 field, so they must not be used for the a2 field.
+	const donor = "test$recur2(&(0x7f0000000000)={0x0, 0x0, 0x0, &(0x7f0000000100)={0x0, 0x0, 0x0, " +
+		"&(0x7f0000000200)={0x0, 0x0, 0x0, 0x0}}, 0x0, 0x0})\n"
+	donorProg, err := target.Deserialize([]byte(donor), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := testutil.RandSource(t)
+	ct := target.DefaultChoiceTable()
+	for i := 0; i < 100; i++ {
+		p, err := target.Deserialize([]byte(orig), Strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.MutateWithOpts(rs, 1, ct, nil, []*Prog{donorProg}, MutateOpts{
+			ExpectedIterations: 1,
+			CrossoverWeight:    1,
+		})
+		// The only argument with matching path is the whole struct.
+		if data := p.Serialize(); !bytes.Equal(data, donorProg.Serialize()) {
+			t.Fatalf("unexpected program after crossover:\n%s", data)
+		}
+	}
+}
+
+func TestMutateDictionary(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte(`
+mutate4(&(0x7f0000000000)="11223344", 0x4)
+mutate_integer2(0x0, 0x0, 0x0, 0x0, 0x0)
+`), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict := &Dictionary{
+		Strings: [][]byte{[]byte("ReIsEr2Fs")},
+		Ints:    []uint64{0xcafef00d},
+	}
+	opts := DefaultMutateOpts
+	opts.Dictionary = dict
+	rs := testutil.RandSource(t)
+	ct := target.DefaultChoiceTable()
+	foundString, foundInt := false, false
+	for i := 0; i < 10000 && !(foundString && foundInt); i++ {
+		p1 := p.Clone()
+		p1.MutateWithOpts(rs, len(p.Calls), ct, nil, nil, opts)
+		for _, c := range p1.Calls {
+			ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+				switch a := arg.(type) {
+				case *DataArg:
+					if a.Dir() != DirOut && bytes.Contains(a.Data(), dict.Strings[0]) {
+						foundString = true
+					}
+				case *ConstArg:
+					if a.Val == dict.Ints[0] {
+						foundInt = true
+					}
+				}
+			})
+		}
+	}
+	if !foundString || !foundInt {
+		t.Fatalf("dictionary values are not used: string=%v int=%v", foundString, foundInt)
+	}
+}
+
 func TestMutateTable(t *testing.T) {
 	tests := [][2]string{
 		// Insert a call.
@@ -0,0 +1,190 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// CallModel is an n-gram model of syscall sequences. It counts how often a call follows
+// the preceding call (bigrams) and the preceding pair of calls (trigrams) in the programs
+// it was trained on. ChoiceTable uses the model to choose the next call conditioned
+// on the previous calls (see ChoiceTable.WithCallModel).
+// CallModel can be trained and used concurrently.
+type CallModel struct {
+	target *Target
+	mu     sync.RWMutex
+	counts map[ngramContext]map[int]uint32
+	progs  int
+}
+
+// ngramContext identifies the calls preceding the predicted call.
+// For bigrams prev2 is -1.
+type ngramContext struct {
+	prev2 int
+	prev1 int
+}
+
+// Contexts with fewer observations are not used to choose calls since they are too noisy.
+const ngramMinCount = 3
+
+func NewCallModel(target *Target) *CallModel {
+	return &CallModel{
+		target: target,
+		counts: make(map[ngramContext]map[int]uint32),
+	}
+}
+
+// Train accounts call sequences of the program in the model.
+func (m *CallModel) Train(p *Prog) {
+	if p.Target != m.target {
+		panic("training call model on a program for a different target")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progs++
+	for i := 1; i < len(p.Calls); i++ {
+		next := p.Calls[i].Meta.ID
+		m.add(ngramContext{-1, p.Calls[i-1].Meta.ID}, next)
+		if i >= 2 {
+			m.add(ngramContext{p.Calls[i-2].Meta.ID, p.Calls[i-1].Meta.ID}, next)
+		}
+	}
+}
+
+func (m *CallModel) add(ctx ngramContext, next int) {
+	nexts := m.counts[ctx]
+	if nexts == nil {
+		nexts = make(map[int]uint32)
+		m.counts[ctx] = nexts
+	}
+	nexts[next]++
+}
+
+// Len returns the number of programs the model was trained on.
+func (m *CallModel) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.progs
+}
+
+// CallContext describes the calls observed after the Prev calls.
+type CallContext struct {
+	Prev  []*Syscall
+	Total uint32
+	Next  []NextCall
+}
+
+type NextCall struct {
+	Call  *Syscall
+	Count uint32
+}
+
+// Contexts returns all contexts known to the model sorted by the number of observations.
+// Next calls in each context are sorted by the number of observations as well.
+func (m *CallModel) Contexts() []CallContext {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []CallContext
+	for ctx, nexts := range m.counts {
+		cc := CallContext{}
+		if ctx.prev2 != -1 {
+			cc.Prev = append(cc.Prev, m.target.Syscalls[ctx.prev2])
+		}
+		cc.Prev = append(cc.Prev, m.target.Syscalls[ctx.prev1])
+		for id, count := range nexts {
+			cc.Total += count
+			cc.Next = append(cc.Next, NextCall{m.target.Syscalls[id], count})
+		}
+		sort.Slice(cc.Next, func(i, j int) bool {
+			if cc.Next[i].Count != cc.Next[j].Count {
+				return cc.Next[i].Count > cc.Next[j].Count
+			}
+			return cc.Next[i].Call.ID < cc.Next[j].Call.ID
+		})
+		res = append(res, cc)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Total != res[j].Total {
+			return res[i].Total > res[j].Total
+		}
+		if len(res[i].Prev) != len(res[j].Prev) {
+			return len(res[i].Prev) < len(res[j].Prev)
+		}
+		for k := range res[i].Prev {
+			if res[i].Prev[k] != res[j].Prev[k] {
+				return res[i].Prev[k].ID < res[j].Prev[k].ID
+			}
+		}
+		return false
+	})
+	return res
+}
+
+// ngramRun is a snapshot of the model for one context restricted to the generatable calls.
+// sums contains cumulated counts similarly to ChoiceTable.runs.
+type ngramRun struct {
+	calls []int
+	sums  []uint32
+}
+
+func (run *ngramRun) choose(r *rand.Rand) int {
+	x := uint32(r.Int63n(int64(run.sums[len(run.sums)-1]))) + 1
+	return run.calls[sort.Search(len(run.sums), func(i int) bool {
+		return run.sums[i] >= x
+	})]
+}
+
+// WithCallModel returns a copy of the choice table that uses a snapshot of the model
+// to choose calls conditioned on the preceding calls in the program.
+// Later training of the model does not affect the returned table.
+func (ct *ChoiceTable) WithCallModel(m *CallModel) *ChoiceTable {
+	if m.target != ct.target {
+		panic("call model for a different target")
+	}
+	res := *ct
+	res.ngrams = make(map[ngramContext]*ngramRun)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for ctx, nexts := range m.counts {
+		var ids []int
+		for id := range nexts {
+			if ct.Generatable(id) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+		run := &ngramRun{}
+		var sum uint32
+		for _, id := range ids {
+			sum += nexts[id]
+			run.calls = append(run.calls, id)
+			run.sums = append(run.sums, sum)
+		}
+		if sum >= ngramMinCount {
+			res.ngrams[ctx] = run
+		}
+	}
+	return &res
+}
+
+// chooseNext chooses a call to insert after the prev calls.
+// If the table has a call model, half of the time the choice is based on the model.
+// Otherwise the choice is biased towards the bias call as in choose.
+func (ct *ChoiceTable) chooseNext(r *rand.Rand, bias int, prev []*Call) int {
+	if len(ct.ngrams) != 0 && len(prev) != 0 && r.Intn(2) == 0 {
+		prev1 := prev[len(prev)-1].Meta.ID
+		if len(prev) >= 2 {
+			if run := ct.ngrams[ngramContext{prev[len(prev)-2].Meta.ID, prev1}]; run != nil {
+				return run.choose(r)
+			}
+		}
+		if run := ct.ngrams[ngramContext{-1, prev1}]; run != nil {
+			return run.choose(r)
+		}
+	}
+	return ct.choose(r, bias)
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallModel(t *testing.T) {
+	target, rs, _ := initTest(t)
+	m := NewCallModel(target)
+	progs := []string{
+		"r0 = open(&(0x7f0000000000)='./file0\\x00', 0x0, 0x0)\nread(r0, 0x0, 0x0)\nclose(r0)\n",
+		"r0 = open(&(0x7f0000000000)='./file0\\x00', 0x0, 0x0)\nread(r0, 0x0, 0x0)\nclose(r0)\n",
+		"r0 = open(&(0x7f0000000000)='./file0\\x00', 0x0, 0x0)\nread(r0, 0x0, 0x0)\nclose(r0)\n",
+		"r0 = open(&(0x7f0000000000)='./file0\\x00', 0x0, 0x0)\nwrite(r0, 0x0, 0x0)\n",
+	}
+	for _, text := range progs {
+		p, err := target.Deserialize([]byte(text), Strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Train(p)
+	}
+	assert.Equal(t, len(progs), m.Len())
+	calls := func(names ...string) []*Syscall {
+		var res []*Syscall
+		for _, name := range names {
+			res = append(res, target.SyscallMap[name])
+		}
+		return res
+	}
+	ctxs := m.Contexts()
+	assert.Len(t, ctxs, 3)
+	assert.Equal(t, calls("open"), ctxs[0].Prev)
+	assert.Equal(t, uint32(4), ctxs[0].Total)
+	assert.Equal(t, []NextCall{
+		{target.SyscallMap["read"], 3},
+		{target.SyscallMap["write"], 1},
+	}, ctxs[0].Next)
+	assert.Equal(t, calls("read"), ctxs[1].Prev)
+	assert.Equal(t, calls("open", "read"), ctxs[2].Prev)
+
+	ct := target.DefaultChoiceTable().WithCallModel(m)
+	// "read"->"close" and "open","read"->"close" contexts have enough observations,
+	// so the model must strongly prefer "close" after "open","read".
+	prev := []*Call{MakeCall(target.SyscallMap["open"], nil), MakeCall(target.SyscallMap["read"], nil)}
+	r := rand.New(rs)
+	closeID := target.SyscallMap["close"].ID
+	chosen := 0
+	const iters = 1000
+	for i := 0; i < iters; i++ {
+		if ct.chooseNext(r, prev[1].Meta.ID, prev) == closeID {
+			chosen++
+		}
+	}
+	if chosen < iters/3 {
+		t.Fatalf("close was chosen %v times out of %v", chosen, iters)
+	}
+	// Without the model the choice shouldn't be affected.
+	ct = target.DefaultChoiceTable()
+	assert.Empty(t, ct.ngrams)
+}
+
+func TestCallModelGeneratable(t *testing.T) {
+	target, rs, _ := initTest(t)
+	m := NewCallModel(target)
+	p, err := target.Deserialize([]byte("r0 = open(&(0x7f0000000000)='./file0\\x00', 0x0, 0x0)\n"+
+		"read(r0, 0x0, 0x0)\nread(r0, 0x0, 0x0)\nread(r0, 0x0, 0x0)\nread(r0, 0x0, 0x0)\n"), Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Train(p)
+	// The model must never choose calls that are not enabled in the table.
+	enabled := map[*Syscall]bool{
+		target.SyscallMap["open"]:  true,
+		target.SyscallMap["close"]: true,
+	}
+	ct := target.BuildChoiceTable(nil, enabled).WithCallModel(m)
+	assert.Empty(t, ct.ngrams)
+	r := rand.New(rs)
+	for i := 0; i < 100; i++ {
+		assert.True(t, ct.Generatable(ct.chooseNext(r, -1, p.Calls)))
+	}
+}
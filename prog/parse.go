@@ -15,6 +15,9 @@ type LogEntry struct {
 	ID    int // ID of the executed program (-1 if not present)
 	Start int // start offset in log
 	End   int // end offset in log
+	// The program changed persistent machine state (see Prog.StateChanges)
+	// and may be executed long before the other programs in the log.
+	State bool
 }
 
 func (target *Target) ParseLog(data []byte, mode DeserializeMode) []*LogEntry {
@@ -35,7 +38,13 @@ func (target *Target) ParseLog(data []byte, mode DeserializeMode) []*LogEntry {
 		pos0 := pos
 		pos = nl + 1
 
-		if proc, ok := extractInt(line, "executing program "); ok {
+		proc, ok := extractInt(line, "executing program ")
+		state := false
+		if !ok {
+			proc, ok = extractInt(line, "executing state program ")
+			state = ok
+		}
+		if ok {
 			if ent.P != nil && len(ent.P.Calls) != 0 {
 				ent.End = pos0
 				entries = append(entries, ent)
@@ -45,6 +54,7 @@ func (target *Target) ParseLog(data []byte, mode DeserializeMode) []*LogEntry {
 				Proc:  proc,
 				Start: pos0,
 				ID:    -1,
+				State: state,
 			}
 			if id, ok := extractInt(line, "id="); ok {
 				ent.ID = id
@@ -186,3 +186,26 @@ getpid()
 		t.Fatalf("bad program: %s, want %s", got, want)
 	}
 }
+
+func TestParseState(t *testing.T) {
+	t.Parallel()
+	target, err := GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const execLog = `last executing test programs:
+
+1m10.5s ago: executing state program 3 (id=12, state=sysctl):
+getpid()
+10.5s ago: executing program 1 (id=70):
+gettid()
+`
+	entries := target.ParseLog([]byte(execLog), NonStrict)
+	assert.Len(t, entries, 2)
+	assert.True(t, entries[0].State)
+	assert.Equal(t, 3, entries[0].Proc)
+	assert.Equal(t, 12, entries[0].ID)
+	assert.Equal(t, "getpid", entries[0].P.String())
+	assert.False(t, entries[1].State)
+	assert.Equal(t, 70, entries[1].ID)
+}
@@ -59,8 +59,18 @@ type CallProps struct {
 	FailNth int  `key:"fail_nth"`
 	Async   bool `key:"async"`
 	Rerun   int  `key:"rerun"`
+	// Delay (in microseconds) before the call is started in its thread.
+	Delay int `key:"delay"`
+	// CPU the thread executing the call is pinned to (1-based, 0 means no pinning).
+	CPU int `key:"cpu"`
 }
 
+// Limits for the call scheduling props (CallProps.Delay and CallProps.CPU).
+const (
+	MaxCallDelay = 1000000
+	MaxCallCPU   = 64
+)
+
 type Call struct {
 	Meta    *Syscall
 	Args    []Arg
@@ -29,6 +29,7 @@ type randGen struct {
 	inGenerateResource    bool
 	patchConditionalDepth int
 	recDepth              map[string]int
+	dict                  *Dictionary
 }
 
 func newRand(target *Target, rs rand.Source) *randGen {
@@ -596,7 +597,7 @@ func (r *randGen) generateCall(s *state, p *Prog, insertionPoint int) []*Call {
 			biasCall = insertionCall.ID
 		}
 	}
-	idx := s.ct.choose(r.Rand, biasCall)
+	idx := s.ct.chooseNext(r.Rand, biasCall, p.Calls[:insertionPoint])
 	meta := r.target.Syscalls[idx]
 	return r.generateParticularCall(s, meta)
 }
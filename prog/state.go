@@ -0,0 +1,69 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"strings"
+)
+
+// StateKind is a set of kinds of persistent machine state a program may change.
+// Such changes outlive the program and may affect execution of all subsequent programs,
+// so crashes may depend on programs executed long before the crash.
+type StateKind int
+
+const (
+	// The program injects faults (see CallProps.FailNth).
+	StateFaultInjection StateKind = 1 << iota
+	// The program mounts filesystems. The executor unmounts everything in the program
+	// working dir, but mount points can be reached via symlinks in mounted images,
+	// and the filesystems may be left in a changed state.
+	StateMount
+	// The program changes sysctls.
+	StateSysctl
+)
+
+var stateKindNames = []string{"fault", "mount", "sysctl"}
+
+func (kind StateKind) String() string {
+	var names []string
+	for i, name := range stateKindNames {
+		if kind&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// StateChanges returns kinds of persistent machine state the program may change.
+func (p *Prog) StateChanges() StateKind {
+	var kind StateKind
+	for _, c := range p.Calls {
+		if c.Props.FailNth > 0 {
+			kind |= StateFaultInjection
+		}
+		switch c.Meta.CallName {
+		case "mount", "nmount", "fsmount", "move_mount":
+			kind |= StateMount
+		case "sysctl", "__sysctl":
+			kind |= StateSysctl
+		}
+		ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+			a, ok := arg.(*DataArg)
+			if !ok || a.Dir() == DirOut {
+				return
+			}
+			switch a.Type().(*BufferType).Kind {
+			case BufferCompressed:
+				// Filesystem images are mounted by syz_mount_image.
+				kind |= StateMount
+			case BufferFilename, BufferString:
+				if bytes.HasPrefix(a.Data(), []byte("/proc/sys/")) {
+					kind |= StateSysctl
+				}
+			}
+		})
+	}
+	return kind
+}
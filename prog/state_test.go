@@ -0,0 +1,56 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateChanges(t *testing.T) {
+	target, err := GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		prog  string
+		state StateKind
+	}{
+		{
+			`getpid()`,
+			0,
+		},
+		{
+			`getpid() (fail_nth: 3)`,
+			StateFaultInjection,
+		},
+		{
+			`r0 = openat$sysctl(0xffffffffffffff9c, &AUTO='/proc/sys/net/ipv4/tcp_timestamps\x00', 0x1, 0x0)
+write$sysctl(r0, &AUTO='0\x00', 0x2)
+`,
+			StateSysctl,
+		},
+		{
+			`openat(0xffffffffffffff9c, &AUTO='./file0\x00', 0x0, 0x0)`,
+			0,
+		},
+		{
+			`mount(&AUTO='./file0\x00', &AUTO='./file1\x00', &AUTO='tmpfs\x00', 0x0, 0x0) (fail_nth: 1)`,
+			StateMount | StateFaultInjection,
+		},
+		{
+			`syz_mount_image$tmpfs(&AUTO='tmpfs\x00', &AUTO='./file0\x00', 0x0, &AUTO={}, 0x1, 0x0, &AUTO="")`,
+			StateMount,
+		},
+	}
+	for _, test := range tests {
+		p, err := target.Deserialize([]byte(test.prog), NonStrict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, test.state, p.StateChanges(), test.prog)
+	}
+	assert.Equal(t, "fault,sysctl", (StateFaultInjection | StateSysctl).String())
+}
@@ -73,6 +73,12 @@ func (ctx *validCtx) validateCall(c *Call) error {
 	if c.Props.Rerun > 0 && c.Props.FailNth > 0 {
 		return fmt.Errorf("rerun > 0 && fail_nth > 0")
 	}
+	if c.Props.Delay < 0 || c.Props.Delay > MaxCallDelay {
+		return fmt.Errorf("bad delay %v", c.Props.Delay)
+	}
+	if c.Props.CPU < 0 || c.Props.CPU > MaxCallCPU {
+		return fmt.Errorf("bad cpu %v", c.Props.CPU)
+	}
 	if len(c.Args) != len(c.Meta.Args) {
 		return fmt.Errorf("wrong number of arguments, want %v, got %v",
 			len(c.Meta.Args), len(c.Args))
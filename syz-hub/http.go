@@ -4,18 +4,25 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/google/syzkaller/pkg/html/pages"
 	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/syz-hub/state"
 )
 
 func (hub *Hub) initHTTP(addr string) {
 	http.HandleFunc("/", hub.httpSummary)
+	http.HandleFunc("/calls", hub.httpCalls)
+	http.HandleFunc("/repros", hub.httpRepros)
 
 	ln, err := net.Listen("tcp4", addr)
 	if err != nil {
@@ -28,22 +35,38 @@ func (hub *Hub) initHTTP(addr string) {
 	}()
 }
 
+// All pages can be requested in JSON format with ?json=1 parameter (e.g. for monitoring).
+
 func (hub *Hub) httpSummary(w http.ResponseWriter, r *http.Request) {
+	data := hub.summaryData()
+	if serveJSON(w, r, data) {
+		return
+	}
+	var err error
+	if data.Graphs, err = pages.StatsHTML(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data.Log = log.CachedLogOutput()
+	executeTemplate(w, summaryTemplate, data)
+}
+
+func (hub *Hub) summaryData() *UISummaryData {
 	hub.mu.Lock()
 	defer hub.mu.Unlock()
-
-	data := &UISummaryData{
-		Log: log.CachedLogOutput(),
-	}
+	data := &UISummaryData{}
 	total := UIManager{
 		Name:   "total",
 		Corpus: len(hub.st.Corpus.Records),
 		Repros: len(hub.st.Repros.Records),
 	}
+	domains := make(map[string]*UIDomain)
 	for name, mgr := range hub.st.Managers {
 		total.Added += mgr.Added
 		total.Deleted += mgr.Deleted
 		total.New += mgr.New
+		total.Covered += mgr.Covered
+		total.Dropped += mgr.Dropped
 		total.SentRepros += mgr.SentRepros
 		total.RecvRepros += mgr.RecvRepros
 		data.Managers = append(data.Managers, UIManager{
@@ -54,28 +77,112 @@ func (hub *Hub) httpSummary(w http.ResponseWriter, r *http.Request) {
 			Added:      mgr.Added,
 			Deleted:    mgr.Deleted,
 			New:        mgr.New,
+			Covered:    mgr.Covered,
+			Dropped:    mgr.Dropped,
 			SentRepros: mgr.SentRepros,
 			RecvRepros: mgr.RecvRepros,
+			Connected:  mgr.Connected,
+			LastSync:   mgr.LastSync,
 		})
+		domain := domains[mgr.Domain]
+		if domain == nil {
+			domain = &UIDomain{Name: mgr.Domain}
+			domains[mgr.Domain] = domain
+		}
+		domain.Managers++
+		domain.Recv += mgr.Added
+		domain.Sent += mgr.New
+		domain.Covered += mgr.Covered
+		domain.Dropped += mgr.Dropped
 	}
 	sort.Slice(data.Managers, func(i, j int) bool {
 		return data.Managers[i].Name < data.Managers[j].Name
 	})
 	data.Managers = append([]UIManager{total}, data.Managers...)
-	if err := summaryTemplate.Execute(w, data); err != nil {
+	for _, domain := range domains {
+		data.Domains = append(data.Domains, *domain)
+	}
+	sort.Slice(data.Domains, func(i, j int) bool {
+		return data.Domains[i].Name < data.Domains[j].Name
+	})
+	return data
+}
+
+func (hub *Hub) httpCalls(w http.ResponseWriter, r *http.Request) {
+	hub.mu.Lock()
+	calls := hub.st.CallDistribution()
+	corpus := len(hub.st.Corpus.Records)
+	hub.mu.Unlock()
+	data := &UICallsData{
+		Corpus: corpus,
+	}
+	for call, progs := range calls {
+		data.Calls = append(data.Calls, UICall{
+			Name:    call,
+			Progs:   progs,
+			Percent: progs * 100 / max(corpus, 1),
+		})
+	}
+	sort.Slice(data.Calls, func(i, j int) bool {
+		if data.Calls[i].Progs != data.Calls[j].Progs {
+			return data.Calls[i].Progs > data.Calls[j].Progs
+		}
+		return data.Calls[i].Name < data.Calls[j].Name
+	})
+	if serveJSON(w, r, data) {
+		return
+	}
+	executeTemplate(w, callsTemplate, data)
+}
+
+func (hub *Hub) httpRepros(w http.ResponseWriter, r *http.Request) {
+	hub.mu.Lock()
+	data := &UIReprosData{
+		Total:   len(hub.st.Repros.Records),
+		Pending: hub.st.PendingRepros(),
+	}
+	hub.mu.Unlock()
+	if serveJSON(w, r, data) {
+		return
+	}
+	executeTemplate(w, reprosTemplate, data)
+}
+
+func serveJSON(w http.ResponseWriter, r *http.Request, data any) bool {
+	if r.FormValue("json") != "1" {
+		return false
+	}
+	text, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode json: %v", err), http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(text)
+	return true
+}
+
+func executeTemplate(w http.ResponseWriter, templ *template.Template, data any) {
+	buf := new(bytes.Buffer)
+	if err := templ.Execute(buf, data); err != nil {
 		log.Logf(0, "failed to execute template: %v", err)
 		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
 		return
 	}
+	w.Write(buf.Bytes())
 }
 
 func compileTemplate(html string) *template.Template {
-	return template.Must(template.New("").Parse(strings.ReplaceAll(html, "{{STYLE}}", htmlStyle)))
+	html = strings.ReplaceAll(html, "{{STYLE}}", htmlStyle)
+	html = strings.ReplaceAll(html, "{{NAV}}", htmlNav)
+	return template.Must(template.New("").Parse(html))
 }
 
 type UISummaryData struct {
 	Managers []UIManager
-	Log      string
+	Domains  []UIDomain
+	Graphs   template.HTML `json:"-"`
+	Log      string        `json:"-"`
 }
 
 type UIManager struct {
@@ -86,11 +193,48 @@ type UIManager struct {
 	Added      int
 	Deleted    int
 	New        int
+	Covered    int
+	Dropped    int
 	Repros     int
 	SentRepros int
 	RecvRepros int
+	Connected  time.Time
+	LastSync   time.Time
 }
 
+type UIDomain struct {
+	Name     string
+	Managers int
+	Recv     int
+	Sent     int
+	Covered  int
+	Dropped  int
+}
+
+type UICallsData struct {
+	Corpus int
+	Calls  []UICall
+}
+
+type UICall struct {
+	Name    string
+	Progs   int
+	Percent int
+}
+
+type UIReprosData struct {
+	Total   int
+	Pending []state.PendingRepro
+}
+
+const htmlNav = `
+<b>syz-hub</b>:
+<a href="/">summary</a>
+<a href="/calls">calls</a>
+<a href="/repros">repros</a>
+<br><br>
+`
+
 var summaryTemplate = compileTemplate(`
 <!doctype html>
 <html>
@@ -99,8 +243,7 @@ var summaryTemplate = compileTemplate(`
 	{{STYLE}}
 </head>
 <body>
-<b>syz-hub</b>
-<br><br>
+{{NAV}}
 
 <table>
 	<caption>Managers:</caption>
@@ -112,9 +255,12 @@ var summaryTemplate = compileTemplate(`
 		<th>Added</th>
 		<th>Deleted</th>
 		<th>New</th>
+		<th title="not sent b/c signal is already covered by the manager corpus">Covered</th>
+		<th title="malformed programs received from the manager">Dropped</th>
 		<th>Repros</th>
 		<th>Sent</th>
 		<th>Recv</th>
+		<th>Last sync</th>
 	</tr>
 	{{range $m := $.Managers}}
 	<tr>
@@ -125,14 +271,43 @@ var summaryTemplate = compileTemplate(`
 		<td>{{$m.Added}}</td>
 		<td>{{$m.Deleted}}</td>
 		<td>{{$m.New}}</td>
+		<td>{{$m.Covered}}</td>
+		<td>{{$m.Dropped}}</td>
 		<td>{{$m.Repros}}</td>
 		<td>{{$m.SentRepros}}</td>
 		<td>{{$m.RecvRepros}}</td>
+		<td>{{if not $m.LastSync.IsZero}}{{$m.LastSync.Format "2006-01-02 15:04:05"}}{{end}}</td>
 	</tr>
 	{{end}}
 </table>
 <br><br>
 
+<table>
+	<caption>Domains:</caption>
+	<tr>
+		<th>Domain</th>
+		<th>Managers</th>
+		<th title="programs received from managers in the domain">Recv</th>
+		<th title="programs sent to managers in the domain">Sent</th>
+		<th>Covered</th>
+		<th>Dropped</th>
+	</tr>
+	{{range $d := $.Domains}}
+	<tr>
+		<td>{{$d.Name}}</td>
+		<td>{{$d.Managers}}</td>
+		<td>{{$d.Recv}}</td>
+		<td>{{$d.Sent}}</td>
+		<td>{{$d.Covered}}</td>
+		<td>{{$d.Dropped}}</td>
+	</tr>
+	{{end}}
+</table>
+<br><br>
+
+{{.Graphs}}
+<br><br>
+
 Log:
 <br>
 <textarea id="log_textarea" readonly rows="50">
@@ -146,6 +321,62 @@ Log:
 </body></html>
 `)
 
+var callsTemplate = compileTemplate(`
+<!doctype html>
+<html>
+<head>
+	<title>syz-hub calls</title>
+	{{STYLE}}
+</head>
+<body>
+{{NAV}}
+
+<table>
+	<caption>Syscalls in the corpus of {{.Corpus}} programs:</caption>
+	<tr>
+		<th>Syscall</th>
+		<th>Programs</th>
+		<th>Percent</th>
+	</tr>
+	{{range $c := $.Calls}}
+	<tr>
+		<td>{{$c.Name}}</td>
+		<td>{{$c.Progs}}</td>
+		<td>{{$c.Percent}}%</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
+
+var reprosTemplate = compileTemplate(`
+<!doctype html>
+<html>
+<head>
+	<title>syz-hub repros</title>
+	{{STYLE}}
+</head>
+<body>
+{{NAV}}
+
+<table>
+	<caption>Pending repros ({{len .Pending}} out of {{.Total}}):</caption>
+	<tr>
+		<th>Repro</th>
+		<th>Syscalls</th>
+		<th>Pending for managers</th>
+	</tr>
+	{{range $r := $.Pending}}
+	<tr>
+		<td>{{$r.Sig}}</td>
+		<td>{{range $c := $r.Calls}}{{$c}} {{end}}</td>
+		<td>{{range $m := $r.Managers}}{{$m}} {{end}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
+
 const htmlStyle = `
 	<style type="text/css" media="screen">
 		table {
@@ -32,10 +32,11 @@ type Config struct {
 }
 
 type Hub struct {
-	mu   sync.Mutex
-	st   *state.State
-	keys map[string]string
-	auth auth.Endpoint
+	mu    sync.Mutex
+	st    *state.State
+	keys  map[string]string
+	auth  auth.Endpoint
+	stats *hubStats
 }
 
 func main() {
@@ -51,10 +52,12 @@ func main() {
 		log.Fatalf("failed to load state: %v", err)
 	}
 	hub := &Hub{
-		st:   st,
-		keys: make(map[string]string),
-		auth: auth.MakeEndpoint(auth.GoogleTokenInfoEndpoint),
+		st:    st,
+		keys:  make(map[string]string),
+		auth:  auth.MakeEndpoint(auth.GoogleTokenInfoEndpoint),
+		stats: newHubStats(),
 	}
+	hub.stats.update(hub)
 	for _, mgr := range cfg.Clients {
 		hub.keys[mgr.Name] = mgr.Key
 	}
@@ -78,12 +81,21 @@ func (hub *Hub) Connect(a *rpctype.HubConnectArgs, r *int) error {
 	hub.mu.Lock()
 	defer hub.mu.Unlock()
 
-	log.Logf(0, "connect from %v (%v): domain=%v fresh=%v calls=%v corpus=%v",
-		name, a.HTTP, a.Domain, a.Fresh, len(a.Calls), len(a.Corpus))
-	if err := hub.st.Connect(name, a.HTTP, a.Domain, a.Fresh, a.Calls, a.Corpus); err != nil {
+	dropped := 0
+	if mgr := hub.st.Managers[name]; mgr != nil {
+		dropped = mgr.Dropped
+	}
+	log.Logf(0, "connect from %v (%v): domain=%v space=%v fresh=%v calls=%v corpus=%v signal=%v digest=%v",
+		name, a.HTTP, a.Domain, a.SignalSpace, a.Fresh, len(a.Calls), len(a.Corpus), len(a.CorpusSignal),
+		len(a.Digest))
+	if err := hub.st.Connect(name, a.HTTP, a.Domain, a.SignalSpace, a.Fresh, a.Calls, a.Corpus,
+		a.CorpusSignal, a.Digest); err != nil {
 		log.Logf(0, "connect error: %v", err)
 		return err
 	}
+	hub.stats.recvInputs.Add(len(a.Corpus))
+	hub.stats.droppedInputs.Add(hub.st.Managers[name].Dropped - dropped)
+	hub.stats.update(hub)
 	return nil
 }
 
@@ -95,11 +107,20 @@ func (hub *Hub) Sync(a *rpctype.HubSyncArgs, r *rpctype.HubSyncRes) error {
 	hub.mu.Lock()
 	defer hub.mu.Unlock()
 
-	domain, inputs, more, err := hub.st.Sync(name, a.Add, a.Del)
+	var covered, dropped int
+	if mgr := hub.st.Managers[name]; mgr != nil {
+		covered, dropped = mgr.Covered, mgr.Dropped
+	}
+	domain, inputs, more, err := hub.st.Sync(name, a.Add, a.AddSignal, a.Del, a.Digest)
 	if err != nil {
 		log.Logf(0, "sync error: %v", err)
 		return err
 	}
+	mgr := hub.st.Managers[name]
+	hub.stats.recvInputs.Add(len(a.Add))
+	hub.stats.sentInputs.Add(len(inputs))
+	hub.stats.coveredInputs.Add(mgr.Covered - covered)
+	hub.stats.droppedInputs.Add(mgr.Dropped - dropped)
 	if domain != "" {
 		r.Inputs = inputs
 	} else {
@@ -122,6 +143,9 @@ func (hub *Hub) Sync(a *rpctype.HubSyncArgs, r *rpctype.HubSyncRes) error {
 			r.Repros = [][]byte{repro}
 		}
 	}
+	hub.stats.recvRepros.Add(len(a.Repros))
+	hub.stats.sentRepros.Add(len(r.Repros))
+	hub.stats.update(hub)
 	log.Logf(0, "sync from %v: recv: add=%v del=%v repros=%v; send: progs=%v repros=%v pending=%v",
 		name, len(a.Add), len(a.Del), len(a.Repros), len(inputs), len(r.Repros), more)
 	return nil
@@ -133,6 +157,7 @@ func (hub *Hub) purgeOldManagers() {
 		if err := hub.st.PurgeOldManagers(); err != nil {
 			log.Logf(0, "failed to purge managers: %v", err)
 		}
+		hub.stats.update(hub)
 		hub.mu.Unlock()
 	}
 }
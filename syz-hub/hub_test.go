@@ -4,8 +4,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/syz-hub/state"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAuth(t *testing.T) {
@@ -101,3 +108,68 @@ func TestAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP(t *testing.T) {
+	st, err := state.Make(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := &Hub{
+		st:    st,
+		keys:  map[string]string{"foo": "1234"},
+		stats: newHubStats(),
+	}
+	for _, name := range []string{"foo-0", "foo-1"} {
+		assert.NoError(t, hub.Connect(&rpctype.HubConnectArgs{
+			Client:  "foo",
+			Key:     "1234",
+			Manager: name,
+			Domain:  "linux/" + name,
+			Calls:   []string{"open", "read"},
+			Corpus:  [][]byte{[]byte("open(0x0)"), []byte("open(0x1)\nread()")},
+		}, nil))
+	}
+	res := new(rpctype.HubSyncRes)
+	assert.NoError(t, hub.Sync(&rpctype.HubSyncArgs{
+		Client:  "foo",
+		Key:     "1234",
+		Manager: "foo-0",
+		Add:     [][]byte{[]byte("read()"), []byte("bad")},
+		Repros:  [][]byte{[]byte("read()")},
+	}, res))
+
+	get := func(handler http.HandlerFunc, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, url)
+		return rec
+	}
+	for _, handler := range []http.HandlerFunc{hub.httpSummary, hub.httpCalls, hub.httpRepros} {
+		get(handler, "/")
+	}
+
+	summary := new(UISummaryData)
+	assert.NoError(t, json.Unmarshal(get(hub.httpSummary, "/?json=1").Body.Bytes(), summary))
+	assert.Len(t, summary.Managers, 3)
+	assert.Equal(t, 3, summary.Managers[0].Corpus)
+	assert.Equal(t, 1, summary.Managers[0].Dropped)
+	assert.False(t, summary.Managers[1].LastSync.IsZero())
+	assert.True(t, summary.Managers[2].LastSync.IsZero())
+	assert.Len(t, summary.Domains, 2)
+
+	calls := new(UICallsData)
+	assert.NoError(t, json.Unmarshal(get(hub.httpCalls, "/calls?json=1").Body.Bytes(), calls))
+	assert.Equal(t, []UICall{
+		{Name: "open", Progs: 2, Percent: 66},
+		{Name: "read", Progs: 2, Percent: 66},
+	}, calls.Calls)
+
+	repros := new(UIReprosData)
+	assert.NoError(t, json.Unmarshal(get(hub.httpRepros, "/repros?json=1").Body.Bytes(), repros))
+	assert.Equal(t, []state.PendingRepro{{
+		Sig:      repros.Pending[0].Sig,
+		Calls:    []string{"read"},
+		Managers: []string{"foo-1"},
+	}}, repros.Pending)
+}
@@ -4,7 +4,10 @@
 package state
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"sort"
@@ -17,6 +20,7 @@ import (
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/prog"
 )
 
@@ -29,7 +33,12 @@ type State struct {
 	dir       string
 	Corpus    *db.DB
 	Repros    *db.DB
-	Managers  map[string]*Manager
+	// Signal of corpus programs (if known), see encodeInputSignal.
+	Signal   *db.DB
+	Managers map[string]*Manager
+	// The number of corpus programs that contain each syscall.
+	// It's updated incrementally since parsing the whole corpus is slow.
+	calls map[string]int
 }
 
 // Manager represents one syz-manager instance.
@@ -44,11 +53,18 @@ type Manager struct {
 	corpusSeqFile string
 	reproSeqFile  string
 	domainFile    string
+	spaceFile     string
+	signalSpace   string // see rpctype.HubConnectArgs.SignalSpace
+	digestFile    string
+	digest        signal.Digest
 	ownRepros     map[string]bool
 	Connected     time.Time
+	LastSync      time.Time
 	Added         int
 	Deleted       int
 	New           int
+	Covered       int
+	Dropped       int
 	SentRepros    int
 	RecvRepros    int
 	Calls         map[string]struct{}
@@ -68,10 +84,18 @@ func Make(dir string) (*State, error) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	st.calls = make(map[string]int)
+	for _, rec := range st.Corpus.Records {
+		st.countCalls(rec.Val, 1)
+	}
 	st.Repros, st.reproSeq, err = loadDB(filepath.Join(st.dir, "repro.db"), "repro", true)
 	if err != nil {
 		log.Fatal(err)
 	}
+	st.Signal, _, err = loadDB(filepath.Join(st.dir, "signal.db"), "signal", false)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	managersDir := filepath.Join(st.dir, "manager")
 	osutil.MkdirAll(managersDir)
@@ -101,6 +125,9 @@ func (st *State) Flush() {
 	if err := st.Corpus.Flush(); err != nil {
 		log.Logf(0, "failed to flush corpus database: %v", err)
 	}
+	if err := st.Signal.Flush(); err != nil {
+		log.Logf(0, "failed to flush signal database: %v", err)
+	}
 	for _, mgr := range st.Managers {
 		if err := mgr.Corpus.Flush(); err != nil {
 			log.Logf(0, "failed to flush corpus database: %v", err)
@@ -153,6 +180,8 @@ func (st *State) createManager(name string) (*Manager, error) {
 		corpusSeqFile: filepath.Join(dir, "seq"),
 		reproSeqFile:  filepath.Join(dir, "repro.seq"),
 		domainFile:    filepath.Join(dir, "domain"),
+		spaceFile:     filepath.Join(dir, "signal_space"),
+		digestFile:    filepath.Join(dir, "digest"),
 		ownRepros:     make(map[string]bool),
 	}
 	mgr.corpusSeq = loadSeqFile(mgr.corpusSeqFile)
@@ -164,6 +193,9 @@ func (st *State) createManager(name string) (*Manager, error) {
 	st.reproSeq = max(st.reproSeq, mgr.reproSeq)
 	domainData, _ := os.ReadFile(mgr.domainFile)
 	mgr.Domain = string(domainData)
+	spaceData, _ := os.ReadFile(mgr.spaceFile)
+	mgr.signalSpace = string(spaceData)
+	mgr.digest, _ = os.ReadFile(mgr.digestFile)
 	corpus, _, err := loadDB(mgr.corpusFile, name, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open manager corpus %v: %w", mgr.corpusFile, err)
@@ -209,7 +241,8 @@ func (st *State) PurgeOldManagers() error {
 	return nil
 }
 
-func (st *State) Connect(name, http, domain string, fresh bool, calls []string, corpus [][]byte) error {
+func (st *State) Connect(name, http, domain, signalSpace string, fresh bool, calls []string, corpus [][]byte,
+	corpusSignal [][]uint32, digest []byte) error {
 	mgr := st.Managers[name]
 	if mgr == nil {
 		var err error
@@ -222,6 +255,10 @@ func (st *State) Connect(name, http, domain string, fresh bool, calls []string,
 	mgr.Connected = time.Now()
 	mgr.Domain = domain
 	writeFile(mgr.domainFile, []byte(mgr.Domain))
+	mgr.signalSpace = signalSpace
+	writeFile(mgr.spaceFile, []byte(mgr.signalSpace))
+	mgr.digest = digest
+	writeFile(mgr.digestFile, mgr.digest)
 	if fresh {
 		mgr.corpusSeq = 0
 		mgr.reproSeq = st.reproSeq
@@ -241,16 +278,21 @@ func (st *State) Connect(name, http, domain string, fresh bool, calls []string,
 		log.Logf(0, "failed to open corpus database: %v", err)
 		return err
 	}
-	st.addInputs(mgr, corpus)
+	st.addInputs(mgr, corpus, corpusSignal)
 	st.purgeCorpus()
 	return nil
 }
 
-func (st *State) Sync(name string, add [][]byte, del []string) (string, []rpctype.HubInput, int, error) {
+func (st *State) Sync(name string, add [][]byte, addSignal [][]uint32, del []string, digest []byte) (
+	string, []rpctype.HubInput, int, error) {
 	mgr := st.Managers[name]
 	if mgr == nil || mgr.Connected.IsZero() {
 		return "", nil, 0, fmt.Errorf("unconnected manager %v", name)
 	}
+	if digest != nil {
+		mgr.digest = digest
+		writeFile(mgr.digestFile, mgr.digest)
+	}
 	if len(del) != 0 {
 		for _, sig := range del {
 			mgr.Corpus.Delete(sig)
@@ -260,8 +302,9 @@ func (st *State) Sync(name string, add [][]byte, del []string) (string, []rpctyp
 		}
 		st.purgeCorpus()
 	}
-	st.addInputs(mgr, add)
+	st.addInputs(mgr, add, addSignal)
 	progs, more, err := st.pendingInputs(mgr)
+	mgr.LastSync = time.Now()
 	mgr.Added += len(add)
 	mgr.Deleted += len(del)
 	mgr.New += len(progs)
@@ -338,6 +381,63 @@ func (st *State) PendingRepro(name string) ([]byte, error) {
 	return repro, nil
 }
 
+// PendingRepro describes a repro that is not yet sent to some managers.
+type PendingRepro struct {
+	Sig      string
+	Calls    []string
+	Managers []string
+}
+
+// PendingRepros returns repros that are not yet sent to some connected managers.
+func (st *State) PendingRepros() []PendingRepro {
+	var res []PendingRepro
+	for key, rec := range st.Repros.Records {
+		calls, _, err := prog.CallSet(rec.Val)
+		if err != nil {
+			continue
+		}
+		repro := PendingRepro{Sig: key}
+		for name, mgr := range st.Managers {
+			if mgr.Connected.IsZero() || mgr.reproSeq >= rec.Seq || mgr.ownRepros[key] ||
+				!managerSupportsAllCalls(mgr.Calls, calls) {
+				continue
+			}
+			repro.Managers = append(repro.Managers, name)
+		}
+		if len(repro.Managers) == 0 {
+			continue
+		}
+		for call := range calls {
+			repro.Calls = append(repro.Calls, call)
+		}
+		sort.Strings(repro.Calls)
+		sort.Strings(repro.Managers)
+		res = append(res, repro)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return st.Repros.Records[res[i].Sig].Seq < st.Repros.Records[res[j].Sig].Seq
+	})
+	return res
+}
+
+// CallDistribution returns the number of corpus programs that contain each syscall.
+func (st *State) CallDistribution() map[string]int {
+	return maps.Clone(st.calls)
+}
+
+func (st *State) countCalls(input []byte, delta int) {
+	calls, _, err := prog.CallSet(input)
+	if err != nil {
+		return
+	}
+	for call := range calls {
+		st.calls[call] += delta
+		if st.calls[call] == 0 {
+			delete(st.calls, call)
+		}
+	}
+}
+
 func (st *State) pendingInputs(mgr *Manager) ([]rpctype.HubInput, int, error) {
 	if mgr.corpusSeq == st.corpusSeq {
 		return nil, 0, nil
@@ -362,6 +462,10 @@ func (st *State) pendingInputs(mgr *Manager) ([]rpctype.HubInput, int, error) {
 		if !managerSupportsAllCalls(mgr.Calls, calls) {
 			continue
 		}
+		if st.inputCovered(mgr, key) {
+			mgr.Covered++
+			continue
+		}
 		records = append(records, Record{key, rec.Val, rec.Seq})
 	}
 	maxSeq := st.corpusSeq
@@ -420,13 +524,39 @@ func (st *State) inputDomain(key, self string) string {
 	return domain
 }
 
-func (st *State) addInputs(mgr *Manager, inputs [][]byte) {
+// inputCovered says if signal of the input is already covered by the last digest reported by the manager.
+// Signal is comparable only between managers in the same signal space (i.e. testing the same kernel build),
+// regardless of their domains, so we don't filter inputs with signal from other signal spaces.
+func (st *State) inputCovered(mgr *Manager, key string) bool {
+	if len(mgr.digest) == 0 || mgr.signalSpace == "" {
+		return false
+	}
+	rec, ok := st.Signal.Records[key]
+	if !ok {
+		return false
+	}
+	space, hashes, err := decodeInputSignal(rec.Val)
+	if err != nil {
+		log.Logf(0, "bad signal record %v: %v", key, err)
+		return false
+	}
+	return space == mgr.signalSpace && mgr.digest.ContainsAll(hashes)
+}
+
+func (st *State) addInputs(mgr *Manager, inputs [][]byte, inputSignal [][]uint32) {
 	if len(inputs) == 0 {
 		return
 	}
+	if len(inputSignal) != len(inputs) {
+		inputSignal = nil
+	}
 	st.corpusSeq++
-	for _, input := range inputs {
-		st.addInput(mgr, input)
+	for i, input := range inputs {
+		var hashes []uint32
+		if inputSignal != nil {
+			hashes = inputSignal[i]
+		}
+		st.addInput(mgr, input, hashes)
 	}
 	if err := mgr.Corpus.Flush(); err != nil {
 		log.Logf(0, "failed to flush corpus database: %v", err)
@@ -434,23 +564,57 @@ func (st *State) addInputs(mgr *Manager, inputs [][]byte) {
 	if err := st.Corpus.Flush(); err != nil {
 		log.Logf(0, "failed to flush corpus database: %v", err)
 	}
+	if err := st.Signal.Flush(); err != nil {
+		log.Logf(0, "failed to flush signal database: %v", err)
+	}
 }
 
-func (st *State) addInput(mgr *Manager, input []byte) {
+func (st *State) addInput(mgr *Manager, input []byte, hashes []uint32) {
 	_, ncalls, err := prog.CallSet(input)
 	if err != nil {
 		log.Logf(0, "manager %v: failed to extract call set: %v, program:\n%v", mgr.name, err, string(input))
+		mgr.Dropped++
 		return
 	}
 	if want := prog.MaxCalls; ncalls > want {
 		log.Logf(0, "manager %v: too long program, ignoring (%v/%v)", mgr.name, ncalls, want)
+		mgr.Dropped++
 		return
 	}
 	sig := hash.String(input)
 	mgr.Corpus.Save(sig, nil, 0)
 	if _, ok := st.Corpus.Records[sig]; !ok {
 		st.Corpus.Save(sig, input, st.corpusSeq)
+		st.countCalls(input, 1)
+	}
+	if _, ok := st.Signal.Records[sig]; !ok && len(hashes) != 0 && mgr.signalSpace != "" {
+		st.Signal.Save(sig, encodeInputSignal(mgr.signalSpace, hashes), 0)
+	}
+}
+
+// encodeInputSignal encodes signal of an input together with signal space of the manager
+// that reported the signal as: signal space, 0 byte, little-endian 32-bit signal hashes.
+func encodeInputSignal(space string, hashes []uint32) []byte {
+	res := make([]byte, 0, len(space)+1+len(hashes)*4)
+	res = append(res, space...)
+	res = append(res, 0)
+	for _, h := range hashes {
+		res = binary.LittleEndian.AppendUint32(res, h)
+	}
+	return res
+}
+
+func decodeInputSignal(data []byte) (string, []uint32, error) {
+	pos := bytes.IndexByte(data, 0)
+	if pos == -1 || (len(data)-pos-1)%4 != 0 {
+		return "", nil, fmt.Errorf("malformed signal record")
+	}
+	space := string(data[:pos])
+	var hashes []uint32
+	for data = data[pos+1:]; len(data) != 0; data = data[4:] {
+		hashes = append(hashes, binary.LittleEndian.Uint32(data))
 	}
+	return space, hashes, nil
 }
 
 func (st *State) purgeCorpus() {
@@ -460,15 +624,24 @@ func (st *State) purgeCorpus() {
 			used[sig] = true
 		}
 	}
-	for key := range st.Corpus.Records {
+	for key, rec := range st.Corpus.Records {
 		if used[key] {
 			continue
 		}
+		st.countCalls(rec.Val, -1)
 		st.Corpus.Delete(key)
 	}
 	if err := st.Corpus.Flush(); err != nil {
 		log.Logf(0, "failed to flush corpus database: %v", err)
 	}
+	for key := range st.Signal.Records {
+		if !used[key] {
+			st.Signal.Delete(key)
+		}
+	}
+	if err := st.Signal.Flush(); err != nil {
+		log.Logf(0, "failed to flush signal database: %v", err)
+	}
 }
 
 func managerSupportsAllCalls(mgr, prog map[string]struct{}) bool {
@@ -8,7 +8,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/stretchr/testify/assert"
 )
 
 type TestState struct {
@@ -38,14 +41,26 @@ func (ts *TestState) Reload() {
 
 func (ts *TestState) Connect(name, domain string, fresh bool, calls []string, corpus [][]byte) {
 	ts.t.Helper()
-	if err := ts.state.Connect(name, "", domain, fresh, calls, corpus); err != nil {
+	ts.ConnectSignal(name, domain, "", fresh, calls, corpus, nil, nil)
+}
+
+func (ts *TestState) ConnectSignal(name, domain, space string, fresh bool, calls []string, corpus [][]byte,
+	corpusSignal [][]uint32, digest []byte) {
+	ts.t.Helper()
+	if err := ts.state.Connect(name, "", domain, space, fresh, calls, corpus, corpusSignal, digest); err != nil {
 		ts.t.Fatalf("Connect failed: %v", err)
 	}
 }
 
 func (ts *TestState) Sync(name string, add [][]byte, del []string) (string, []rpctype.HubInput, int) {
 	ts.t.Helper()
-	domain, inputs, pending, err := ts.state.Sync(name, add, del)
+	return ts.SyncSignal(name, add, nil, del, nil)
+}
+
+func (ts *TestState) SyncSignal(name string, add [][]byte, addSignal [][]uint32, del []string, digest []byte) (
+	string, []rpctype.HubInput, int) {
+	ts.t.Helper()
+	domain, inputs, pending, err := ts.state.Sync(name, add, addSignal, del, digest)
 	if err != nil {
 		ts.t.Fatalf("Sync failed: %v", err)
 	}
@@ -77,7 +92,7 @@ func (ts *TestState) PendingRepro(name string) []byte {
 func TestBasic(t *testing.T) {
 	st := MakeTestState(t)
 
-	if _, _, _, err := st.state.Sync("foo", nil, nil); err == nil {
+	if _, _, _, err := st.state.Sync("foo", nil, nil, nil, nil); err == nil {
 		t.Fatalf("synced with unconnected manager")
 	}
 	calls := []string{"read", "write"}
@@ -186,3 +201,122 @@ func TestDomain(t *testing.T) {
 		}
 	}
 }
+
+func TestSignal(t *testing.T) {
+	st := MakeTestState(t)
+
+	sig := func(elems ...uint64) signal.Signal {
+		return signal.FromRaw(elems, 0)
+	}
+	calls := []string{"open"}
+	// The first program is covered by the bar corpus, the second is not,
+	// signal of the third one is unknown.
+	st.ConnectSignal("foo", "domain", "build", false, calls,
+		[][]byte{[]byte("open(0x0)"), []byte("open(0x1)")},
+		[][]uint32{sig(1, 2).DigestHashes(), sig(2, 3).DigestHashes()}, nil)
+	st.ConnectSignal("baz", "domain", "build", false, calls, [][]byte{[]byte("open(0x2)")}, nil, nil)
+	barDigest := sig(1, 2).Digest()
+	st.ConnectSignal("bar", "domain", "build", false, calls, nil, nil, barDigest)
+	// Signal from another build is not comparable.
+	st.ConnectSignal("qux", "other", "other-build", false, calls, nil, nil, nil)
+	{
+		_, inputs, _ := st.Sync("bar", nil, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Domain: "domain", Prog: []byte("open(0x1)")},
+			{Domain: "domain", Prog: []byte("open(0x2)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+		if st.state.Managers["bar"].Covered != 1 {
+			t.Fatalf("covered %v, want 1", st.state.Managers["bar"].Covered)
+		}
+	}
+	st.Reload()
+	// Signal of inputs is persistent, and the new digest covers open(0x1) as well.
+	st.ConnectSignal("qux", "other", "other-build", true, calls, nil, nil, nil)
+	st.ConnectSignal("bar", "domain", "build", true, calls, nil, nil, barDigest)
+	{
+		_, inputs, _ := st.SyncSignal("bar", nil, nil, nil, sig(1, 2, 3).Digest())
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Domain: "domain", Prog: []byte("open(0x2)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+	{
+		_, inputs, _ := st.SyncSignal("qux", nil, nil, nil, sig(1, 2, 3).Digest())
+		if len(inputs) != 3 {
+			t.Fatalf("got %v inputs, want 3", len(inputs))
+		}
+	}
+	// Signal of programs added with Sync is used as well.
+	st.ConnectSignal("baz", "domain", "build", false, calls, nil, nil, nil)
+	st.SyncSignal("baz", [][]byte{[]byte("open(0x3)"), []byte("open(0x4)")},
+		[][]uint32{sig(3).DigestHashes(), sig(4).DigestHashes()}, nil, nil)
+	{
+		_, inputs, _ := st.Sync("bar", nil, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Domain: "domain", Prog: []byte("open(0x4)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
+func TestSignalSpace(t *testing.T) {
+	st := MakeTestState(t)
+
+	sig := func(elems ...uint64) signal.Signal {
+		return signal.FromRaw(elems, 0)
+	}
+	calls := []string{"open"}
+	st.ConnectSignal("foo", "domain1", "build", false, calls,
+		[][]byte{[]byte("open(0x0)"), []byte("open(0x1)")},
+		[][]uint32{sig(1, 2).DigestHashes(), sig(2, 3).DigestHashes()}, nil)
+	// Managers in different domains that test the same build filter inputs by signal.
+	st.ConnectSignal("bar", "domain2", "build", false, calls, nil, nil, sig(1, 2).Digest())
+	{
+		_, inputs, _ := st.Sync("bar", nil, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Domain: "domain1", Prog: []byte("open(0x1)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+		if st.state.Managers["bar"].Covered != 1 {
+			t.Fatalf("covered %v, want 1", st.state.Managers["bar"].Covered)
+		}
+	}
+	// Managers in the same domain that test different builds don't.
+	st.ConnectSignal("baz", "domain1", "other-build", false, calls, nil, nil, sig(1, 2, 3).Digest())
+	{
+		_, inputs, _ := st.Sync("baz", nil, nil)
+		if diff := cmp.Diff(inputs, []rpctype.HubInput{
+			{Domain: "domain1", Prog: []byte("open(0x0)")},
+			{Domain: "domain1", Prog: []byte("open(0x1)")},
+		}); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+	// Managers that don't report signal space don't filter either.
+	st.ConnectSignal("qux", "domain2", "", false, calls, nil, nil, sig(1, 2, 3).Digest())
+	{
+		_, inputs, _ := st.Sync("qux", nil, nil)
+		if len(inputs) != 2 {
+			t.Fatalf("got %v inputs, want 2", len(inputs))
+		}
+	}
+}
+
+func TestCallDistribution(t *testing.T) {
+	st := MakeTestState(t)
+
+	st.Connect("foo", "", false, []string{"open", "read"},
+		[][]byte{[]byte("open(0x0)"), []byte("open(0x1)\nread()")})
+	assert.Equal(t, map[string]int{"open": 2, "read": 1}, st.state.CallDistribution())
+
+	st.Sync("foo", nil, []string{hash.String([]byte("open(0x1)\nread()"))})
+	assert.Equal(t, map[string]int{"open": 1}, st.state.CallDistribution())
+
+	st.Reload()
+	assert.Equal(t, map[string]int{"open": 1}, st.state.CallDistribution())
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sync/atomic"
+
+	"github.com/google/syzkaller/pkg/stat"
+)
+
+// hubStats are exported via pkg/stat and shown as graphs on the web UI.
+// Stat values are read from the stat ticker goroutine, so gauges are stored in atomic
+// variables that are updated under hub mutex rather than read directly from the state.
+type hubStats struct {
+	recvInputs    *stat.Val
+	sentInputs    *stat.Val
+	coveredInputs *stat.Val
+	droppedInputs *stat.Val
+	recvRepros    *stat.Val
+	sentRepros    *stat.Val
+
+	corpus    atomic.Int64
+	repros    atomic.Int64
+	managers  atomic.Int64
+	mgrCorpus map[string]*mgrStat
+}
+
+type mgrStat struct {
+	corpus atomic.Int64
+	stat   *stat.Val
+}
+
+func newHubStats() *hubStats {
+	s := &hubStats{
+		recvInputs: stat.New("recv inputs", "Programs received from managers",
+			stat.Rate{}, stat.Graph("inputs")),
+		sentInputs: stat.New("sent inputs", "Programs sent to managers",
+			stat.Rate{}, stat.Graph("inputs")),
+		coveredInputs: stat.New("covered inputs", "Programs not sent b/c their signal is already covered",
+			stat.Rate{}, stat.Graph("inputs")),
+		droppedInputs: stat.New("dropped inputs", "Malformed programs received from managers",
+			stat.Rate{}, stat.Graph("inputs")),
+		recvRepros: stat.New("recv repros", "Repros received from managers",
+			stat.Rate{}, stat.Graph("repros")),
+		sentRepros: stat.New("sent repros", "Repros sent to managers",
+			stat.Rate{}, stat.Graph("repros")),
+		mgrCorpus: make(map[string]*mgrStat),
+	}
+	stat.New("corpus", "Programs in the hub corpus", stat.Graph("corpus"), s.load(&s.corpus))
+	stat.New("repro db", "Repros in the hub database", stat.Graph("repros"), s.load(&s.repros))
+	stat.New("managers", "Number of connected managers", s.load(&s.managers))
+	return s
+}
+
+func (s *hubStats) load(v *atomic.Int64) func() int {
+	return func() int {
+		return int(v.Load())
+	}
+}
+
+// update must be called with hub mutex held.
+func (s *hubStats) update(hub *Hub) {
+	s.corpus.Store(int64(len(hub.st.Corpus.Records)))
+	s.repros.Store(int64(len(hub.st.Repros.Records)))
+	// Purged managers don't come back, so unregister their stats.
+	for name, ms := range s.mgrCorpus {
+		if hub.st.Managers[name] == nil {
+			stat.Delete(ms.stat)
+			delete(s.mgrCorpus, name)
+		}
+	}
+	connected := 0
+	for name, mgr := range hub.st.Managers {
+		if !mgr.Connected.IsZero() {
+			connected++
+		}
+		ms := s.mgrCorpus[name]
+		if ms == nil {
+			ms = new(mgrStat)
+			ms.stat = stat.New("corpus "+name, "Corpus of "+name, stat.Graph("manager corpus"), s.load(&ms.corpus))
+			s.mgrCorpus[name] = ms
+		}
+		ms.corpus.Store(int64(len(mgr.Corpus.Records)))
+	}
+	s.managers.Store(int64(connected))
+}
@@ -4,9 +4,9 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/google/syzkaller/pkg/auth"
@@ -19,6 +19,7 @@ import (
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
 	"github.com/google/syzkaller/prog"
 )
@@ -72,6 +73,7 @@ type HubConnector struct {
 	enabledCalls   map[*prog.Syscall]bool
 	leak           bool
 	fresh          bool
+	digest         signal.Digest // the last corpus digest sent to the hub
 	newRepros      [][]byte
 	hubReproQueue  chan *manager.Crash
 	needMoreRepros func() bool
@@ -87,6 +89,7 @@ type HubConnector struct {
 // HubManagerView restricts interface between HubConnector and Manager.
 type HubManagerView interface {
 	getMinimizedCorpus() []*corpus.Item
+	getCorpusDigest() signal.Digest
 	getNewRepros() [][]byte
 	addNewCandidates(candidates []fuzzer.Candidate)
 	needMoreCandidates() bool
@@ -164,11 +167,18 @@ func (hc *HubConnector) connect(corpus []*corpus.Item) (*rpctype.RPCClient, erro
 		Domain:  hc.domain,
 		Fresh:   hc.fresh,
 	}
+	if hc.cfg.Cover && hc.cfg.Tag != "" {
+		// Tag identifies the kernel build (syz-ci sets it to a combination of
+		// the compiler, kernel commit and config), so signal of managers
+		// with the same target and tag is comparable.
+		a.SignalSpace = hc.cfg.RawTarget + "/" + hc.cfg.Tag
+	}
 	for call := range hc.enabledCalls {
 		a.Calls = append(a.Calls, call.Name)
 	}
 	for _, inp := range corpus {
 		a.Corpus = append(a.Corpus, inp.Prog.Serialize())
+		a.CorpusSignal = append(a.CorpusSignal, inp.Signal.DigestHashes())
 	}
 	// Never send more than this, this is never healthy but happens episodically
 	// due to various reasons: problems with fallback coverage, bugs in kcov,
@@ -176,6 +186,10 @@ func (hc *HubConnector) connect(corpus []*corpus.Item) (*rpctype.RPCClient, erro
 	const max = 100 * 1000
 	if len(a.Corpus) > max {
 		a.Corpus = a.Corpus[:max]
+		a.CorpusSignal = a.CorpusSignal[:max]
+	}
+	if hc.cfg.Cover {
+		a.Digest = hc.mgr.getCorpusDigest()
 	}
 	err = hub.Call("Hub.Connect", a, nil)
 	// Hub.Connect request can be very large, so do it on a transient connection
@@ -184,6 +198,7 @@ func (hc *HubConnector) connect(corpus []*corpus.Item) (*rpctype.RPCClient, erro
 	if err != nil {
 		return nil, err
 	}
+	hc.digest = a.Digest
 	hub, err = rpctype.NewRPCClient(hc.cfg.HubAddr)
 	if err != nil {
 		return nil, err
@@ -205,12 +220,22 @@ func (hc *HubConnector) sync(hub *rpctype.RPCClient) error {
 	if hc.needMoreRepros != nil {
 		a.NeedRepros = hc.needMoreRepros()
 	}
+	if hc.cfg.Cover {
+		// The hub does not send us programs that are already covered by our corpus.
+		// The digest is large, so we send it only if the corpus signal has changed.
+		if digest := hc.mgr.getCorpusDigest(); !bytes.Equal(digest, hc.digest) {
+			a.Digest = digest
+		}
+	}
 	a.Repros = hc.newRepros
 	for {
 		r := new(rpctype.HubSyncRes)
 		if err := hub.Call("Hub.Sync", a, r); err != nil {
 			return err
 		}
+		if a.Digest != nil {
+			hc.digest = a.Digest
+		}
 		minimized, smashed, progDropped := hc.processProgs(r.Inputs)
 		reproDropped := hc.processRepros(r.Repros)
 		hc.statSendRepro.Add(len(a.Repros))
@@ -225,6 +250,7 @@ func (hc *HubConnector) sync(hub *rpctype.RPCClient) error {
 		a.Add = nil
 		a.Del = nil
 		a.Repros = nil
+		a.Digest = nil
 		a.NeedRepros = false
 		hc.newRepros = nil
 		if len(r.Inputs)+r.More == 0 {
@@ -242,7 +268,7 @@ func (hc *HubConnector) processProgs(inputs []rpctype.HubInput) (minimized, smas
 			dropped++
 			continue
 		}
-		min, smash := matchDomains(hc.domain, inp.Domain)
+		min, smash := manager.MatchDomains(hc.domain, inp.Domain)
 		var flags fuzzer.ProgFlags
 		if min && len(p.Calls) < manager.ReminimizeThreshold {
 			minimized++
@@ -253,40 +279,15 @@ func (hc *HubConnector) processProgs(inputs []rpctype.HubInput) (minimized, smas
 			flags |= fuzzer.ProgSmashed
 		}
 		candidates = append(candidates, fuzzer.Candidate{
-			Prog:  p,
-			Flags: flags,
+			Prog:       p,
+			Flags:      flags,
+			Provenance: corpus.Provenance{Job: corpus.ProvenanceHub},
 		})
 	}
 	hc.mgr.addNewCandidates(candidates)
 	return
 }
 
-func matchDomains(self, input string) (bool, bool) {
-	if self == "" || input == "" {
-		return true, true
-	}
-	min0, smash0 := splitDomains(self)
-	min1, smash1 := splitDomains(input)
-	min := min0 != min1
-	smash := min || smash0 != smash1
-	return min, smash
-}
-
-func splitDomains(domain string) (string, string) {
-	delim0 := strings.IndexByte(domain, '/')
-	if delim0 == -1 {
-		return domain, ""
-	}
-	if delim0 == len(domain)-1 {
-		return domain[:delim0], ""
-	}
-	delim1 := strings.IndexByte(domain[delim0+1:], '/')
-	if delim1 == -1 {
-		return domain, ""
-	}
-	return domain[:delim0+delim1+1], domain[delim0+delim1+2:]
-}
-
 func (hc *HubConnector) processRepros(repros [][]byte) int {
 	dropped := 0
 	for _, repro := range repros {
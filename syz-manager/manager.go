@@ -25,6 +25,7 @@ import (
 	"github.com/google/syzkaller/pkg/asset"
 	"github.com/google/syzkaller/pkg/corpus"
 	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/dictionary"
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/fuzzer"
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
@@ -71,8 +72,14 @@ type Manager struct {
 	servStats       rpcserver.Stats
 	corpus          *corpus.Corpus
 	corpusDB        *db.DB
+	provenanceDB    *db.DB
 	corpusDBMu      sync.Mutex // for concurrent operations on corpusDB
 	corpusPreload   chan []fuzzer.Candidate
+	seedStats       map[string]corpus.SeedStats
+	provenance      map[string]corpus.Provenance
+	dictionary      *prog.Dictionary
+	hintsCache      *prog.HintsCache
+	hintsCacheSaved uint64       // version of hintsCache that was last saved
 	firstConnect    atomic.Int64 // unix time, or 0 if not connected
 	crashTypes      map[string]bool
 	enabledFeatures flatrpc.Feature
@@ -80,6 +87,7 @@ type Manager struct {
 	reportGenerator *manager.ReportGeneratorWrapper
 	fresh           bool
 	coverFilters    manager.CoverageFilters
+	directed        *corpus.Directed
 
 	dash *dashapi.Dashboard
 	// This is specifically separated from dash, so that we can keep dash = nil when
@@ -156,6 +164,22 @@ var (
 		Description: `run unit tests
 	Run sys/os/test/* tests in various modes and print results.`,
 	}
+	ModeDirected = &Mode{
+		Name: "directed",
+		Description: `fuzz towards directed_targets from the config and exit when all of them are reached
+	Programs that are closer to the targets in the static call graph are mutated more often.
+	Programs that reach the targets are saved to workdir/directed/.`,
+		LoadCorpus: true,
+		CheckConfig: func(cfg *mgrconfig.Config) error {
+			if !cfg.Cover {
+				return fmt.Errorf("coverage is required")
+			}
+			if len(cfg.Experimental.DirectedTargets) == 0 {
+				return fmt.Errorf("directed_targets are not specified")
+			}
+			return nil
+		},
+	}
 	ModeIfaceProbe = &Mode{
 		Name: "iface-probe",
 		Description: `run dynamic part of kernel interface auto-extraction
@@ -181,6 +205,7 @@ var (
 		ModeCorpusRun,
 		ModeRunTests,
 		ModeIfaceProbe,
+		ModeDirected,
 	}
 )
 
@@ -240,6 +265,8 @@ func main() {
 	if !mode.UseDashboard {
 		cfg.DashboardClient = ""
 		cfg.HubClient = ""
+		cfg.PeersAddr = ""
+		cfg.Peers = nil
 	}
 	RunManager(mode, cfg)
 }
@@ -261,6 +288,14 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
+	var dict *prog.Dictionary
+	if cfg.Experimental.Dictionary != "" {
+		dict, err = dictionary.Load(cfg.Experimental.Dictionary)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Logf(0, "loaded dictionary: %v strings, %v integers", len(dict.Strings), len(dict.Ints))
+	}
 
 	mgr := &Manager{
 		cfg:                cfg,
@@ -280,10 +315,14 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 		crashes:            make(chan *manager.Crash, 10),
 		saturatedCalls:     make(map[string]bool),
 		reportGenerator:    manager.ReportGeneratorCache(cfg),
+		dictionary:         dict,
 	}
 	if *flagDebug {
 		mgr.cfg.Procs = 1
 	}
+	if cfg.Experimental.HintsCache {
+		mgr.hintsCache = loadHintsCache(cfg.Workdir)
+	}
 	mgr.http = &manager.HTTPServer{
 		// Note that if cfg.HTTP == "", we don't start the server.
 		Cfg:        cfg,
@@ -505,7 +544,7 @@ func reportReproError(err error) {
 }
 
 func (mgr *Manager) RunRepro(ctx context.Context, crash *manager.Crash) *manager.ReproResult {
-	res, stats, err := repro.Run(ctx, crash.Output, repro.Environment{
+	res, stats, err := repro.Run(ctx, mgr.reproLogs(crash), repro.Environment{
 		Config:   mgr.cfg,
 		Features: mgr.enabledFeatures,
 		Reporter: mgr.reporter,
@@ -538,6 +577,28 @@ func (mgr *Manager) RunRepro(ctx context.Context, crash *manager.Crash) *manager
 	return ret
 }
 
+// The max number of stored logs of the same crash passed to repro.
+const maxReproLogs = 10
+
+// reproLogs returns the crash log followed by other stored logs of the same crash.
+func (mgr *Manager) reproLogs(crash *manager.Crash) [][]byte {
+	logs := [][]byte{crash.Output}
+	if crash.FromHub || crash.FromDashboard {
+		return logs
+	}
+	stored, err := mgr.crashStore.CrashLogs(crash.Title, maxReproLogs)
+	if err != nil {
+		log.Logf(1, "failed to read crash logs of '%v': %v", crash.Title, err)
+		return logs
+	}
+	for _, data := range stored {
+		if !bytes.Equal(data, crash.Output) && len(logs) < maxReproLogs {
+			logs = append(logs, data)
+		}
+	}
+	return logs
+}
+
 func (mgr *Manager) processRepro(res *manager.ReproResult) {
 	if res.Err != nil {
 		reportReproError(res.Err)
@@ -562,6 +623,9 @@ func (mgr *Manager) preloadCorpus() {
 	}
 	mgr.fresh = info.Fresh
 	mgr.corpusDB = info.CorpusDB
+	mgr.seedStats = info.SeedStats
+	mgr.provenanceDB = info.ProvenanceDB
+	mgr.provenance = info.Provenance
 	mgr.corpusPreload <- info.Candidates
 }
 
@@ -607,7 +671,7 @@ func (mgr *Manager) fuzzerInstance(ctx context.Context, inst *vm.Instance, updIn
 	if rep != nil && rep.Executor != nil {
 		extraExecs = []report.ExecutorInfo{*rep.Executor}
 	}
-	lastExec, machineInfo := serv.ShutdownInstance(inst.Index(), rep != nil, extraExecs...)
+	lastExec, machineInfo, session := serv.ShutdownInstance(inst.Index(), rep != nil, extraExecs...)
 	if rep != nil {
 		rpcserver.PrependExecuting(rep, lastExec)
 		if len(vmInfo) != 0 {
@@ -618,8 +682,11 @@ func (mgr *Manager) fuzzerInstance(ctx context.Context, inst *vm.Instance, updIn
 	if err == nil && rep != nil {
 		mgr.crashes <- &manager.Crash{
 			InstanceIndex: inst.Index(),
+			SessionFile:   session,
 			Report:        rep,
 		}
+	} else if session != "" {
+		os.Remove(session)
 	}
 	if err != nil {
 		log.Logf(1, "VM %v: failed with error: %v", inst.Index(), err)
@@ -688,6 +755,10 @@ func (mgr *Manager) emailCrash(crash *manager.Crash) {
 }
 
 func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
+	if crash.SessionFile != "" {
+		// SaveCrash moves the file into the crash dir, otherwise it's not needed.
+		defer os.Remove(crash.SessionFile)
+	}
 	if err := mgr.reporter.Symbolize(crash.Report); err != nil {
 		log.Errorf("failed to symbolize report: %v", err)
 	}
@@ -980,6 +1051,10 @@ func (mgr *Manager) corpusInputHandler(updates <-chan corpus.NewItemEvent) {
 		if err := mgr.corpusDB.Flush(); err != nil {
 			log.Errorf("failed to save corpus database: %v", err)
 		}
+		manager.SaveProvenance(mgr.provenanceDB, update.Sig, update.Provenance)
+		if err := mgr.provenanceDB.Flush(); err != nil {
+			log.Errorf("failed to save provenance database: %v", err)
+		}
 		mgr.corpusDBMu.Unlock()
 	}
 }
@@ -991,6 +1066,10 @@ func (mgr *Manager) getMinimizedCorpus() []*corpus.Item {
 	return mgr.corpus.Items()
 }
 
+func (mgr *Manager) getCorpusDigest() signal.Digest {
+	return mgr.corpus.Signal().Digest()
+}
+
 func (mgr *Manager) getNewRepros() [][]byte {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
@@ -1076,6 +1155,14 @@ func (mgr *Manager) minimizeCorpusLocked() {
 		log.Fatalf("failed to save corpus database: %v", err)
 	}
 	mgr.corpusDB.BumpVersion(manager.CurrentDBVersion)
+	for key := range mgr.provenanceDB.Records {
+		if _, ok := mgr.corpusDB.Records[key]; !ok {
+			mgr.provenanceDB.Delete(key)
+		}
+	}
+	if err := mgr.provenanceDB.Flush(); err != nil {
+		log.Errorf("failed to save provenance database: %v", err)
+	}
 }
 
 func setGuiltyFiles(crash *dashapi.Crash, report *report.Report) {
@@ -1124,23 +1211,35 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 	opts := fuzzer.DefaultExecOpts(mgr.cfg, features, *flagDebug)
 
 	switch mgr.mode {
-	case ModeFuzzing, ModeCorpusTriage:
+	case ModeFuzzing, ModeCorpusTriage, ModeDirected:
 		corpusUpdates := make(chan corpus.NewItemEvent, 128)
-		mgr.corpus = corpus.NewFocusedCorpus(context.Background(),
-			corpusUpdates, mgr.coverFilters.Areas)
+		mgr.corpus = corpus.NewDirectedCorpus(context.Background(),
+			corpusUpdates, mgr.coverFilters.Areas, mgr.directed)
+		mgr.corpus.RestoreSeedStats(mgr.seedStats)
+		mgr.seedStats = nil
+		mgr.corpus.RestoreProvenance(mgr.provenance)
+		mgr.provenance = nil
 		mgr.http.Corpus.Store(mgr.corpus)
 
 		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+		var callModel *prog.CallModel
+		if mgr.cfg.Experimental.CallModel {
+			callModel = prog.NewCallModel(mgr.target)
+		}
 		fuzzerObj := fuzzer.NewFuzzer(context.Background(), &fuzzer.Config{
-			Corpus:         mgr.corpus,
-			Snapshot:       mgr.cfg.Snapshot,
-			Coverage:       mgr.cfg.Cover,
-			FaultInjection: features&flatrpc.FeatureFault != 0,
-			Comparisons:    features&flatrpc.FeatureComparisons != 0,
-			Collide:        true,
-			EnabledCalls:   enabledSyscalls,
-			NoMutateCalls:  mgr.cfg.NoMutateCalls,
-			FetchRawCover:  mgr.cfg.RawCover,
+			Corpus:           mgr.corpus,
+			Snapshot:         mgr.cfg.Snapshot,
+			Coverage:         mgr.cfg.Cover,
+			FaultInjection:   features&flatrpc.FeatureFault != 0,
+			Comparisons:      features&flatrpc.FeatureComparisons != 0,
+			Collide:          true,
+			EnabledCalls:     enabledSyscalls,
+			NoMutateCalls:    mgr.cfg.NoMutateCalls,
+			FetchRawCover:    mgr.cfg.RawCover,
+			SchedulingPolicy: mgr.cfg.Experimental.SchedulingPolicy,
+			Dictionary:       mgr.dictionary,
+			HintsCache:       mgr.hintsCache,
+			CallModel:        callModel,
 			Logf: func(level int, msg string, args ...interface{}) {
 				if level != 0 {
 					return
@@ -1159,7 +1258,13 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 
 		go mgr.corpusInputHandler(corpusUpdates)
 		go mgr.corpusMinimization()
+		if mgr.hintsCache != nil {
+			go mgr.hintsCacheLoop(vm.ShutdownCtx())
+		}
 		go mgr.fuzzerLoop(fuzzerObj)
+		if mgr.mode == ModeDirected {
+			go mgr.directedLoop(vm.ShutdownCtx())
+		}
 		if mgr.dash != nil {
 			go mgr.dashboardReporter()
 			if mgr.cfg.Reproduce {
@@ -1255,6 +1360,100 @@ func (mgr *Manager) corpusMinimization() {
 		mgr.mu.Lock()
 		mgr.minimizeCorpusLocked()
 		mgr.mu.Unlock()
+		mgr.saveSeedStats()
+	}
+}
+
+const hintsCacheFile = "hints-cache.json"
+
+// hintsCacheLoop periodically persists the hints cache and saves it once more on shutdown.
+func (mgr *Manager) hintsCacheLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			mgr.saveHintsCache()
+		case <-ctx.Done():
+			mgr.saveHintsCache()
+			return
+		}
+	}
+}
+
+// loadHintsCache loads comparison operands collected by hints jobs before the restart.
+func loadHintsCache(workdir string) *prog.HintsCache {
+	cache := prog.NewHintsCache()
+	data, err := os.ReadFile(filepath.Join(workdir, hintsCacheFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("failed to read hints cache: %v", err)
+		}
+		return cache
+	}
+	if err := cache.Deserialize(data); err != nil {
+		log.Errorf("%v", err)
+		return prog.NewHintsCache()
+	}
+	log.Logf(0, "loaded %v cached hints", cache.Len())
+	return cache
+}
+
+func (mgr *Manager) saveHintsCache() {
+	version := mgr.hintsCache.Version()
+	if version == mgr.hintsCacheSaved {
+		return
+	}
+	file := filepath.Join(mgr.cfg.Workdir, hintsCacheFile)
+	if err := osutil.WriteFile(file, mgr.hintsCache.Serialize()); err != nil {
+		log.Errorf("failed to save hints cache: %v", err)
+		return
+	}
+	mgr.hintsCacheSaved = version
+}
+
+// saveSeedStats persists seed scheduling stats of corpus programs,
+// so that restarts don't reset the schedule.
+func (mgr *Manager) saveSeedStats() {
+	items := mgr.corpus.Items()
+	mgr.corpusDBMu.Lock()
+	defer mgr.corpusDBMu.Unlock()
+	if manager.SaveSeedStats(mgr.corpusDB, items) == 0 {
+		return
+	}
+	if err := mgr.corpusDB.Flush(); err != nil {
+		log.Errorf("failed to save corpus database: %v", err)
+	}
+}
+
+// directedLoop saves programs that reach directed fuzzing targets and exits once all targets are reached.
+func (mgr *Manager) directedLoop(ctx context.Context) {
+	saved := make(map[string]bool)
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		reached := mgr.corpus.DirectedTargets()
+		for target, item := range reached {
+			if saved[target] {
+				continue
+			}
+			saved[target] = true
+			file, err := manager.SaveDirectedProg(mgr.cfg.Workdir, target, item.Prog.Serialize())
+			if err != nil {
+				log.Errorf("%v", err)
+				continue
+			}
+			log.Logf(0, "reached directed target %v with program %v, saved to %v", target, item.Sig, file)
+		}
+		if len(reached) == len(mgr.directed.Targets) {
+			mgr.exit("directed fuzzing")
+			return
+		}
 	}
 }
 
@@ -1295,6 +1494,9 @@ func (mgr *Manager) fuzzerLoop(fuzzer *fuzzer.Fuzzer) {
 						fuzzer.Config.EnabledCalls)
 				} else {
 					mgr.setPhaseLocked(phaseTriagedHub)
+					if len(mgr.cfg.Peers) != 0 || mgr.cfg.PeersAddr != "" {
+						go mgr.peersSyncLoop(fuzzer.Config.EnabledCalls)
+					}
 				}
 			case phaseQueriedHub:
 				mgr.setPhaseLocked(phaseTriagedHub)
@@ -1450,6 +1652,12 @@ func (mgr *Manager) CoverageFilter(modules []*vminfo.KernelModule) ([]uint64, er
 		return nil, fmt.Errorf("failed to init coverage filter: %w", err)
 	}
 	mgr.coverFilters = filters
+	if mgr.mode == ModeDirected {
+		mgr.directed, err = manager.PrepareDirected(mgr.reportGenerator, mgr.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init directed fuzzing: %w", err)
+		}
+	}
 	mgr.http.Cover.Store(&manager.CoverageInfo{
 		Modules:         modules,
 		ReportGenerator: mgr.reportGenerator,
@@ -0,0 +1,54 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/vm"
+)
+
+const peersSyncPeriod = time.Minute
+
+func (mgr *Manager) peersSyncLoop(enabledSyscalls map[*prog.Syscall]bool) {
+	peers, err := manager.NewPeers(manager.PeersConfig{
+		Name:         mgr.cfg.Name,
+		Domain:       mgr.cfg.TargetOS + "/" + mgr.cfg.HubDomain,
+		Addr:         mgr.cfg.PeersAddr,
+		Peers:        mgr.cfg.Peers,
+		Key:          mgr.cfg.PeersKey,
+		Target:       mgr.target,
+		EnabledCalls: enabledSyscalls,
+		SendCorpus:   !mgr.cfg.Experimental.ResetAccState && mgr.cfg.Cover,
+		Leak:         mgr.enabledFeatures&flatrpc.FeatureLeak != 0,
+	}, mgr)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := peers.Loop(vm.ShutdownCtx(), peersSyncPeriod); err != nil {
+		log.Errorf("peers sync failed: %v", err)
+	}
+}
+
+func (mgr *Manager) PeersCorpus() []*corpus.Item {
+	return mgr.getMinimizedCorpus()
+}
+
+func (mgr *Manager) PeersRepros() [][]byte {
+	return mgr.getNewRepros()
+}
+
+func (mgr *Manager) AddPeersCandidates(candidates []fuzzer.Candidate) {
+	mgr.addNewCandidates(candidates)
+}
+
+func (mgr *Manager) AddPeersRepro(crash *manager.Crash) {
+	mgr.externalReproQueue <- crash
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-callmodel trains the n-gram call sequence model (prog.CallModel) on a corpus
+// and shows the most likely next calls for the most frequent call contexts.
+// The model is used by the fuzzer if experimental call_model config option is enabled.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/prog"
+)
+
+var (
+	flagOS       = flag.String("os", runtime.GOOS, "target os")
+	flagArch     = flag.String("arch", runtime.GOARCH, "target arch")
+	flagCorpus   = flag.String("corpus", "", "name of the corpus file")
+	flagPrev     = flag.String("prev", "", "comma-separated preceding calls to show (all if empty)")
+	flagContexts = flag.Int("contexts", 50, "number of most frequent contexts to show")
+	flagNext     = flag.Int("next", 5, "number of most likely next calls to show for each context")
+)
+
+func main() {
+	flag.Parse()
+	target, err := prog.GetTarget(*flagOS, *flagArch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	corpus, err := db.ReadCorpus(*flagCorpus, target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read corpus: %v\n", err)
+		os.Exit(1)
+	}
+	model := prog.NewCallModel(target)
+	for _, p := range corpus {
+		model.Train(p)
+	}
+	contexts := model.Contexts()
+	if *flagPrev != "" {
+		contexts = filterContexts(contexts, strings.Split(*flagPrev, ","))
+	} else if len(contexts) > *flagContexts {
+		contexts = contexts[:*flagContexts]
+	}
+	fmt.Printf("trained on %v programs\n", model.Len())
+	showContexts(contexts)
+}
+
+func filterContexts(contexts []prog.CallContext, prev []string) []prog.CallContext {
+	var res []prog.CallContext
+	for _, ctx := range contexts {
+		if strings.Join(callNames(ctx.Prev), ",") == strings.Join(prev, ",") {
+			res = append(res, ctx)
+		}
+	}
+	return res
+}
+
+func showContexts(contexts []prog.CallContext) {
+	header := []string{"PREV", "TOTAL"}
+	for i := 0; i < *flagNext; i++ {
+		header = append(header, fmt.Sprintf("NEXT%v", i+1))
+	}
+	printLine(header)
+	for _, ctx := range contexts {
+		line := []string{strings.Join(callNames(ctx.Prev), ","), fmt.Sprint(ctx.Total)}
+		for i, next := range ctx.Next {
+			if i == *flagNext {
+				break
+			}
+			line = append(line, fmt.Sprintf("%v %.1f%%", next.Call.Name,
+				float64(next.Count)*100/float64(ctx.Total)))
+		}
+		printLine(line)
+	}
+}
+
+func callNames(calls []*prog.Syscall) []string {
+	var res []string
+	for _, call := range calls {
+		res = append(res, call.Name)
+	}
+	return res
+}
+
+func printLine(values []string) {
+	fmt.Printf("|")
+	for _, val := range values {
+		fmt.Printf("%-30v|", val)
+	}
+	fmt.Printf("\n")
+}
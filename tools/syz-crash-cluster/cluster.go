@@ -0,0 +1,301 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-crash-cluster groups crash reports into likely-same-root-cause clusters based on similarity
+// of their stack traces (see report.Similarity) and prints suggested merges and splits of bugs.
+// It accepts manager workdirs (reports are taken from the crashes/ dir) and
+// syz-db-export output dirs (exported with -reports flag).
+//
+// A merge is suggested if reports of several bugs (crash dirs) are in the same cluster,
+// a split is suggested if reports of a single bug are in several clusters.
+//
+// Usage:
+//
+//	syz-crash-cluster -config manager.cfg [-threshold 0.7] workdir|export-dir...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/dashboard/api"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/tool"
+	_ "github.com/google/syzkaller/sys"
+)
+
+var (
+	flagOS        = flag.String("os", runtime.GOOS, "target os")
+	flagArch      = flag.String("arch", runtime.GOARCH, "target arch")
+	flagConfig    = flag.String("config", "", "optional manager configuration file (overrides -os/-arch)")
+	flagThreshold = flag.Float64("threshold", 0.7, "minimal similarity of reports in a cluster")
+	flagAll       = flag.Bool("all", false, "print all clusters, not only suggested merges/splits")
+)
+
+func main() {
+	flag.Parse()
+	if len(flag.Args()) == 0 {
+		fmt.Fprintf(os.Stderr, "usage: syz-crash-cluster [flags] workdir|export-dir...\n")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	var err error
+	cfg := &mgrconfig.Config{}
+	if *flagConfig != "" {
+		cfg, err = mgrconfig.LoadPartialFile(*flagConfig)
+	} else {
+		cfg, err = mgrconfig.LoadPartialData([]byte(`{"target": "` + *flagOS + "/" + *flagArch + `"}`))
+	}
+	if err != nil {
+		tool.Fail(err)
+	}
+	cfg.CompleteKernelDirs()
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		tool.Failf("failed to create reporter: %v", err)
+	}
+	var items []*item
+	for _, dir := range flag.Args() {
+		dirItems, err := loadDir(reporter, dir)
+		if err != nil {
+			tool.Fail(err)
+		}
+		items = append(items, dirItems...)
+	}
+	log.Logf(0, "loaded %v reports", len(items))
+	clusters := cluster(items, *flagThreshold)
+	printClusters(os.Stdout, clusters, *flagAll)
+}
+
+// item is a single crash report.
+type item struct {
+	// bug is the crash dir or the exported bug the report belongs to.
+	bug   string
+	title string
+	file  string
+	rep   *report.Report
+}
+
+func loadDir(reporter *report.Reporter, dir string) ([]*item, error) {
+	if osutil.IsExist(filepath.Join(dir, "crashes")) {
+		return loadCrashes(reporter, filepath.Join(dir, "crashes"))
+	}
+	if osutil.IsExist(filepath.Join(dir, "bugs")) {
+		return loadExport(reporter, filepath.Join(dir, "bugs"))
+	}
+	return nil, fmt.Errorf("%v is neither a manager workdir nor a syz-db-export dir", dir)
+}
+
+func loadCrashes(reporter *report.Reporter, dir string) ([]*item, error) {
+	crashes, err := osutil.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var items []*item
+	for _, crash := range crashes {
+		crashDir := filepath.Join(dir, crash)
+		files, err := osutil.ListDir(crashDir)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := os.ReadFile(filepath.Join(crashDir, "description"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			// Reports are already extracted and symbolized, logs are used only if there is no report.
+			var index int
+			if n, _ := fmt.Sscanf(file, "log%d", &index); n != 1 || file != fmt.Sprintf("log%v", index) {
+				continue
+			}
+			item := &item{
+				bug:   filepath.Join("crashes", crash),
+				title: strings.TrimSpace(string(desc)),
+			}
+			reportFile := filepath.Join(crashDir, fmt.Sprintf("report%v", index))
+			if osutil.IsExist(reportFile) {
+				item.file = reportFile
+				item.rep, err = parseFile(reporter, reportFile, false)
+			} else {
+				item.file = filepath.Join(crashDir, file)
+				item.rep, err = parseFile(reporter, item.file, true)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if item.rep != nil {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+func loadExport(reporter *report.Reporter, dir string) ([]*item, error) {
+	bugs, err := osutil.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var items []*item
+	for _, id := range bugs {
+		reportFile := filepath.Join(dir, id, "report.txt")
+		if !osutil.IsExist(reportFile) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, id, "details.json"))
+		if err != nil {
+			return nil, err
+		}
+		bug := new(api.Bug)
+		if err := json.Unmarshal(data, bug); err != nil {
+			return nil, fmt.Errorf("failed to parse %v details: %w", id, err)
+		}
+		rep, err := parseFile(reporter, reportFile, false)
+		if err != nil {
+			return nil, err
+		}
+		if rep == nil {
+			continue
+		}
+		items = append(items, &item{
+			bug:   filepath.Join("bugs", id),
+			title: bug.Title,
+			file:  reportFile,
+			rep:   rep,
+		})
+	}
+	return items, nil
+}
+
+func parseFile(reporter *report.Reporter, file string, symbolize bool) (*report.Report, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rep := reporter.Parse(data)
+	if rep == nil {
+		log.Logf(0, "%v: no crash report found", file)
+		return nil, nil
+	}
+	if symbolize {
+		if err := reporter.Symbolize(rep); err != nil {
+			return nil, fmt.Errorf("failed to symbolize %v: %w", file, err)
+		}
+	}
+	return rep, nil
+}
+
+// cluster does single-linkage clustering of the items:
+// items with similarity >= threshold end up in the same cluster.
+func cluster(items []*item, threshold float64) [][]*item {
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if find(i) == find(j) {
+				continue
+			}
+			if report.Similarity(items[i].rep, items[j].rep) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+	groups := make(map[int][]*item)
+	for i, item := range items {
+		root := find(i)
+		groups[root] = append(groups[root], item)
+	}
+	var clusters [][]*item
+	for _, group := range groups {
+		clusters = append(clusters, group)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+		return clusters[i][0].file < clusters[j][0].file
+	})
+	return clusters
+}
+
+func printClusters(w io.Writer, clusters [][]*item, all bool) {
+	// Which clusters reports of each bug ended up in.
+	bugClusters := make(map[string][]int)
+	titles := make(map[string]string)
+	for i, cluster := range clusters {
+		for _, item := range cluster {
+			titles[item.bug] = item.title
+			if ids := bugClusters[item.bug]; len(ids) == 0 || ids[len(ids)-1] != i {
+				bugClusters[item.bug] = append(ids, i)
+			}
+		}
+	}
+	fmt.Fprintf(w, "SUGGESTED MERGES:\n\n")
+	for i, cluster := range clusters {
+		bugs := clusterBugs(cluster)
+		if len(bugs) < 2 && !all {
+			continue
+		}
+		fmt.Fprintf(w, "cluster #%v (%v reports):\n", i, len(cluster))
+		if sig := report.StackSignature(cluster[0].rep); len(sig) != 0 {
+			fmt.Fprintf(w, "  stack: %v\n", strings.Join(sig, " <- "))
+		}
+		for _, bug := range bugs {
+			fmt.Fprintf(w, "  %-30v %v\n", bug, titles[bug])
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	fmt.Fprintf(w, "SUGGESTED SPLITS:\n\n")
+	var bugs []string
+	for bug, ids := range bugClusters {
+		if len(ids) > 1 {
+			bugs = append(bugs, bug)
+		}
+	}
+	sort.Strings(bugs)
+	for _, bug := range bugs {
+		fmt.Fprintf(w, "%v: %v\n", bug, titles[bug])
+		for _, id := range bugClusters[bug] {
+			var files []string
+			for _, item := range clusters[id] {
+				if item.bug == bug {
+					files = append(files, filepath.Base(item.file))
+				}
+			}
+			fmt.Fprintf(w, "  %v\n", strings.Join(files, " "))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+func clusterBugs(cluster []*item) []string {
+	var bugs []string
+	dedup := make(map[string]bool)
+	for _, item := range cluster {
+		if !dedup[item.bug] {
+			dedup[item.bug] = true
+			bugs = append(bugs, item.bug)
+		}
+	}
+	sort.Strings(bugs)
+	return bugs
+}
@@ -25,6 +25,7 @@ var (
 		"usage example: ./tools/syz-db-export -namespace upstream -token $(gcloud auth print-access-token)")
 	flagParallel = flag.Int("j", 2, "number of parallel threads")
 	flagVerbose  = flag.Bool("v", false, "verbose output")
+	flagReports  = flag.Bool("reports", false, "also export the first crash report for every bug")
 )
 
 func main() {
@@ -64,6 +65,15 @@ func exportNamespace() error {
 				if err := saveBug(bug); err != nil {
 					return fmt.Errorf("saveBug(bugID=%s): %w", bug.ID, err)
 				}
+				if reportURL := bug.Crashes[0].CrashReportLink; *flagReports && reportURL != "" {
+					report, err := cli.Text(reportURL)
+					if err != nil {
+						return err
+					}
+					if err := saveReport(bug.ID, report); err != nil {
+						return fmt.Errorf("saveReport(bugID=%s): %w", bug.ID, err)
+					}
+				}
 				cReproURL := bug.Crashes[0].CReproducerLink // export max 1 CRepro per bug
 				if cReproURL == "" {
 					continue
@@ -108,6 +118,15 @@ func saveCRepro(bugID, reproID string, reproData []byte) error {
 	return nil
 }
 
+// saveReport assumes the bug dir already exists.
+func saveReport(bugID string, report []byte) error {
+	reportPath := path.Join(*flagOutputDir, "bugs", bugID, "report.txt")
+	if err := os.WriteFile(reportPath, report, 0666); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
+
 func reproIDFromURL(url string) string {
 	parts := strings.Split(url, "&")
 	if len(parts) != 2 {
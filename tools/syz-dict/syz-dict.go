@@ -0,0 +1,47 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-dict extracts a dictionary of interesting values (string literals, magic constants,
+// enum values) from a kernel binary. The dictionary can be passed to syz-manager
+// with the "dictionary" experimental config parameter.
+//
+// Usage:
+//
+//	syz-dict -os linux -arch amd64 -vmlinux vmlinux -output vmlinux.dict
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+
+	"github.com/google/syzkaller/pkg/dictionary"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var (
+	flagOS      = flag.String("os", runtime.GOOS, "target os")
+	flagArch    = flag.String("arch", runtime.GOARCH, "target arch")
+	flagVmlinux = flag.String("vmlinux", "", "kernel object file to extract the dictionary from")
+	flagOutput  = flag.String("output", "", "output dictionary file")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagVmlinux == "" || *flagOutput == "" {
+		tool.Failf("both -vmlinux and -output flags are required")
+	}
+	target := targets.Get(*flagOS, *flagArch)
+	if target == nil {
+		tool.Failf("unknown target %v/%v", *flagOS, *flagArch)
+	}
+	dict, err := dictionary.Extract(target, *flagVmlinux)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if err := dictionary.Save(*flagOutput, dict); err != nil {
+		tool.Fail(err)
+	}
+	fmt.Printf("extracted %v strings and %v integers\n", len(dict.Strings), len(dict.Ints))
+}
@@ -19,6 +19,9 @@ var (
 	flagNewConfig  = flag.String("new", "", "new config (treated as the main one)")
 	flagDebug      = flag.Bool("debug", false, "dump all VM output to console")
 	flagPatch      = flag.String("patch", "", "a git patch")
+	flagExec       = flag.Bool("exec", false, "compare behavior of syscalls on the base and new kernels"+
+		" instead of patch fuzzing (mismatches are saved to the new kernel workdir)")
+	flagOutput = flag.Bool("compare_output", false, "in the -exec mode compare program output as well")
 )
 
 func main() {
@@ -47,6 +50,17 @@ func main() {
 	}
 
 	ctx := vm.ShutdownCtx()
+	if *flagExec {
+		err = manager.RunDifferential(ctx, []*mgrconfig.Config{baseCfg, newCfg}, manager.DifferentialConfig{
+			Debug:         *flagDebug,
+			CompareOutput: *flagOutput,
+			Store:         manager.NewCrashStore(newCfg),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	err = manager.RunDiffFuzzer(ctx, baseCfg, newCfg, manager.DiffFuzzerConfig{
 		Store: &manager.DiffFuzzerStore{BasePath: newCfg.Workdir},
 		Debug: *flagDebug,
@@ -0,0 +1,238 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-replay replays executor sessions recorded by syz-manager with "record_sessions" experimental
+// config option (see pkg/rpcserver/session.go). The programs are executed in the same order
+// on the same procs and with the same timing as they were executed originally.
+// Similar to syz-execprog, the tool needs to be copied to a freshly booted VM along with syz-executor
+// and the session files:
+//
+//	syz-replay -executor=./syz-executor workdir/sessions/vm3.jsonl.1 workdir/sessions/vm3.jsonl
+//
+// Sessions of crashed VMs are saved into the crash dirs as sessionN.jsonl along with logN.
+//
+// Alternatively, with -dump flag the tool prints the programs in the execution log format,
+// which can be passed to syz-execprog or syz-repro.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/rpcserver"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+)
+
+var (
+	flagOS       = flag.String("os", runtime.GOOS, "target os")
+	flagArch     = flag.String("arch", runtime.GOARCH, "target arch")
+	flagType     = flag.String("type", "", "target VM type")
+	flagExecutor = flag.String("executor", "./syz-executor", "path to executor binary")
+	flagSandbox  = flag.String("sandbox", "none", "sandbox for fuzzing (none/setuid/namespace/android)")
+	flagSpeed    = flag.Float64("speed", 1, "replay speed relative to the original session (0 - as fast as possible)")
+	flagStart    = flag.Duration("start", 0, "skip executions that started before this time in the session")
+	flagDebug    = flag.Bool("debug", false, "debug output from executor")
+	flagDump     = flag.Bool("dump", false, "print programs in the execution log format instead of executing")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: syz-replay [flags] session-file+\n")
+		fmt.Fprintf(os.Stderr, "session files must be given in the chronological order (e.g. vm0.jsonl.1 vm0.jsonl)\n")
+		flag.PrintDefaults()
+	}
+	defer tool.Init()()
+	if len(flag.Args()) == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	target, err := prog.GetTarget(*flagOS, *flagArch)
+	if err != nil {
+		tool.Fail(err)
+	}
+	events, err := rpcserver.ReadSession(flag.Args()...)
+	if err != nil {
+		tool.Fail(err)
+	}
+	replay, err := newReplay(target, rpcserver.SessionExecs(events))
+	if err != nil {
+		tool.Fail(err)
+	}
+	if len(replay.execs) == 0 {
+		tool.Failf("no executions in the session")
+	}
+	if *flagDump {
+		replay.dump()
+		return
+	}
+	sandbox, err := flatrpc.SandboxToFlags(*flagSandbox)
+	if err != nil {
+		tool.Failf("failed to parse sandbox: %v", err)
+	}
+	log.Logf(0, "replaying %v executions on %v procs", len(replay.execs), replay.procs)
+	ctx, done := context.WithCancel(context.Background())
+	replay.done = done
+	cfg := &rpcserver.LocalConfig{
+		Config: rpcserver.Config{
+			Config: vminfo.Config{
+				Target:     target,
+				VMType:     *flagType,
+				Features:   flatrpc.AllFeatures,
+				Debug:      *flagDebug,
+				Sandbox:    sandbox,
+				SandboxArg: replay.execs[0].Request.ExecOpts.SandboxArg,
+			},
+			Procs:    replay.procs,
+			Slowdown: 1,
+		},
+		Executor:         *flagExecutor,
+		HandleInterrupts: true,
+		MachineChecked: func(features flatrpc.Feature, syscalls map[*prog.Syscall]bool) queue.Source {
+			return replay
+		},
+	}
+	if err := rpcserver.RunLocal(ctx, cfg); err != nil {
+		tool.Fail(err)
+	}
+	log.Logf(0, "replayed %v executions, %v diverged from the recorded results",
+		replay.completed.Load(), replay.diverged.Load())
+}
+
+type replayExec struct {
+	*rpcserver.SessionExec
+	prog *prog.Prog
+}
+
+type replay struct {
+	execs     []*replayExec
+	procs     int
+	done      func()
+	mu        sync.Mutex
+	pos       int
+	start     time.Time
+	completed atomic.Int64
+	diverged  atomic.Int64
+}
+
+func newReplay(target *prog.Target, execs []*rpcserver.SessionExec) (*replay, error) {
+	r := &replay{
+		procs: 1,
+	}
+	for _, exec := range execs {
+		if exec.Time < *flagStart {
+			continue
+		}
+		req := exec.Request
+		re := &replayExec{SessionExec: exec}
+		switch req.Type {
+		case flatrpc.RequestTypeProgram:
+			p, err := target.Deserialize([]byte(req.Prog), prog.NonStrict)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse program %v: %w", req.ID, err)
+			}
+			re.prog = p
+		case flatrpc.RequestTypeGlob:
+		default:
+			log.Logf(0, "skipping request %v of type %v", req.ID, req.Type)
+			continue
+		}
+		if req.ExecOpts == nil {
+			return nil, fmt.Errorf("request %v has no exec opts", req.ID)
+		}
+		r.procs = max(r.procs, exec.Proc+1)
+		r.execs = append(r.execs, re)
+	}
+	return r, nil
+}
+
+func (r *replay) Next() *queue.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pos >= len(r.execs) {
+		return nil
+	}
+	exec := r.execs[r.pos]
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+	if *flagSpeed > 0 {
+		elapsed := time.Duration(float64(time.Since(r.start)) * *flagSpeed)
+		if elapsed < exec.Time-r.execs[0].Time {
+			return nil
+		}
+	}
+	r.pos++
+	orig := exec.Request
+	req := &queue.Request{
+		Type:         orig.Type,
+		ExecOpts:     *orig.ExecOpts,
+		Prog:         exec.prog,
+		GlobPattern:  orig.GlobPattern,
+		ReturnError:  orig.Flags&flatrpc.RequestFlagReturnError != 0,
+		ReturnOutput: orig.Flags&flatrpc.RequestFlagReturnOutput != 0,
+	}
+	for _, call := range orig.AllSignal {
+		req.ReturnAllSignal = append(req.ReturnAllSignal, int(call))
+	}
+	// Pin the request to the original proc (the local runner has VM ID 0).
+	for proc := 0; proc < r.procs; proc++ {
+		if proc != exec.Proc {
+			req.Avoid = append(req.Avoid, queue.ExecutorID{VM: 0, Proc: proc})
+		}
+	}
+	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
+		r.compare(exec, res)
+		if int(r.completed.Add(1)) == len(r.execs) {
+			r.done()
+		}
+		return true
+	})
+	return req
+}
+
+func (r *replay) compare(exec *replayExec, res *queue.Result) {
+	orig := exec.Result
+	if orig == nil {
+		log.Logf(0, "request %v has not finished in the original session, now: %v %v",
+			exec.Request.ID, res.Status, res.Err)
+		return
+	}
+	diff := ""
+	if orig.Status != res.Status {
+		diff = fmt.Sprintf("status %v -> %v", orig.Status, res.Status)
+	} else if res.Info != nil {
+		for i, call := range res.Info.Calls {
+			if i < len(orig.Calls) && orig.Calls[i].Error != call.Error {
+				diff = fmt.Sprintf("call %v errno %v -> %v", i, orig.Calls[i].Error, call.Error)
+				break
+			}
+		}
+	}
+	if diff == "" {
+		return
+	}
+	r.diverged.Add(1)
+	log.Logf(1, "request %v on proc %v diverged: %v", exec.Request.ID, exec.Proc, diff)
+}
+
+func (r *replay) dump() {
+	for _, exec := range r.execs {
+		if exec.prog == nil {
+			continue
+		}
+		fmt.Printf("%v: executing program %v (id=%v):\n%s\n",
+			exec.Time, exec.Proc, exec.Request.ID, exec.prog.Serialize())
+	}
+}
@@ -33,17 +33,20 @@ var (
 func main() {
 	os.Args = append(append([]string{}, os.Args[0], "-vv=10"), os.Args[1:]...)
 	flag.Parse()
-	if len(flag.Args()) != 1 || *flagConfig == "" {
-		log.Fatalf("usage: syz-repro -config=manager.cfg execution.log")
+	if len(flag.Args()) == 0 || *flagConfig == "" {
+		log.Fatalf("usage: syz-repro -config=manager.cfg execution.log [other logs of the same crash...]")
 	}
 	cfg, err := mgrconfig.LoadFile(*flagConfig)
 	if err != nil {
 		log.Fatalf("%v: %v", *flagConfig, err)
 	}
-	logFile := flag.Args()[0]
-	data, err := os.ReadFile(logFile)
-	if err != nil {
-		log.Fatalf("failed to open log file %v: %v", logFile, err)
+	var logs [][]byte
+	for _, logFile := range flag.Args() {
+		data, err := os.ReadFile(logFile)
+		if err != nil {
+			log.Fatalf("failed to open log file %v: %v", logFile, err)
+		}
+		logs = append(logs, data)
 	}
 	vmPool, err := vm.Create(cfg, *flagDebug)
 	if err != nil {
@@ -67,7 +70,7 @@ func main() {
 	go func() {
 		defer done()
 
-		res, stats, err := repro.Run(ctx, data, repro.Environment{
+		res, stats, err := repro.Run(ctx, logs, repro.Environment{
 			Config:   cfg,
 			Features: flatrpc.AllFeatures,
 			Reporter: reporter,
@@ -79,6 +82,7 @@ func main() {
 		if stats != nil {
 			fmt.Printf("extracting prog: %v\n", stats.ExtractProgTime)
 			fmt.Printf("minimizing prog: %v\n", stats.MinimizeProgTime)
+			fmt.Printf("searching call schedule: %v\n", stats.SearchScheduleTime)
 			fmt.Printf("simplifying prog options: %v\n", stats.SimplifyProgTime)
 			fmt.Printf("extracting C: %v\n", stats.ExtractCTime)
 			fmt.Printf("simplifying C: %v\n", stats.SimplifyCTime)
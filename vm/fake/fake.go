@@ -0,0 +1,151 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package fake implements an in-process fake VM type for testing of code that uses VMs.
+// The fake VM does not run anything, instead it invokes callbacks provided by the test.
+// It also supports the snapshot mode: the VM state is saved by SetupSnapshot
+// and is restored before each RunSnapshot, so that inputs can't affect each other.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+// Config describes behavior of a fake VM type.
+type Config struct {
+	// Number of VMs in the pool (1 if not set).
+	Count int
+	// Run is invoked for commands started with Instance.Run (optional).
+	// The output is sent to the console, and the command exits with the returned error.
+	Run func(inst *Instance, command string) (output []byte, err error)
+	// Setup is invoked by SetupSnapshot (optional). It can initialize the state
+	// that will be snapshotted.
+	Setup func(inst *Instance, state State, input []byte) error
+	// Exec executes an input in the snapshot mode. The state is restored from the snapshot
+	// before each invocation and can be freely modified.
+	Exec func(inst *Instance, state State, input []byte) (result, output []byte)
+}
+
+// State is the mutable state of a fake VM (an analog of the VM memory).
+type State map[string]int
+
+// Register registers a new fake VM type with the given behavior.
+func Register(typ string, cfg Config) {
+	if cfg.Count == 0 {
+		cfg.Count = 1
+	}
+	vmimpl.Register(typ, vmimpl.Type{
+		Ctor: func(env *vmimpl.Env) (vmimpl.Pool, error) {
+			return &pool{cfg: cfg, env: env}, nil
+		},
+		Overcommit: true,
+	})
+}
+
+type pool struct {
+	cfg Config
+	env *vmimpl.Env
+}
+
+func (pool *pool) Count() int {
+	return pool.cfg.Count
+}
+
+func (pool *pool) Create(workdir string, index int) (vmimpl.Instance, error) {
+	return &Instance{
+		Index: index,
+		cfg:   pool.cfg,
+		state: make(State),
+	}, nil
+}
+
+// Instance is a single fake VM.
+type Instance struct {
+	Index int
+
+	cfg      Config
+	mu       sync.Mutex
+	state    State
+	snapshot State
+	// Number of times the VM was restored from the snapshot.
+	restores int
+}
+
+var _ vmimpl.Snapshotter = (*Instance)(nil)
+
+func (inst *Instance) Copy(hostSrc string) (string, error) {
+	return filepath.Join("/", filepath.Base(hostSrc)), nil
+}
+
+func (inst *Instance) Forward(port int) (string, error) {
+	return fmt.Sprintf("localhost:%v", port), nil
+}
+
+func (inst *Instance) Run(ctx context.Context, command string) (<-chan []byte, <-chan error, error) {
+	outc := make(chan []byte, 1)
+	errc := make(chan error, 1)
+	var output []byte
+	var err error
+	if inst.cfg.Run != nil {
+		output, err = inst.cfg.Run(inst, command)
+	}
+	if len(output) != 0 {
+		outc <- output
+	}
+	close(outc)
+	errc <- err
+	return outc, errc, nil
+}
+
+func (inst *Instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	return nil, false
+}
+
+func (inst *Instance) Close() error {
+	return nil
+}
+
+func (inst *Instance) SetupSnapshot(input []byte) error {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.snapshot != nil {
+		return fmt.Errorf("fake: the snapshot is already set up")
+	}
+	if inst.cfg.Setup != nil {
+		if err := inst.cfg.Setup(inst, inst.state, input); err != nil {
+			return err
+		}
+	}
+	inst.snapshot = maps.Clone(inst.state)
+	return nil
+}
+
+func (inst *Instance) RunSnapshot(timeout time.Duration, input []byte) (result, output []byte, err error) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.snapshot == nil {
+		return nil, nil, fmt.Errorf("fake: the snapshot is not set up")
+	}
+	inst.state = maps.Clone(inst.snapshot)
+	inst.restores++
+	if inst.cfg.Exec == nil {
+		return nil, nil, nil
+	}
+	result, output = inst.cfg.Exec(inst, inst.state, input)
+	return result, output, nil
+}
+
+// Restores returns the number of times the VM was restored from the snapshot.
+func (inst *Instance) Restores() int {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	return inst.restores
+}
@@ -47,15 +47,17 @@ type Pool struct {
 }
 
 type instance struct {
-	cfg      *Config
-	image    string
-	debug    bool
-	rootDir  string
-	imageDir string
-	name     string
-	port     int
-	cmd      *exec.Cmd
-	merger   *vmimpl.OutputMerger
+	cfg       *Config
+	image     string
+	debug     bool
+	rootDir   string
+	imageDir  string
+	bundleDir string
+	name      string
+	port      int
+	cmd       *exec.Cmd
+	merger    *vmimpl.OutputMerger
+	*snapshot
 }
 
 func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
@@ -114,7 +116,15 @@ func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
 		cpus = uint64(runtime.NumCPU())
 	}
 	cpuLimit := cpuPeriod * cpus
-	vmConfig := fmt.Sprintf(configTempl, imageDir, caps, name, memoryLimit, cpuLimit, cpuPeriod)
+	var snap *snapshot
+	mounts := ""
+	if pool.env.Snapshot {
+		var err error
+		if snap, mounts, err = snapshotCreate(workdir, imageDir); err != nil {
+			return nil, err
+		}
+	}
+	vmConfig := fmt.Sprintf(configTempl, imageDir, caps, name, memoryLimit, cpuLimit, cpuPeriod, mounts)
 	if err := osutil.WriteFile(filepath.Join(bundleDir, "config.json"), []byte(vmConfig)); err != nil {
 		return nil, err
 	}
@@ -157,15 +167,25 @@ func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
 	merger := vmimpl.NewOutputMerger(tee)
 	merger.Add("runsc", rpipe)
 	merger.Add("runsc-goruntime", panicLogReadFD)
+	if snap != nil {
+		if err := snap.setupOutput(merger); err != nil {
+			wpipe.Close()
+			panicLogWriteFD.Close()
+			merger.Wait()
+			return nil, err
+		}
+	}
 
 	inst := &instance{
-		cfg:      pool.cfg,
-		image:    pool.env.Image,
-		debug:    pool.env.Debug,
-		rootDir:  rootDir,
-		imageDir: imageDir,
-		name:     name,
-		merger:   merger,
+		cfg:       pool.cfg,
+		image:     pool.env.Image,
+		debug:     pool.env.Debug,
+		rootDir:   rootDir,
+		imageDir:  imageDir,
+		bundleDir: bundleDir,
+		name:      name,
+		merger:    merger,
+		snapshot:  snap,
 	}
 
 	// Kill the previous instance in case it's still running.
@@ -269,6 +289,9 @@ func (inst *instance) Close() error {
 	time.Sleep(3 * time.Second)
 	osutil.Run(time.Minute, inst.runscCmd("delete", "-force", inst.name))
 	inst.cmd.Process.Kill()
+	if inst.snapshot != nil {
+		inst.snapshotClose()
+	}
 	inst.merger.Wait()
 	inst.cmd.Wait()
 	osutil.Run(time.Minute, inst.runscCmd("delete", "-force", inst.name))
@@ -297,13 +320,10 @@ func (inst *instance) Copy(hostSrc string) (string, error) {
 
 func (inst *instance) Run(ctx context.Context, command string) (
 	<-chan []byte, <-chan error, error) {
-	args := []string{"exec", "-user=0:0"}
-	for _, c := range sandboxCaps {
-		args = append(args, "-cap", c)
+	if inst.snapshot != nil {
+		return inst.runDetached(command)
 	}
-	args = append(args, inst.name)
-	args = append(args, strings.Split(command, " ")...)
-	cmd := inst.runscCmd(args...)
+	cmd := inst.execCmd(command)
 
 	rpipe, wpipe, err := osutil.LongPipe()
 	if err != nil {
@@ -366,6 +386,47 @@ func (inst *instance) Run(ctx context.Context, command string) (
 	return inst.merger.Output, errc, nil
 }
 
+func (inst *instance) execCmd(command string, add ...string) *exec.Cmd {
+	args := []string{"exec", "-user=0:0"}
+	for _, c := range sandboxCaps {
+		args = append(args, "-cap", c)
+	}
+	args = append(args, add...)
+	args = append(args, inst.name)
+	args = append(args, strings.Split(command, " ")...)
+	return inst.runscCmd(args...)
+}
+
+// runDetached starts the command in background and returns once it's started.
+// In the snapshot mode the sandbox is checkpointed and restored, so the only command
+// (the executor) must not be connected to any host processes. Its stdio is /dev/null,
+// console output is still collected from runsc debug logs.
+func (inst *instance) runDetached(command string) (<-chan []byte, <-chan error, error) {
+	cmd := inst.execCmd(detachedCommand(command), "-detach")
+	if err := cmd.Run(); err != nil {
+		return nil, nil, fmt.Errorf("runsc exec failed: %w", err)
+	}
+	outc := make(chan []byte)
+	close(outc)
+	errc := make(chan error, 1)
+	errc <- nil
+	return outc, errc, nil
+}
+
+// detachedCommand strips the shell syntax that other VM types use to start background commands
+// (e.g. "nohup cmd args 1>/dev/null </dev/null &"): there is no shell in the sandbox,
+// and detached commands don't have stdio anyway.
+func detachedCommand(command string) string {
+	var args []string
+	for i, arg := range strings.Fields(command) {
+		if i == 0 && arg == "nohup" || arg == "&" || strings.ContainsAny(arg, "<>") {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, " ")
+}
+
 func (inst *instance) guestProxy() (*os.File, error) {
 	if inst.port == 0 {
 		return nil, nil
@@ -427,7 +488,7 @@ const configTempl = `
 	"root": {
 		"path": "%[1]v",
 		"readonly": true
-	},
+	},%[7]v
 	"linux": {
 		"cgroupsPath": "%[3]v",
 		"resources": {
@@ -0,0 +1,181 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package gvisor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+// The snapshot mode is implemented with runsc checkpoint/restore.
+//
+// Inputs and results are passed via a host file that is bind-mounted into the sandbox
+// and is mapped by the executor instead of qemu ivshmem memory (see executor/snapshot.h).
+// Pages of bind-mounted files (with the default shared file access for mounts) are backed
+// by the host file and are not saved in the checkpoint image, so the executor sees the new input
+// written by the host after each restore. There is no doorbell, the host polls the state in the header.
+
+// Must be in sync with kSnapshotShmemFile in executor/snapshot.h.
+const snapshotShmemFile = "/syz-snapshot-shmem"
+
+type snapshot struct {
+	shmemFile     string
+	checkpointDir string
+	shmem         []byte
+	input         []byte
+	header        *flatrpc.SnapshotHeaderT
+	// Output of all restored sandboxes goes into the same merger source.
+	restoreOutput io.WriteCloser
+}
+
+var _ vmimpl.Snapshotter = (*instance)(nil)
+
+func snapshotCreate(workdir, imageDir string) (*snapshot, string, error) {
+	snap := &snapshot{
+		shmemFile:     filepath.Join(workdir, "snapshot.shmem"),
+		checkpointDir: filepath.Join(workdir, "checkpoint"),
+	}
+	osutil.MkdirAll(snap.checkpointDir)
+	if err := osutil.WriteFile(snap.shmemFile, nil); err != nil {
+		return nil, "", err
+	}
+	if err := os.Truncate(snap.shmemFile, int64(flatrpc.ConstSnapshotShmemSize)); err != nil {
+		return nil, "", err
+	}
+	// The root is read-only, so the mount point needs to exist in the image.
+	if err := osutil.WriteFile(filepath.Join(imageDir, snapshotShmemFile), nil); err != nil {
+		return nil, "", err
+	}
+	mounts := fmt.Sprintf(snapshotMountsTempl, snapshotShmemFile, snap.shmemFile)
+	return snap, mounts, nil
+}
+
+func (snap *snapshot) setupOutput(merger *vmimpl.OutputMerger) error {
+	rpipe, wpipe, err := osutil.LongPipe()
+	if err != nil {
+		return err
+	}
+	merger.Add("runsc-restore", rpipe)
+	snap.restoreOutput = wpipe
+	return nil
+}
+
+func (inst *instance) snapshotClose() {
+	if inst.restoreOutput != nil {
+		inst.restoreOutput.Close()
+	}
+	if inst.shmem != nil {
+		syscall.Munmap(inst.shmem)
+	}
+}
+
+func (inst *instance) SetupSnapshot(input []byte) error {
+	f, err := os.OpenFile(inst.shmemFile, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	shmem, err := syscall.Mmap(int(f.Fd()), 0, int(flatrpc.ConstSnapshotShmemSize),
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("gvisor: shmem mmap failed: %w", err)
+	}
+	inst.shmem = shmem
+	inst.input = shmem[:flatrpc.ConstMaxInputSize:flatrpc.ConstMaxInputSize]
+	inst.header = (*flatrpc.SnapshotHeaderT)(unsafe.Pointer(&shmem[flatrpc.ConstMaxInputSize]))
+
+	copy(inst.input, input)
+	// Tell executor that we are ready to snapshot and wait for an ack.
+	inst.header.UpdateState(flatrpc.SnapshotStateHandshake)
+	if !inst.waitSnapshotStateChange(flatrpc.SnapshotStateHandshake, 10*time.Minute) {
+		return fmt.Errorf("executor does not start snapshot handshake\n%s", inst.merger.ReadAvailable())
+	}
+	// The executor is spinning in the Ready state, the sandbox continues running after the checkpoint.
+	if out, err := osutil.Run(10*time.Minute, inst.checkpointCmd()); err != nil {
+		return fmt.Errorf("runsc checkpoint failed: %w\n%s", err, out)
+	}
+	inst.header.UpdateState(flatrpc.SnapshotStateSnapshotted)
+	if !inst.waitSnapshotStateChange(flatrpc.SnapshotStateSnapshotted, time.Minute) {
+		return fmt.Errorf("executor has not confirmed snapshot handshake\n%s", inst.merger.ReadAvailable())
+	}
+	return nil
+}
+
+func (inst *instance) RunSnapshot(timeout time.Duration, input []byte) (result, output []byte, err error) {
+	copy(inst.input, input)
+	inst.header.OutputOffset = 0
+	inst.header.OutputSize = 0
+	inst.header.UpdateState(flatrpc.SnapshotStateExecute)
+	if err := inst.restore(); err != nil {
+		return nil, nil, fmt.Errorf("%w\n%s", err, inst.merger.ReadAvailable())
+	}
+	inst.waitSnapshotStateChange(flatrpc.SnapshotStateExecute, timeout)
+	resStart := int(flatrpc.ConstMaxInputSize) + int(atomic.LoadUint32(&inst.header.OutputOffset))
+	resEnd := resStart + int(atomic.LoadUint32(&inst.header.OutputSize))
+	var res []byte
+	if resEnd <= len(inst.shmem) {
+		res = inst.shmem[resStart:resEnd:resEnd]
+	}
+	output = inst.merger.ReadAvailable()
+	return res, output, nil
+}
+
+func (inst *instance) checkpointCmd() *exec.Cmd {
+	return inst.runscCmd("checkpoint", "-image-path", inst.checkpointDir, "-leave-running", inst.name)
+}
+
+// restoreCmd uses the same bundle as the original sandbox, so the restored sandbox
+// bind-mounts the same shmem file.
+func (inst *instance) restoreCmd() *exec.Cmd {
+	cmd := inst.runscCmd("restore", "-image-path", inst.checkpointDir, "-bundle", inst.bundleDir, inst.name)
+	cmd.Stdout = inst.restoreOutput
+	cmd.Stderr = inst.restoreOutput
+	return cmd
+}
+
+// restore destroys the current sandbox and starts a new one from the checkpoint image.
+func (inst *instance) restore() error {
+	osutil.Run(time.Minute, inst.runscCmd("delete", "-force", inst.name))
+	inst.cmd.Process.Kill()
+	inst.cmd.Wait()
+
+	cmd := inst.restoreCmd()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("runsc restore failed: %w", err)
+	}
+	inst.cmd = cmd
+	return nil
+}
+
+func (inst *instance) waitSnapshotStateChange(state flatrpc.SnapshotState, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for inst.header.LoadState() == state {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	return true
+}
+
+const snapshotMountsTempl = `
+	"mounts": [
+		{
+			"destination": "%v",
+			"type": "bind",
+			"source": "%v",
+			"options": ["rbind", "rw"]
+		}
+	],`
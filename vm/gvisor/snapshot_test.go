@@ -0,0 +1,94 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package gvisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/vm/vmimpl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCommands(t *testing.T) {
+	workdir := t.TempDir()
+	imageDir := filepath.Join(workdir, "image")
+	bundleDir := filepath.Join(workdir, "bundle")
+	for _, dir := range []string{imageDir, bundleDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snap, mounts, err := snapshotCreate(workdir, imageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(snap.shmemFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(flatrpc.ConstSnapshotShmemSize), info.Size())
+	assert.FileExists(t, filepath.Join(imageDir, snapshotShmemFile))
+
+	// The input file is bind-mounted read-write, so that the executor sees host writes.
+	var config struct {
+		Mounts []struct {
+			Destination string   `json:"destination"`
+			Type        string   `json:"type"`
+			Source      string   `json:"source"`
+			Options     []string `json:"options"`
+		} `json:"mounts"`
+	}
+	data := fmt.Sprintf(configTempl, imageDir, `"CAP_KILL"`, "syz-0", -1, 100000, 100000, mounts)
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("bad config: %v\n%s", err, data)
+	}
+	assert.Len(t, config.Mounts, 1)
+	assert.Equal(t, snapshotShmemFile, config.Mounts[0].Destination)
+	assert.Equal(t, "bind", config.Mounts[0].Type)
+	assert.Equal(t, snap.shmemFile, config.Mounts[0].Source)
+	assert.Contains(t, config.Mounts[0].Options, "rw")
+
+	merger := vmimpl.NewOutputMerger(nil)
+	if err := snap.setupOutput(merger); err != nil {
+		t.Fatal(err)
+	}
+	inst := &instance{
+		cfg:       &Config{},
+		image:     "runsc",
+		rootDir:   filepath.Join(workdir, "root"),
+		imageDir:  imageDir,
+		bundleDir: bundleDir,
+		name:      "syz-0",
+		merger:    merger,
+		snapshot:  snap,
+	}
+	defer func() {
+		inst.snapshotClose()
+		merger.Wait()
+	}()
+	checkpoint := inst.checkpointCmd().Args
+	assert.Equal(t, []string{"checkpoint", "-image-path", snap.checkpointDir, "-leave-running", "syz-0"},
+		checkpoint[len(checkpoint)-5:])
+
+	// All restores use the original bundle (and thus bind-mount the same input file)
+	// and write to the same output.
+	restore0, restore1 := inst.restoreCmd(), inst.restoreCmd()
+	assert.Equal(t, []string{"restore", "-image-path", snap.checkpointDir, "-bundle", bundleDir, "syz-0"},
+		restore0.Args[len(restore0.Args)-6:])
+	assert.Equal(t, restore0.Args, restore1.Args)
+	assert.Equal(t, snap.restoreOutput, restore0.Stdout)
+	assert.Equal(t, restore0.Stdout, restore1.Stdout)
+	assert.Equal(t, restore0.Stderr, restore1.Stderr)
+}
+
+func TestDetachedCommand(t *testing.T) {
+	assert.Equal(t, "/syz-executor exec snapshot",
+		detachedCommand("nohup /syz-executor exec snapshot 1>/dev/null 2>/dev/kmsg </dev/null &"))
+	assert.Equal(t, "/syz-executor exec snapshot", detachedCommand("/syz-executor exec snapshot"))
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package local implements a VM type that runs commands as local processes on the host.
+// It's intended for fuzzing of user-space (test) targets and for testing of syzkaller itself
+// without a VM image. Commands are run in fresh user/mount/pid namespaces (if enabled),
+// but otherwise share file system and network with the host.
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+func init() {
+	vmimpl.Register("local", vmimpl.Type{
+		Ctor:       ctor,
+		Overcommit: true,
+	})
+}
+
+type Config struct {
+	Count int `json:"count"` // number of VMs to use (1 by default)
+	// Run commands in new user, mount and pid namespaces (true by default).
+	// Requires support for unprivileged user namespaces on the host.
+	Namespaces bool `json:"namespaces"`
+}
+
+type Pool struct {
+	env *vmimpl.Env
+	cfg *Config
+}
+
+type instance struct {
+	cfg     *Config
+	workdir string
+	debug   bool
+}
+
+func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
+	cfg := &Config{
+		Count:      1,
+		Namespaces: true,
+	}
+	if err := config.LoadData(env.Config, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse local vm config: %w", err)
+	}
+	if cfg.Count < 1 || cfg.Count > 128 {
+		return nil, fmt.Errorf("invalid config param count: %v, want [1, 128]", cfg.Count)
+	}
+	if env.OS != targets.TestOS && env.OS != runtime.GOOS {
+		return nil, fmt.Errorf("local VMs support only %v and %v targets, not %v",
+			targets.TestOS, runtime.GOOS, env.OS)
+	}
+	pool := &Pool{
+		cfg: cfg,
+		env: env,
+	}
+	return pool, nil
+}
+
+func (pool *Pool) Count() int {
+	return pool.cfg.Count
+}
+
+func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
+	inst := &instance{
+		cfg:     pool.cfg,
+		workdir: workdir,
+		debug:   pool.env.Debug,
+	}
+	return inst, nil
+}
+
+func (inst *instance) Copy(hostSrc string) (string, error) {
+	dst := filepath.Join(inst.workdir, filepath.Base(hostSrc))
+	if err := osutil.CopyFile(hostSrc, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+func (inst *instance) Forward(port int) (string, error) {
+	// Network is shared with the host.
+	return fmt.Sprintf("127.0.0.1:%v", port), nil
+}
+
+func (inst *instance) Run(ctx context.Context, command string) (
+	<-chan []byte, <-chan error, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("empty command")
+	}
+	cmd := osutil.Command(args[0], args[1:]...)
+	cmd.Dir = inst.workdir
+	if inst.cfg.Namespaces {
+		if err := setupNamespaces(cmd); err != nil {
+			return nil, nil, err
+		}
+	}
+	rpipe, wpipe, err := osutil.LongPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer wpipe.Close()
+	cmd.Stdout = wpipe
+	cmd.Stderr = wpipe
+	if err := cmd.Start(); err != nil {
+		rpipe.Close()
+		if inst.cfg.Namespaces {
+			err = fmt.Errorf("%w (set namespaces=false in the vm config if user namespaces"+
+				" are not supported on the host)", err)
+		}
+		return nil, nil, err
+	}
+	var tee io.Writer
+	if inst.debug {
+		tee = os.Stdout
+	}
+	merger := vmimpl.NewOutputMerger(tee)
+	merger.Add("local", rpipe)
+
+	errc := make(chan error, 1)
+	go func() {
+		waitc := make(chan error, 1)
+		stop := make(chan struct{})
+		go func() {
+			waitc <- waitAndKill(cmd, stop)
+		}()
+		select {
+		case <-ctx.Done():
+			// The process group is killed by waitAndKill before the command is reaped.
+			close(stop)
+			<-waitc
+			errc <- vmimpl.ErrTimeout
+		case err := <-waitc:
+			// Leftover processes are killed by waitAndKill, wait for the rest of the output
+			// (the pipe is closed once all processes exit).
+			select {
+			case <-merger.Err:
+			case <-time.After(10 * time.Second):
+			}
+			if err != nil {
+				err = fmt.Errorf("%v failed: %w", args[0], err)
+			}
+			errc <- err
+		}
+	}()
+	return merger.Output, errc, nil
+}
+
+func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	return nil, false
+}
+
+func (inst *instance) Close() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package local
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+func setupNamespaces(cmd *exec.Cmd) error {
+	// The new pid namespace ensures that all processes are killed when the command exits.
+	cmd.SysProcAttr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID
+	cmd.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+	cmd.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	return nil
+}
+
+// waitAndKill waits for the command to exit (or kills it once stop is closed)
+// and kills all leftover processes in its process group.
+// The group is killed before the command is reaped, otherwise its pid (and the group id)
+// could be reused by an unrelated process.
+func waitAndKill(cmd *exec.Cmd, stop <-chan struct{}) error {
+	exited := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-stop:
+			// Killing just the leader is safe since it's not reaped yet,
+			// the rest of the group is killed below once it exits.
+			cmd.Process.Kill()
+		case <-exited:
+		}
+	}()
+	for {
+		var info unix.Siginfo
+		err := unix.Waitid(unix.P_PID, cmd.Process.Pid, &info, unix.WEXITED|unix.WNOWAIT, nil)
+		if err != unix.EINTR {
+			break
+		}
+	}
+	close(exited)
+	<-stopped
+	kill(cmd)
+	return cmd.Wait()
+}
+
+func kill(cmd *exec.Cmd) {
+	// The process group is created by osutil.Command.
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	cmd.Process.Kill()
+}
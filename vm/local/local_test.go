@@ -0,0 +1,111 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package local
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	inst := createInstance(t, `{"namespaces": false}`)
+	output, err := run(context.Background(), t, inst, lookPath(t, "echo")+" hello  world")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world\n", output)
+
+	_, err = run(context.Background(), t, inst, lookPath(t, "false"))
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = run(ctx, t, inst, lookPath(t, "sleep")+" 1000")
+	assert.ErrorIs(t, err, vmimpl.ErrTimeout)
+}
+
+func TestNamespaces(t *testing.T) {
+	if runtime.GOOS != targets.Linux {
+		t.Skip("namespaces are supported only on linux")
+	}
+	inst := createInstance(t, `{}`)
+	outc, errc, err := inst.Run(context.Background(), lookPath(t, "id")+" -u")
+	if err != nil {
+		t.Skipf("user namespaces are not supported: %v", err)
+	}
+	output, err := wait(outc, errc)
+	assert.NoError(t, err)
+	assert.Equal(t, "0\n", output)
+}
+
+func TestCopy(t *testing.T) {
+	inst := createInstance(t, `{"namespaces": false}`)
+	src := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(src, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := inst.Copy(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "data", string(data))
+	assert.NotEqual(t, src, dst)
+}
+
+func createInstance(t *testing.T, cfg string) vmimpl.Instance {
+	pool, err := ctor(&vmimpl.Env{
+		OS:     targets.TestOS,
+		Config: []byte(cfg),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst, err := pool.Create(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { inst.Close() })
+	return inst
+}
+
+func run(ctx context.Context, t *testing.T, inst vmimpl.Instance, command string) (string, error) {
+	outc, errc, err := inst.Run(ctx, command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wait(outc, errc)
+}
+
+func wait(outc <-chan []byte, errc <-chan error) (string, error) {
+	err := <-errc
+	var output strings.Builder
+	for {
+		select {
+		case out := <-outc:
+			output.Write(out)
+		default:
+			return output.String(), err
+		}
+	}
+}
+
+func lookPath(t *testing.T, bin string) string {
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		t.Skipf("%v is not available: %v", bin, err)
+	}
+	return path
+}
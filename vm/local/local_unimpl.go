@@ -0,0 +1,33 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+//go:build !linux
+
+package local
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func setupNamespaces(cmd *exec.Cmd) error {
+	return fmt.Errorf("namespaces are not supported on this OS, set namespaces=false in the vm config")
+}
+
+func waitAndKill(cmd *exec.Cmd, stop <-chan struct{}) error {
+	waitc := make(chan error, 1)
+	go func() {
+		waitc <- cmd.Wait()
+	}()
+	select {
+	case err := <-waitc:
+		return err
+	case <-stop:
+		kill(cmd)
+		return <-waitc
+	}
+}
+
+func kill(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -113,6 +113,8 @@ type instance struct {
 	*snapshot
 }
 
+var _ vmimpl.Snapshotter = (*instance)(nil)
+
 type archConfig struct {
 	Qemu      string
 	QemuArgs  string
@@ -37,6 +37,7 @@ import (
 	_ "github.com/google/syzkaller/vm/gce"
 	_ "github.com/google/syzkaller/vm/gvisor"
 	_ "github.com/google/syzkaller/vm/isolated"
+	_ "github.com/google/syzkaller/vm/local"
 	_ "github.com/google/syzkaller/vm/proxyapp"
 	_ "github.com/google/syzkaller/vm/qemu"
 	_ "github.com/google/syzkaller/vm/starnix"
@@ -201,7 +202,7 @@ func (pool *Pool) Close() error {
 // SetupSnapshot must be called once before calling RunSnapshot.
 // Input is copied into the VM in an implementation defined way and is interpreted by executor.
 func (inst *Instance) SetupSnapshot(input []byte) error {
-	impl, ok := inst.impl.(snapshotter)
+	impl, ok := inst.impl.(vmimpl.Snapshotter)
 	if !ok {
 		return errors.New("this VM type does not support snapshot mode")
 	}
@@ -217,7 +218,7 @@ func (inst *Instance) SetupSnapshot(input []byte) error {
 // Result is the result provided by the executor.
 // Output is the kernel console output during execution of the input.
 func (inst *Instance) RunSnapshot(input []byte) (result, output []byte, err error) {
-	impl, ok := inst.impl.(snapshotter)
+	impl, ok := inst.impl.(vmimpl.Snapshotter)
 	if !ok {
 		return nil, nil, errors.New("this VM type does not support snapshot mode")
 	}
@@ -229,11 +230,6 @@ func (inst *Instance) RunSnapshot(input []byte) (result, output []byte, err erro
 	return impl.RunSnapshot(timeout, input)
 }
 
-type snapshotter interface {
-	SetupSnapshot([]byte) error
-	RunSnapshot(time.Duration, []byte) ([]byte, []byte, error)
-}
-
 func (inst *Instance) Copy(hostSrc string) (string, error) {
 	return inst.impl.Copy(hostSrc)
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 )
 
 type OutputMerger struct {
@@ -41,6 +42,29 @@ func (merger *OutputMerger) Wait() {
 	close(merger.Output)
 }
 
+// ReadAvailable returns output that is already available (or is being sent concurrently)
+// without waiting for new output. It's used by VMs that read output synchronously (e.g. in the snapshot mode).
+func (merger *OutputMerger) ReadAvailable() []byte {
+	var output []byte
+	// If output channel has overflown, then wait for more output from the merger goroutine.
+	wait := cap(merger.Output)
+	for {
+		select {
+		case out := <-merger.Output:
+			output = append(output, out...)
+			wait--
+		default:
+			if wait > 0 {
+				return output
+			}
+			// After the first overflow we wait after every read because the goroutine
+			// may be running and sending more output to the channel concurrently.
+			wait = 1
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
 func (merger *OutputMerger) Add(name string, r io.ReadCloser) {
 	merger.AddDecoder(name, r, nil)
 }
@@ -70,6 +70,26 @@ type Infoer interface {
 	Info() ([]byte, error)
 }
 
+// Snapshotter is an optional interface that can be implemented by Instance to support
+// the snapshot mode (see mgrconfig.Config.Snapshot). In this mode the VM is snapshotted once
+// after the executor has started, and the snapshot is restored before execution of each input.
+// Inputs and results are passed between the host and the executor in an implementation-defined
+// way (e.g. via shared memory), the executor interprets them as flatrpc.SnapshotHandshake
+// and flatrpc.SnapshotRequest messages.
+type Snapshotter interface {
+	// SetupSnapshot passes the handshake input to the executor (which is already running
+	// in the snapshot mode), waits until it's ready to execute inputs, and snapshots the VM.
+	// It's called once before any RunSnapshot calls.
+	SetupSnapshot(input []byte) error
+
+	// RunSnapshot restores the VM from the snapshot, executes the input and waits for the result
+	// for up to the timeout. It returns the result provided by the executor (empty if the executor
+	// has not provided any result) and the console output produced during the execution.
+	// Errors are returned only for infrastructure problems, kernel crashes are detected
+	// by the caller based on the output.
+	RunSnapshot(timeout time.Duration, input []byte) (result, output []byte, err error)
+}
+
 // Env contains global constant parameters for a pool of VMs.
 type Env struct {
 	// Unique name